@@ -0,0 +1,143 @@
+package dynjson
+
+import (
+	"math"
+	"sort"
+	"strconv"
+	"unicode/utf16"
+)
+
+// Canonical returns the canonical json encoding of the object as defined by RFC 8785, the JSON Canonicalization
+// Scheme (JCS). Implementations in other languages produce the same bytes for the same data, so it is suitable
+// for signing and hashing:
+//   - no whitespace
+//   - keys sorted by their UTF-16 code units
+//   - numbers formatted like ECMAScript does, e.g. 1e+21 or 0.000001
+//   - only '"', '\' and control characters escaped inside of strings
+//
+// Integers beyond 2^53 lose precision, because JCS represents all numbers as IEEE 754 double.
+// Invalid UTF-8 is replaced by U+FFFD. NaN and infinity fail with an *Error.
+func (j JsonObject) Canonical() ([]byte, error) {
+	return marshalNode(j, canonicalConfig)
+}
+
+// Canonical returns the canonical json encoding of the list. See JsonObject.Canonical.
+func (j JsonList) Canonical() ([]byte, error) {
+	return marshalNode(j, canonicalConfig)
+}
+
+// Canonical returns the canonical json encoding of the object. The order of the keys is not kept.
+// See JsonObject.Canonical.
+func (o *OrderedObject) Canonical() ([]byte, error) {
+	return marshalNode(o, canonicalConfig)
+}
+
+// Canonical returns the canonical json encoding of the value. See JsonObject.Canonical.
+func (v Value) Canonical() ([]byte, error) {
+	return marshalNode(v.data, canonicalConfig)
+}
+
+var canonicalConfig = marshalConfig{canonical: true, floatPrecision: -1}
+
+// canonicalKeys returns the keys of obj sorted by their UTF-16 code units.
+func canonicalKeys(obj JsonObject) []string {
+	keys := objectKeys(obj)
+	sort.SliceStable(keys, func(i, k int) bool {
+		return lessUTF16(keys[i], keys[k])
+	})
+	return keys
+}
+
+// lessUTF16 compares two strings by their UTF-16 code units. It differs from comparing the UTF-8 bytes, when
+// characters above U+FFFF are compared with characters between U+E000 and U+FFFF.
+func lessUTF16(a, b string) bool {
+	runesA, runesB := []rune(a), []rune(b)
+	for i := 0; i < len(runesA) && i < len(runesB); i++ {
+		if runesA[i] == runesB[i] {
+			continue
+		}
+		return utf16Order(runesA[i]) < utf16Order(runesB[i])
+	}
+	return len(runesA) < len(runesB)
+}
+
+// utf16Order combines the UTF-16 code units of r into one number, which sorts like the code units.
+func utf16Order(r rune) uint32 {
+	if r > 0xffff {
+		high, low := utf16.EncodeRune(r)
+		return uint32(high)<<16 | uint32(low)
+	}
+	return uint32(r) << 16
+}
+
+// formatES6 formats a finite number like Number.prototype.toString of ECMAScript 6 does.
+func formatES6(f float64) string {
+	if f == 0 {
+		return "0"
+	}
+
+	sign := ""
+	if f < 0 {
+		sign = "-"
+		f = -f
+	}
+
+	// The shortest representation, which reads back as f, e.g. 1.2345e+06.
+	b := strconv.AppendFloat(nil, f, 'e', -1, 64)
+	mantissa, exp := b, 0
+	for i, c := range b {
+		if c == 'e' {
+			mantissa = b[:i]
+			exp, _ = strconv.Atoi(string(b[i+1:]))
+			break
+		}
+	}
+	digits := make([]byte, 0, len(mantissa))
+	for _, c := range mantissa {
+		if c != '.' {
+			digits = append(digits, c)
+		}
+	}
+
+	// The decimal point is behind n digits.
+	k, n := len(digits), exp+1
+	var result []byte
+	switch {
+	case k <= n && n <= 21:
+		result = append(digits, zeros(n-k)...)
+	case 0 < n && n <= 21:
+		result = append(append(append(result, digits[:n]...), '.'), digits[n:]...)
+	case -6 < n && n <= 0:
+		result = append(append([]byte("0."), zeros(-n)...), digits...)
+	default:
+		result = append(result, digits[0])
+		if k > 1 {
+			result = append(append(result, '.'), digits[1:]...)
+		}
+		result = append(result, 'e')
+		if n-1 >= 0 {
+			result = append(result, '+')
+		}
+		result = strconv.AppendInt(result, int64(n-1), 10)
+	}
+
+	return sign + string(result)
+}
+
+func zeros(n int) []byte {
+	result := make([]byte, n)
+	for i := range result {
+		result[i] = '0'
+	}
+	return result
+}
+
+func (e *encoder) encodeCanonicalNumber(node interface{}, path string) error {
+	f, ok := convToFloat64(node)
+	if !ok || math.IsNaN(f) || math.IsInf(f, 0) {
+		return &Error{Kind: ErrorKindOutOfRange, Path: path, Reason: "number can't be represented in canonical json"}
+	}
+
+	e.buf.WriteString(formatES6(f))
+	return nil
+}
@@ -0,0 +1,137 @@
+package dynjson_test
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/go-schild/dynjson"
+	"github.com/stretchr/testify/assert"
+)
+
+// The test vectors are taken from RFC 8785 and its reference implementation.
+func TestJsonObject_Canonical(t *testing.T) {
+	testData := []struct {
+		name, input, expected string
+	}{
+		{
+			"values",
+			`{
+			  "numbers": [333333333.33333329, 1E30, 4.50, 2e-3, 0.000000000000000000000000001],
+			  "string": "\u20ac$\u000F\u000aA'\u0042\u0022\u005c\\\"\/",
+			  "literals": [null, true, false]
+			}`,
+			`{"literals":[null,true,false],"numbers":[333333333.3333333,1e+30,4.5,0.002,1e-27],` +
+				`"string":"€$\u000f\nA'B\"\\\\\"/"}`,
+		},
+		{
+			"sorting",
+			`{
+			  "\u20ac": "Euro Sign",
+			  "\r": "Carriage Return",
+			  "\ufb33": "Hebrew Letter Dalet With Dagesh",
+			  "1": "One",
+			  "\ud83d\ude00": "Emoji: Grinning Face",
+			  "\u0080": "Control",
+			  "\u00f6": "Latin Small Letter O With Diaeresis"
+			}`,
+			"{\"\\r\":\"Carriage Return\",\"1\":\"One\",\"\u0080\":\"Control\",\"\u00f6\":\"Latin Small Letter O With Diaeresis\"," +
+				"\"\u20ac\":\"Euro Sign\",\"\U0001f600\":\"Emoji: Grinning Face\",\"\ufb33\":\"Hebrew Letter Dalet With Dagesh\"}",
+		},
+		{
+			"structures",
+			`{"1": {"f": {"f": "hi","F": 5} ,"\n": 56.0}, "10": { }, "": "empty", "a": { }, "111": [ {"e": "yes","E": "no" } ], "A": { }}`,
+			`{"":"empty","1":{"\n":56,"f":{"F":5,"f":"hi"}},"10":{},"111":[{"E":"no","e":"yes"}],"A":{},"a":{}}`,
+		},
+		{
+			"unicode",
+			`{"Unnormalized Unicode":"A\u030a"}`,
+			"{\"Unnormalized Unicode\":\"A\u030a\"}",
+		},
+		{
+			"escapes",
+			`{"s": "<&>\u2028\b\f\u001f\u007f"}`,
+			`{"s":"<&>` + "\u2028" + `\b\f\u001f` + "\u007f" + `"}`,
+		},
+	}
+
+	for _, data := range testData {
+		j, err := dynjson.ParseObject(data.input)
+		assert.Nil(t, err, data.name)
+		result, err := j.Canonical()
+		assert.Nil(t, err, data.name)
+		assert.Equal(t, data.expected, string(result), data.name)
+
+		// The result doesn't depend on the Go types of the numbers.
+		j, err = dynjson.ParseObject(data.input, dynjson.UseNumber())
+		assert.Nil(t, err, data.name)
+		result, err = j.Canonical()
+		assert.Nil(t, err, data.name)
+		assert.Equal(t, data.expected, string(result), data.name)
+	}
+}
+
+func TestJsonList_Canonical(t *testing.T) {
+	j, _ := dynjson.ParseList(`[56, {"d": true, "10": null, "1": [ ]}]`)
+	result, err := j.Canonical()
+	assert.Nil(t, err)
+	assert.Equal(t, `[56,{"1":[],"10":null,"d":true}]`, string(result))
+
+	o, _ := dynjson.ParseOrderedObject(`{"b": 1, "a": 2}`)
+	result, err = o.Canonical()
+	assert.Nil(t, err)
+	assert.Equal(t, `{"a":2,"b":1}`, string(result))
+
+	j = dynjson.JsonList{}
+	j.Append(int64(9007199254740993), uint64(1e15), math.NaN())
+	_, err = j.Canonical()
+	assert.True(t, errors.Is(err, dynjson.ErrOutOfRange))
+	j.Set(2, 1)
+	result, err = j.Canonical()
+	assert.Nil(t, err)
+	assert.Equal(t, `[9007199254740992,1000000000000000,1]`, string(result))
+}
+
+// The number test vectors of RFC 8785, Appendix B.
+func TestValue_Canonical(t *testing.T) {
+	testData := []struct {
+		bits     uint64
+		expected string
+	}{
+		{0x0000000000000000, "0"},
+		{0x8000000000000000, "0"},
+		{0x0000000000000001, "5e-324"},
+		{0x8000000000000001, "-5e-324"},
+		{0x7fefffffffffffff, "1.7976931348623157e+308"},
+		{0xffefffffffffffff, "-1.7976931348623157e+308"},
+		{0x4340000000000000, "9007199254740992"},
+		{0xc340000000000000, "-9007199254740992"},
+		{0x4430000000000000, "295147905179352830000"},
+		{0x44b52d02c7e14af5, "9.999999999999997e+22"},
+		{0x44b52d02c7e14af6, "1e+23"},
+		{0x44b52d02c7e14af7, "1.0000000000000001e+23"},
+		{0x444b1ae4d6e2ef4e, "999999999999999700000"},
+		{0x444b1ae4d6e2ef4f, "999999999999999900000"},
+		{0x444b1ae4d6e2ef50, "1e+21"},
+		{0x3eb0c6f7a0b5ed8c, "9.999999999999997e-7"},
+		{0x3eb0c6f7a0b5ed8d, "0.000001"},
+		{0x41b3de4355555553, "333333333.3333332"},
+		{0x41b3de4355555554, "333333333.33333325"},
+		{0x41b3de4355555555, "333333333.3333333"},
+		{0x41b3de4355555556, "333333333.3333334"},
+		{0x41b3de4355555557, "333333333.33333343"},
+		{0xbecbf647612f3696, "-0.0000033333333333333333"},
+		{0x43143ff3c1cb0959, "1424953923781206.2"},
+	}
+
+	for _, data := range testData {
+		result, err := dynjson.NewValue(math.Float64frombits(data.bits)).Canonical()
+		assert.Nil(t, err, data.expected)
+		assert.Equal(t, data.expected, string(result))
+	}
+
+	for _, bits := range []uint64{0x7fffffffffffffff, 0x7ff0000000000000} {
+		_, err := dynjson.NewValue(math.Float64frombits(bits)).Canonical()
+		assert.True(t, errors.Is(err, dynjson.ErrOutOfRange))
+	}
+}
@@ -0,0 +1,299 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/go-schild/dynjson"
+	"github.com/go-schild/dynjson/schema"
+)
+
+// errNotContainer is returned, when set is used on a document which is neither an object nor a list.
+var errNotContainer = errors.New("the document is neither an object nor a list")
+
+func runGet(e *env, args []string) int {
+	fs := e.flags("get")
+	raw := fs.Bool("r", false, "print strings without quotes")
+	compact := fs.Bool("c", false, "print compact json")
+	args, ok := e.parseFlags(fs, args, 1, 2)
+	if !ok {
+		return exitUsage
+	}
+
+	doc, err := e.read(args, 1)
+	if err != nil {
+		return e.fail(exitInput, err)
+	}
+
+	var values []dynjson.Value
+	if strings.HasPrefix(args[0], "$") {
+		path, err := dynjson.CompileJsonPath(args[0])
+		if err != nil {
+			return e.fail(exitUsage, err)
+		}
+		for _, match := range path.Query(doc) {
+			values = append(values, match.Value)
+		}
+	} else {
+		v, err := doc.Pointer(args[0])
+		var pErr *dynjson.PointerError
+		if errors.As(err, &pErr) && pErr.Position < 0 {
+			return e.fail(exitUsage, err)
+		}
+		if err != nil {
+			return e.fail(exitNegative, err)
+		}
+		values = append(values, v)
+	}
+
+	if len(values) == 0 {
+		return exitNegative
+	}
+	for _, v := range values {
+		if s, ok := v.StringOk(); ok && *raw {
+			fmt.Fprintln(e.stdout, s)
+			continue
+		}
+		if code := e.write(v, *compact); code != exitOK {
+			return code
+		}
+	}
+	return exitOK
+}
+
+func runSet(e *env, args []string) int {
+	fs := e.flags("set")
+	str := fs.Bool("s", false, "use the value as string instead of parsing it as json")
+	compact := fs.Bool("c", false, "print compact json")
+	args, ok := e.parseFlags(fs, args, 2, 3)
+	if !ok {
+		return exitUsage
+	}
+
+	value := dynjson.NewValue(args[1])
+	if !*str {
+		v, err := dynjson.Parse([]byte(args[1]), dynjson.UseNumber())
+		if err != nil {
+			return e.fail(exitUsage, fmt.Errorf("invalid value (use -s for strings): %v", err))
+		}
+		value = v
+	}
+
+	doc, err := e.read(args, 2)
+	if err != nil {
+		return e.fail(exitInput, err)
+	}
+
+	if args[0] == "" {
+		return e.write(value, *compact)
+	}
+	if obj, ok := doc.ObjectOk(); ok {
+		err = obj.SetPointer(args[0], value)
+		doc = dynjson.NewValue(obj)
+	} else if list, ok := doc.ListOk(); ok {
+		err = list.SetPointer(args[0], value)
+		doc = dynjson.NewValue(list)
+	} else {
+		err = errNotContainer
+	}
+	if err != nil {
+		return e.fail(exitFailed, err)
+	}
+
+	return e.write(doc, *compact)
+}
+
+func runDelete(e *env, args []string) int {
+	fs := e.flags("delete")
+	compact := fs.Bool("c", false, "print compact json")
+	args, ok := e.parseFlags(fs, args, 1, 2)
+	if !ok {
+		return exitUsage
+	}
+
+	doc, err := e.read(args, 1)
+	if err != nil {
+		return e.fail(exitInput, err)
+	}
+
+	patch := dynjson.NewJsonList([]interface{}{dynjson.JsonObject{"op": "remove", "path": args[0]}})
+	result, err := dynjson.ApplyPatch(doc, patch)
+	if err != nil {
+		return e.fail(exitFailed, err)
+	}
+
+	return e.write(result, *compact)
+}
+
+func runMerge(e *env, args []string) int {
+	fs := e.flags("merge")
+	deep := fs.Bool("deep", false, "merge deeply instead of applying a merge patch, null values don't remove fields")
+	arrays := fs.String("arrays", "replace", "how -deep merges lists: replace, append or index")
+	compact := fs.Bool("c", false, "print compact json")
+	args, ok := e.parseFlags(fs, args, 2, 2)
+	if !ok {
+		return exitUsage
+	}
+
+	strategies := map[string]dynjson.ArrayStrategy{
+		"replace": dynjson.ArrayReplace,
+		"append":  dynjson.ArrayAppend,
+		"index":   dynjson.ArrayMergeByIndex,
+	}
+	strategy, ok := strategies[*arrays]
+	if !ok {
+		return e.fail(exitUsage, fmt.Errorf("unknown array strategy %q", *arrays))
+	}
+
+	doc, err := e.read(args, 0)
+	if err != nil {
+		return e.fail(exitInput, err)
+	}
+	patch, err := e.read(args, 1)
+	if err != nil {
+		return e.fail(exitInput, err)
+	}
+
+	if !*deep {
+		return e.write(dynjson.MergePatch(doc, patch), *compact)
+	}
+
+	dst, ok := doc.ObjectOk()
+	src, srcOk := patch.ObjectOk()
+	if !ok || !srcOk {
+		return e.fail(exitFailed, errors.New("-deep requires two objects"))
+	}
+	if err := dst.DeepMerge(src, dynjson.WithArrayStrategy(strategy)); err != nil {
+		return e.fail(exitFailed, err)
+	}
+	return e.write(dst, *compact)
+}
+
+func runDiff(e *env, args []string) int {
+	fs := e.flags("diff")
+	compact := fs.Bool("c", false, "print compact json")
+	args, ok := e.parseFlags(fs, args, 2, 2)
+	if !ok {
+		return exitUsage
+	}
+
+	a, err := e.read(args, 0)
+	if err != nil {
+		return e.fail(exitInput, err)
+	}
+	b, err := e.read(args, 1)
+	if err != nil {
+		return e.fail(exitInput, err)
+	}
+
+	patch := dynjson.Diff(a, b)
+	if code := e.write(patch, *compact); code != exitOK {
+		return code
+	}
+	if patch.Len() > 0 {
+		return exitNegative
+	}
+	return exitOK
+}
+
+func runPatch(e *env, args []string) int {
+	fs := e.flags("patch")
+	compact := fs.Bool("c", false, "print compact json")
+	args, ok := e.parseFlags(fs, args, 2, 2)
+	if !ok {
+		return exitUsage
+	}
+
+	doc, err := e.read(args, 0)
+	if err != nil {
+		return e.fail(exitInput, err)
+	}
+	patch, err := e.read(args, 1)
+	if err != nil {
+		return e.fail(exitInput, err)
+	}
+	operations, ok := patch.ListOk()
+	if !ok {
+		return e.fail(exitInput, errors.New("the patch must be a list of operations"))
+	}
+
+	result, err := dynjson.ApplyPatch(doc, operations)
+	if err != nil {
+		return e.fail(exitFailed, err)
+	}
+	return e.write(result, *compact)
+}
+
+func runFmt(e *env, args []string) int {
+	fs := e.flags("fmt")
+	compact := fs.Bool("compact", false, "remove all whitespace")
+	canonical := fs.Bool("canonical", false, "write canonical json as defined by RFC 8785")
+	indent := fs.Int("indent", 2, "number of spaces used for indentation")
+	args, ok := e.parseFlags(fs, args, 0, 1)
+	if !ok {
+		return exitUsage
+	}
+	if *indent < 0 {
+		return e.fail(exitUsage, fmt.Errorf("invalid indentation %d", *indent))
+	}
+
+	doc, err := e.read(args, 0)
+	if err != nil {
+		return e.fail(exitInput, err)
+	}
+
+	switch {
+	case *canonical:
+		data, err := doc.Canonical()
+		if err != nil {
+			return e.fail(exitFailed, err)
+		}
+		if _, err := e.stdout.Write(append(data, '\n')); err != nil {
+			return e.fail(exitFailed, err)
+		}
+		return exitOK
+	case *compact:
+		return e.writeJSON(doc)
+	}
+	return e.writeJSON(doc, dynjson.Indent(strings.Repeat(" ", *indent)))
+}
+
+func runValidate(e *env, args []string) int {
+	fs := e.flags("validate")
+	args, ok := e.parseFlags(fs, args, 1, 2)
+	if !ok {
+		return exitUsage
+	}
+
+	schemaDoc, err := e.read(args, 0)
+	if err != nil {
+		return e.fail(exitInput, err)
+	}
+	schemaObj, ok := schemaDoc.ObjectOk()
+	if !ok {
+		return e.fail(exitInput, errors.New("the schema must be an object"))
+	}
+	s, err := schema.Compile(schemaObj)
+	if err != nil {
+		return e.fail(exitInput, err)
+	}
+
+	doc, err := e.read(args, 1)
+	if err != nil {
+		return e.fail(exitInput, err)
+	}
+
+	err = s.Validate(doc)
+	var vErr *schema.ValidationError
+	if errors.As(err, &vErr) {
+		for _, v := range vErr.Violations {
+			fmt.Fprintln(e.stdout, v)
+		}
+		return exitNegative
+	}
+	if err != nil {
+		return e.fail(exitFailed, err)
+	}
+	return exitOK
+}
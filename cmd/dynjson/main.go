@@ -0,0 +1,176 @@
+// Command dynjson queries and transforms json documents.
+//
+// Usage:
+//
+//	dynjson <command> [flags] [arguments]
+//
+// Documents are read from files or, when the file is "-" or missing, from stdin. Results are written to stdout.
+// Run "dynjson help" for a list of commands.
+//
+// Exit codes:
+//
+//	0  success
+//	1  negative result: get found nothing, validate found violations, diff found differences
+//	2  invalid usage
+//	3  invalid input, e.g. a missing file or malformed json
+//	4  the operation failed, e.g. a patch couldn't be applied
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+
+	"github.com/go-schild/dynjson"
+)
+
+const (
+	exitOK = iota
+	exitNegative
+	exitUsage
+	exitInput
+	exitFailed
+)
+
+// command is a subcommand like "get".
+type command struct {
+	args        string
+	description string
+	run         func(env *env, args []string) int
+}
+
+// commands is filled by init, because the commands refer to it.
+var commands map[string]command
+
+func init() {
+	commands = map[string]command{
+		"get":      {"[-r] [-c] <pointer|jsonpath> [file]", "print the value at a JSON Pointer or all matches of a JSONPath expression", runGet},
+		"set":      {"[-s] [-c] <pointer> <value> [file]", "set the value at a JSON Pointer, the value is json unless -s is given", runSet},
+		"delete":   {"[-c] <pointer> [file]", "delete the value at a JSON Pointer", runDelete},
+		"merge":    {"[-deep] [-arrays replace|append|index] [-c] <file> <patch>", "apply a JSON Merge Patch (RFC 7386) or merge deeply", runMerge},
+		"diff":     {"[-c] <file1> <file2>", "print a JSON Patch (RFC 6902), which turns file1 into file2", runDiff},
+		"patch":    {"[-c] <file> <patch>", "apply a JSON Patch (RFC 6902)", runPatch},
+		"fmt":      {"[-compact|-canonical] [-indent n] [file]", "format a document", runFmt},
+		"validate": {"<schema> [file]", "validate a document against a JSON Schema", runValidate},
+	}
+}
+
+// env holds the streams of a run, so the commands can be tested.
+type env struct {
+	stdin  io.Reader
+	stdout io.Writer
+	stderr io.Writer
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	e := &env{stdin: stdin, stdout: stdout, stderr: stderr}
+
+	if len(args) == 0 {
+		e.usage()
+		return exitUsage
+	}
+	if args[0] == "help" || args[0] == "-h" || args[0] == "--help" {
+		e.stderr = stdout
+		e.usage()
+		return exitOK
+	}
+
+	cmd, ok := commands[args[0]]
+	if !ok {
+		fmt.Fprintf(stderr, "dynjson: unknown command %q\n", args[0])
+		e.usage()
+		return exitUsage
+	}
+	return cmd.run(e, args[1:])
+}
+
+func (e *env) usage() {
+	fmt.Fprintln(e.stderr, "usage: dynjson <command> [flags] [arguments]")
+	fmt.Fprintln(e.stderr)
+	fmt.Fprintln(e.stderr, "commands:")
+
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(e.stderr, "  %-8s %s\n", name, commands[name].args)
+		fmt.Fprintf(e.stderr, "           %s\n", commands[name].description)
+	}
+}
+
+// flags creates the flag set of a command. Errors are written to stderr.
+func (e *env) flags(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(e.stderr)
+	fs.Usage = func() {
+		fmt.Fprintf(e.stderr, "usage: dynjson %s %s\n", name, commands[name].args)
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+// parseFlags parses the flags and checks the number of remaining arguments.
+func (e *env) parseFlags(fs *flag.FlagSet, args []string, min, max int) ([]string, bool) {
+	if err := fs.Parse(args); err != nil {
+		return nil, false
+	}
+	if fs.NArg() < min || fs.NArg() > max {
+		fs.Usage()
+		return nil, false
+	}
+	return fs.Args(), true
+}
+
+// read parses the document in the file. A missing file name or "-" reads stdin.
+func (e *env) read(args []string, i int) (dynjson.Value, error) {
+	name := "-"
+	if i < len(args) {
+		name = args[i]
+	}
+
+	r := e.stdin
+	if name != "-" {
+		f, err := os.Open(name)
+		if err != nil {
+			return dynjson.Value{}, err
+		}
+		defer f.Close()
+		r = f
+	}
+
+	v, err := dynjson.ParseReader(r, dynjson.UseNumber(), dynjson.DisallowTrailingData())
+	if err != nil {
+		return dynjson.Value{}, fmt.Errorf("%s: %v", name, err)
+	}
+	return v, nil
+}
+
+// fail prints the error and returns the exit code.
+func (e *env) fail(code int, err error) int {
+	fmt.Fprintf(e.stderr, "dynjson: %v\n", err)
+	return code
+}
+
+// write prints a value, indented by two spaces unless compact is set.
+func (e *env) write(v interface{}, compact bool) int {
+	if compact {
+		return e.writeJSON(v)
+	}
+	return e.writeJSON(v, dynjson.Indent("  "))
+}
+
+func (e *env) writeJSON(v interface{}, opts ...dynjson.MarshalOption) int {
+	opts = append(opts, dynjson.TrailingNewline())
+	if _, err := dynjson.NewValue(v).WriteJSON(e.stdout, opts...); err != nil {
+		return e.fail(exitFailed, err)
+	}
+	return exitOK
+}
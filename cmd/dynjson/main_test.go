@@ -0,0 +1,173 @@
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func runTest(t *testing.T, stdin string, args ...string) (int, string, string) {
+	var stdout, stderr bytes.Buffer
+	code := run(args, strings.NewReader(stdin), &stdout, &stderr)
+	return code, stdout.String(), stderr.String()
+}
+
+func writeFile(t *testing.T, dir, name, content string) string {
+	path := filepath.Join(dir, name)
+	assert.Nil(t, ioutil.WriteFile(path, []byte(content), 0644))
+	return path
+}
+
+const testDoc = `{"name": "Bob", "tags": ["a", "b"], "n": 9007199254740993}`
+
+func TestRun_Usage(t *testing.T) {
+	code, _, stderr := runTest(t, "")
+	assert.Equal(t, exitUsage, code)
+	assert.Contains(t, stderr, "validate")
+
+	code, stdout, _ := runTest(t, "", "help")
+	assert.Equal(t, exitOK, code)
+	assert.Contains(t, stdout, "usage: dynjson")
+
+	code, _, _ = runTest(t, "", "unknown")
+	assert.Equal(t, exitUsage, code)
+
+	code, _, _ = runTest(t, "", "get")
+	assert.Equal(t, exitUsage, code)
+}
+
+func TestRun_Get(t *testing.T) {
+	code, stdout, _ := runTest(t, testDoc, "get", "/name")
+	assert.Equal(t, exitOK, code)
+	assert.Equal(t, "\"Bob\"\n", stdout)
+
+	_, stdout, _ = runTest(t, testDoc, "get", "-r", "/name")
+	assert.Equal(t, "Bob\n", stdout)
+
+	_, stdout, _ = runTest(t, testDoc, "get", "-c", "/tags")
+	assert.Equal(t, "[\"a\",\"b\"]\n", stdout)
+
+	_, stdout, _ = runTest(t, testDoc, "get", "/n")
+	assert.Equal(t, "9007199254740993\n", stdout)
+
+	_, stdout, _ = runTest(t, testDoc, "get", "-r", "$.tags[*]")
+	assert.Equal(t, "a\nb\n", stdout)
+
+	code, _, _ = runTest(t, testDoc, "get", "/missing")
+	assert.Equal(t, exitNegative, code)
+	code, _, _ = runTest(t, testDoc, "get", "$.missing")
+	assert.Equal(t, exitNegative, code)
+	code, _, _ = runTest(t, testDoc, "get", "name")
+	assert.Equal(t, exitUsage, code)
+	code, _, _ = runTest(t, testDoc, "get", "$[")
+	assert.Equal(t, exitUsage, code)
+
+	code, _, stderr := runTest(t, `{"a": `, "get", "/a")
+	assert.Equal(t, exitInput, code)
+	assert.Contains(t, stderr, "syntax error")
+
+	code, _, _ = runTest(t, "", "get", "/a", "does-not-exist.json")
+	assert.Equal(t, exitInput, code)
+}
+
+func TestRun_SetDelete(t *testing.T) {
+	code, stdout, _ := runTest(t, testDoc, "set", "-c", "/address/city", `"Berlin"`)
+	assert.Equal(t, exitOK, code)
+	assert.Equal(t, `{"address":{"city":"Berlin"},"n":9007199254740993,"name":"Bob","tags":["a","b"]}`+"\n", stdout)
+
+	_, stdout, _ = runTest(t, `[1]`, "set", "-c", "-s", "/-", "x")
+	assert.Equal(t, "[1,\"x\"]\n", stdout)
+
+	code, _, _ = runTest(t, testDoc, "set", "/a", "not json")
+	assert.Equal(t, exitUsage, code)
+
+	code, _, _ = runTest(t, `"abc"`, "set", "/a", "1")
+	assert.Equal(t, exitFailed, code)
+
+	code, stdout, _ = runTest(t, testDoc, "delete", "-c", "/tags/0")
+	assert.Equal(t, exitOK, code)
+	assert.Equal(t, `{"n":9007199254740993,"name":"Bob","tags":["b"]}`+"\n", stdout)
+
+	code, _, _ = runTest(t, testDoc, "delete", "/missing")
+	assert.Equal(t, exitFailed, code)
+}
+
+func TestRun_MergeDiffPatch(t *testing.T) {
+	dir, err := ioutil.TempDir("", "dynjson")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	a := writeFile(t, dir, "a.json", `{"a": 1, "b": {"c": 2}, "l": [1]}`)
+	b := writeFile(t, dir, "b.json", `{"a": null, "b": {"d": 3}, "l": [2]}`)
+
+	code, stdout, _ := runTest(t, "", "merge", "-c", a, b)
+	assert.Equal(t, exitOK, code)
+	assert.Equal(t, `{"b":{"c":2,"d":3},"l":[2]}`+"\n", stdout)
+
+	_, stdout, _ = runTest(t, "", "merge", "-c", "-deep", "-arrays", "append", a, b)
+	assert.Equal(t, `{"a":null,"b":{"c":2,"d":3},"l":[1,2]}`+"\n", stdout)
+
+	code, _, _ = runTest(t, "", "merge", "-deep", "-arrays", "zip", a, b)
+	assert.Equal(t, exitUsage, code)
+
+	code, stdout, _ = runTest(t, "", "diff", "-c", a, b)
+	assert.Equal(t, exitNegative, code)
+	p := writeFile(t, dir, "patch.json", stdout)
+
+	code, _, _ = runTest(t, "", "diff", a, a)
+	assert.Equal(t, exitOK, code)
+
+	code, stdout, _ = runTest(t, "", "patch", "-c", a, p)
+	assert.Equal(t, exitOK, code)
+	assert.Equal(t, `{"a":null,"b":{"d":3},"l":[2]}`+"\n", stdout)
+
+	bad := writeFile(t, dir, "bad.json", `[{"op": "remove", "path": "/x"}]`)
+	code, _, _ = runTest(t, "", "patch", a, bad)
+	assert.Equal(t, exitFailed, code)
+}
+
+func TestRun_Fmt(t *testing.T) {
+	code, stdout, _ := runTest(t, `{"b": [1, 2], "a": 1.50}`, "fmt")
+	assert.Equal(t, exitOK, code)
+	assert.Equal(t, "{\n  \"a\": 1.50,\n  \"b\": [\n    1,\n    2\n  ]\n}\n", stdout)
+
+	_, stdout, _ = runTest(t, `{"b": [1, 2], "a": 1}`, "fmt", "-compact")
+	assert.Equal(t, "{\"a\":1,\"b\":[1,2]}\n", stdout)
+
+	_, stdout, _ = runTest(t, `[1]`, "fmt", "-indent", "4")
+	assert.Equal(t, "[\n    1\n]\n", stdout)
+
+	code, _, stderr := runTest(t, `[1]`, "fmt", "-indent", "-1")
+	assert.Equal(t, exitUsage, code)
+	assert.Contains(t, stderr, "invalid indentation -1")
+
+	_, stdout, _ = runTest(t, `{"b": [1e21, 2.50], "a": "<\u00e4>"}`, "fmt", "-canonical")
+	assert.Equal(t, "{\"a\":\"<\u00e4>\",\"b\":[1e+21,2.5]}\n", stdout)
+
+	code, _, _ = runTest(t, `{} {}`, "fmt")
+	assert.Equal(t, exitInput, code)
+}
+
+func TestRun_Validate(t *testing.T) {
+	dir, err := ioutil.TempDir("", "dynjson")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	s := writeFile(t, dir, "schema.json", `{"type": "object", "required": ["name"], "properties": {"name": {"type": "string"}}}`)
+
+	code, _, _ := runTest(t, `{"name": "Bob"}`, "validate", s)
+	assert.Equal(t, exitOK, code)
+
+	code, stdout, _ := runTest(t, `{"name": 1}`, "validate", s, "-")
+	assert.Equal(t, exitNegative, code)
+	assert.Equal(t, "\"/name\": expected string, got integer (#/properties/name/type)\n", stdout)
+
+	invalid := writeFile(t, dir, "invalid.json", `{"type": "text"}`)
+	code, _, _ = runTest(t, `{}`, "validate", invalid)
+	assert.Equal(t, exitInput, code)
+}
@@ -0,0 +1,371 @@
+package dynjson
+
+//go:generate go run gen_coercion.go
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// CoercionOption changes the rules of the lenient mode, see Lenient.
+type CoercionOption func(*coercionConfig)
+
+type coercionConfig struct {
+	allowLossy bool
+}
+
+func newCoercionConfig(opts []CoercionOption) coercionConfig {
+	config := coercionConfig{}
+	for _, opt := range opts {
+		opt(&config)
+	}
+	return config
+}
+
+// AllowLossy accepts coercions which lose information, e.g. "3.7" to the int 3 or 2 to true.
+// By default, they fail and the Err getters return an error of the kind ErrorKindLossy.
+func AllowLossy() CoercionOption {
+	return func(config *coercionConfig) {
+		config.allowLossy = true
+	}
+}
+
+// LenientValue reads a value in lenient mode. Its getters convert between strings, numbers and bools:
+//
+//   - Strings are converted into numbers, when they contain a decimal or exponential number like "42" or "1e3"
+//     (surrounding spaces are ignored). They are converted into bools by strconv.ParseBool, e.g. "true", "1" or "F".
+//   - Numbers are converted into strings as encoding/json would write them. 0 and 1 are converted into false and true,
+//     other numbers into true, which is lossy.
+//   - Bools are converted into the strings "true" and "false" and into the numbers 1 and 0.
+//   - Converting a number with fractional digits into an integer truncates it, which is lossy.
+//     Numbers which don't fit into the integer type are never converted.
+//   - Converting an integer into a float, which can't represent it exactly, e.g. 2^53+1 into a float64, is lossy.
+//     Numbers which don't fit into a float32 are never converted.
+//   - ListOk wraps every value, which is not a list, into a list with a single element. Null results in an empty list.
+//
+// Null and missing values are never converted into strings, numbers or bools.
+type LenientValue struct {
+	value  Value
+	config coercionConfig
+}
+
+// Lenient returns the value in lenient mode, see LenientValue.
+func (v Value) Lenient(opts ...CoercionOption) LenientValue {
+	return LenientValue{value: v, config: newCoercionConfig(opts)}
+}
+
+// Lenient returns the item in lenient mode, see LenientValue.
+func (j JsonListItem) Lenient(opts ...CoercionOption) LenientValue {
+	return j.Value().Lenient(opts...)
+}
+
+// Value returns the wrapped value.
+func (v LenientValue) Value() Value {
+	return v.value
+}
+
+// LenientObject reads the fields of a json object in lenient mode, see LenientValue.
+// Nested objects and lists returned by its getters are read in lenient mode, too.
+// The methods of the embedded JsonObject, which are not overridden, still use the strict rules.
+type LenientObject struct {
+	JsonObject
+	config coercionConfig
+}
+
+// Lenient returns the json object in lenient mode, see LenientObject.
+func (j JsonObject) Lenient(opts ...CoercionOption) LenientObject {
+	return LenientObject{JsonObject: j, config: newCoercionConfig(opts)}
+}
+
+// Value returns the field in lenient mode.
+func (j LenientObject) Value(field string) LenientValue {
+	return LenientValue{value: j.JsonObject.Value(field), config: j.config}
+}
+
+// LenientList reads the items of a json list in lenient mode, see LenientValue.
+// Nested objects and lists returned by its getters are read in lenient mode, too.
+// The methods of the embedded JsonList, which are not overridden, still use the strict rules.
+type LenientList struct {
+	JsonList
+	config coercionConfig
+}
+
+// Lenient returns the json list in lenient mode, see LenientList.
+func (j JsonList) Lenient(opts ...CoercionOption) LenientList {
+	return LenientList{JsonList: j, config: newCoercionConfig(opts)}
+}
+
+// Value returns the item at the index in lenient mode. When the index is out of range, the value is missing.
+func (j LenientList) Value(index int) LenientValue {
+	return LenientValue{value: j.JsonList.Value(index), config: j.config}
+}
+
+// check turns the result of a coercion into an error.
+func (v LenientValue) check(goType string, expected Kind, lossy, ok bool) error {
+	if !v.value.exists {
+		return missingError()
+	}
+	if !ok {
+		if expected == KindNumber && kindOf(v.value.data) == KindNumber {
+			return rangeError(fmt.Sprintf("%s doesn't fit into %s", v.value.ToString(), goType))
+		}
+		return typeError(expected, v.value.data)
+	}
+	if lossy && !v.config.allowLossy {
+		return &Error{Kind: ErrorKindLossy, Reason: fmt.Sprintf("converting %s into %s loses information", v.value.ToString(), goType)}
+	}
+	return nil
+}
+
+func (v LenientValue) ObjectErr() (LenientObject, error) {
+	val, ok := v.value.ObjectOk()
+	if !ok {
+		return LenientObject{}, v.check("object", KindObject, false, false)
+	}
+	return LenientObject{JsonObject: val, config: v.config}, nil
+}
+
+func (v LenientValue) ObjectOk() (LenientObject, bool) {
+	val, err := v.ObjectErr()
+	return val, err == nil
+}
+
+func (v LenientValue) Object() LenientObject {
+	val, _ := v.ObjectOk()
+	return val
+}
+
+func (v LenientValue) ListErr() (LenientList, error) {
+	if !v.value.exists {
+		return LenientList{}, missingError()
+	}
+
+	var list JsonList
+	if v.value.data == nil {
+		list = NewJsonList(nil)
+	} else if val, ok := v.value.ListOk(); ok {
+		list = val
+	} else {
+		list = NewJsonList([]interface{}{v.value.data})
+	}
+	return LenientList{JsonList: list, config: v.config}, nil
+}
+
+func (v LenientValue) ListOk() (LenientList, bool) {
+	val, err := v.ListErr()
+	return val, err == nil
+}
+
+func (v LenientValue) List() LenientList {
+	val, _ := v.ListOk()
+	return val
+}
+
+func (j LenientObject) ObjectErr(field string) (LenientObject, error) {
+	val, err := j.Value(field).ObjectErr()
+	return val, withPath(err, FormatPointer(field))
+}
+
+func (j LenientObject) ObjectOk(field string) (LenientObject, bool) {
+	return j.Value(field).ObjectOk()
+}
+
+func (j LenientObject) Object(field string) LenientObject {
+	return j.Value(field).Object()
+}
+
+func (j LenientObject) ListErr(field string) (LenientList, error) {
+	val, err := j.Value(field).ListErr()
+	return val, withPath(err, FormatPointer(field))
+}
+
+func (j LenientObject) ListOk(field string) (LenientList, bool) {
+	return j.Value(field).ListOk()
+}
+
+func (j LenientObject) List(field string) LenientList {
+	return j.Value(field).List()
+}
+
+func (j LenientList) ObjectErr(index int) (LenientObject, error) {
+	val, err := j.Value(index).ObjectErr()
+	return val, withPath(err, FormatPointer(strconv.Itoa(index)))
+}
+
+func (j LenientList) ObjectOk(index int) (LenientObject, bool) {
+	return j.Value(index).ObjectOk()
+}
+
+func (j LenientList) Object(index int) LenientObject {
+	return j.Value(index).Object()
+}
+
+func (j LenientList) ListErr(index int) (LenientList, error) {
+	val, err := j.Value(index).ListErr()
+	return val, withPath(err, FormatPointer(strconv.Itoa(index)))
+}
+
+func (j LenientList) ListOk(index int) (LenientList, bool) {
+	return j.Value(index).ListOk()
+}
+
+func (j LenientList) List(index int) LenientList {
+	return j.Value(index).List()
+}
+
+// coerceFloat64 converts strings, numbers and bools into a float64. Integers, which a float64 can't represent
+// exactly, e.g. 2^53+1, are lossy.
+func coerceFloat64(data interface{}) (float64, bool, bool) {
+	data = normalize(data)
+	if val, ok := convToFloat64(data); ok {
+		return val, !exactFloat(data, val), true
+	}
+
+	switch d := data.(type) {
+	case string:
+		val, err := strconv.ParseFloat(strings.TrimSpace(d), 64)
+		if err != nil || math.IsNaN(val) || math.IsInf(val, 0) {
+			return 0, false, false
+		}
+		return val, !exactFloat(d, val), true
+	case bool:
+		if d {
+			return 1, false, true
+		}
+		return 0, false, true
+	}
+
+	return 0, false, false
+}
+
+// coerceInt64 converts strings, numbers and bools into an int64. Fractional digits are truncated, which is lossy.
+func coerceInt64(data interface{}) (int64, bool, bool) {
+	if val, ok := convToInt64(data); ok {
+		return val, false, true
+	}
+	if s, ok := data.(string); ok {
+		if val, err := strconv.ParseInt(strings.TrimSpace(s), 10, 64); err == nil {
+			return val, false, true
+		}
+	}
+
+	f, _, ok := coerceFloat64(data)
+	if !ok || f < -(1<<63) || f >= 1<<63 {
+		return 0, false, false
+	}
+	return int64(f), f != math.Trunc(f), true
+}
+
+// coerceUint64 converts strings, numbers and bools into an uint64. Fractional digits are truncated, which is lossy.
+func coerceUint64(data interface{}) (uint64, bool, bool) {
+	if val, ok := convToUint64(data); ok {
+		return val, false, true
+	}
+	if s, ok := data.(string); ok {
+		if val, err := strconv.ParseUint(strings.TrimSpace(s), 10, 64); err == nil {
+			return val, false, true
+		}
+	}
+
+	f, _, ok := coerceFloat64(data)
+	if !ok || f <= -1 || f >= 1<<64 {
+		return 0, false, false
+	}
+	return uint64(f), f != math.Trunc(f), true
+}
+
+func coerceInt32(data interface{}) (int32, bool, bool) {
+	val, lossy, ok := coerceInt64(data)
+	if !ok || val < math.MinInt32 || val > math.MaxInt32 {
+		return 0, false, false
+	}
+	return int32(val), lossy, true
+}
+
+func coerceInt(data interface{}) (int, bool, bool) {
+	val, lossy, ok := coerceInt64(data)
+	if !ok || int64(int(val)) != val {
+		return 0, false, false
+	}
+	return int(val), lossy, true
+}
+
+// coerceFloat32 converts strings, numbers and bools into a float32. Numbers beyond the range of float32 are never
+// converted, integers which a float32 can't represent exactly are lossy.
+func coerceFloat32(data interface{}) (float32, bool, bool) {
+	val, lossy, ok := coerceFloat64(data)
+	f := float32(val)
+	if !ok || math.IsInf(float64(f), 0) {
+		return 0, false, false
+	}
+	if val == math.Trunc(val) && float64(f) != val {
+		lossy = true
+	}
+	return f, lossy, true
+}
+
+// exactFloat returns false, when data is an integer, which isn't exactly f. Data can be an int64, an uint64,
+// a json.Number or a string. Other numbers, e.g. 0.1, can't be represented exactly anyway and are regarded as exact.
+func exactFloat(data interface{}, f float64) bool {
+	var s string
+	switch d := data.(type) {
+	case int64:
+		return f < 1<<63 && int64(f) == d
+	case uint64:
+		return f < 1<<64 && uint64(f) == d
+	case json.Number:
+		s = string(d)
+	case string:
+		s = strings.TrimSpace(d)
+	default:
+		return true
+	}
+
+	if i, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return exactFloat(i, f)
+	}
+	if u, err := strconv.ParseUint(s, 10, 64); err == nil {
+		return exactFloat(u, f)
+	}
+	return true
+}
+
+// coerceString converts strings, numbers and bools into a string.
+func coerceString(data interface{}) (string, bool, bool) {
+	switch d := normalize(data).(type) {
+	case string:
+		return d, false, true
+	case json.Number:
+		return string(d), false, true
+	case int64:
+		return strconv.FormatInt(d, 10), false, true
+	case uint64:
+		return strconv.FormatUint(d, 10), false, true
+	case float64:
+		b, err := json.Marshal(d)
+		return string(b), false, err == nil
+	case bool:
+		return strconv.FormatBool(d), false, true
+	}
+
+	return "", false, false
+}
+
+// coerceBool converts strings, numbers and bools into a bool. Numbers other than 0 and 1 are lossy.
+func coerceBool(data interface{}) (bool, bool, bool) {
+	switch d := normalize(data).(type) {
+	case bool:
+		return d, false, true
+	case string:
+		val, err := strconv.ParseBool(strings.TrimSpace(d))
+		return val, false, err == nil
+	}
+
+	if f, ok := convToFloat64(data); ok {
+		return f != 0, f != 0 && f != 1, true
+	}
+	return false, false, false
+}
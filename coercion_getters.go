@@ -0,0 +1,485 @@
+// Code generated by gen_coercion.go; DO NOT EDIT.
+
+package dynjson
+
+import "strconv"
+
+func (v LenientValue) StringErr() (string, error) {
+	val, lossy, ok := coerceString(v.value.data)
+	if err := v.check("string", KindString, lossy, ok); err != nil {
+		return "", err
+	}
+	return val, nil
+}
+
+func (v LenientValue) StringOk() (string, bool) {
+	val, err := v.StringErr()
+	return val, err == nil
+}
+
+func (v LenientValue) StringDefault(def string) string {
+	val, ok := v.StringOk()
+	if ok {
+		return val
+	}
+	return def
+}
+
+func (v LenientValue) String() string {
+	val, _ := v.StringOk()
+	return val
+}
+
+func (j LenientObject) StringErr(field string) (string, error) {
+	val, err := j.Value(field).StringErr()
+	return val, withPath(err, FormatPointer(field))
+}
+
+func (j LenientObject) StringOk(field string) (string, bool) {
+	return j.Value(field).StringOk()
+}
+
+func (j LenientObject) StringDefault(field string, def string) string {
+	return j.Value(field).StringDefault(def)
+}
+
+func (j LenientObject) String(field string) string {
+	return j.Value(field).String()
+}
+
+func (j LenientList) StringErr(index int) (string, error) {
+	val, err := j.Value(index).StringErr()
+	return val, withPath(err, FormatPointer(strconv.Itoa(index)))
+}
+
+func (j LenientList) StringOk(index int) (string, bool) {
+	return j.Value(index).StringOk()
+}
+
+func (j LenientList) StringDefault(index int, def string) string {
+	return j.Value(index).StringDefault(def)
+}
+
+func (j LenientList) String(index int) string {
+	return j.Value(index).String()
+}
+
+func (v LenientValue) Float64Err() (float64, error) {
+	val, lossy, ok := coerceFloat64(v.value.data)
+	if err := v.check("float64", KindNumber, lossy, ok); err != nil {
+		return 0, err
+	}
+	return val, nil
+}
+
+func (v LenientValue) Float64Ok() (float64, bool) {
+	val, err := v.Float64Err()
+	return val, err == nil
+}
+
+func (v LenientValue) Float64Default(def float64) float64 {
+	val, ok := v.Float64Ok()
+	if ok {
+		return val
+	}
+	return def
+}
+
+func (v LenientValue) Float64() float64 {
+	val, _ := v.Float64Ok()
+	return val
+}
+
+func (j LenientObject) Float64Err(field string) (float64, error) {
+	val, err := j.Value(field).Float64Err()
+	return val, withPath(err, FormatPointer(field))
+}
+
+func (j LenientObject) Float64Ok(field string) (float64, bool) {
+	return j.Value(field).Float64Ok()
+}
+
+func (j LenientObject) Float64Default(field string, def float64) float64 {
+	return j.Value(field).Float64Default(def)
+}
+
+func (j LenientObject) Float64(field string) float64 {
+	return j.Value(field).Float64()
+}
+
+func (j LenientList) Float64Err(index int) (float64, error) {
+	val, err := j.Value(index).Float64Err()
+	return val, withPath(err, FormatPointer(strconv.Itoa(index)))
+}
+
+func (j LenientList) Float64Ok(index int) (float64, bool) {
+	return j.Value(index).Float64Ok()
+}
+
+func (j LenientList) Float64Default(index int, def float64) float64 {
+	return j.Value(index).Float64Default(def)
+}
+
+func (j LenientList) Float64(index int) float64 {
+	return j.Value(index).Float64()
+}
+
+func (v LenientValue) Float32Err() (float32, error) {
+	val, lossy, ok := coerceFloat32(v.value.data)
+	if err := v.check("float32", KindNumber, lossy, ok); err != nil {
+		return 0, err
+	}
+	return val, nil
+}
+
+func (v LenientValue) Float32Ok() (float32, bool) {
+	val, err := v.Float32Err()
+	return val, err == nil
+}
+
+func (v LenientValue) Float32Default(def float32) float32 {
+	val, ok := v.Float32Ok()
+	if ok {
+		return val
+	}
+	return def
+}
+
+func (v LenientValue) Float32() float32 {
+	val, _ := v.Float32Ok()
+	return val
+}
+
+func (j LenientObject) Float32Err(field string) (float32, error) {
+	val, err := j.Value(field).Float32Err()
+	return val, withPath(err, FormatPointer(field))
+}
+
+func (j LenientObject) Float32Ok(field string) (float32, bool) {
+	return j.Value(field).Float32Ok()
+}
+
+func (j LenientObject) Float32Default(field string, def float32) float32 {
+	return j.Value(field).Float32Default(def)
+}
+
+func (j LenientObject) Float32(field string) float32 {
+	return j.Value(field).Float32()
+}
+
+func (j LenientList) Float32Err(index int) (float32, error) {
+	val, err := j.Value(index).Float32Err()
+	return val, withPath(err, FormatPointer(strconv.Itoa(index)))
+}
+
+func (j LenientList) Float32Ok(index int) (float32, bool) {
+	return j.Value(index).Float32Ok()
+}
+
+func (j LenientList) Float32Default(index int, def float32) float32 {
+	return j.Value(index).Float32Default(def)
+}
+
+func (j LenientList) Float32(index int) float32 {
+	return j.Value(index).Float32()
+}
+
+func (v LenientValue) IntErr() (int, error) {
+	val, lossy, ok := coerceInt(v.value.data)
+	if err := v.check("int", KindNumber, lossy, ok); err != nil {
+		return 0, err
+	}
+	return val, nil
+}
+
+func (v LenientValue) IntOk() (int, bool) {
+	val, err := v.IntErr()
+	return val, err == nil
+}
+
+func (v LenientValue) IntDefault(def int) int {
+	val, ok := v.IntOk()
+	if ok {
+		return val
+	}
+	return def
+}
+
+func (v LenientValue) Int() int {
+	val, _ := v.IntOk()
+	return val
+}
+
+func (j LenientObject) IntErr(field string) (int, error) {
+	val, err := j.Value(field).IntErr()
+	return val, withPath(err, FormatPointer(field))
+}
+
+func (j LenientObject) IntOk(field string) (int, bool) {
+	return j.Value(field).IntOk()
+}
+
+func (j LenientObject) IntDefault(field string, def int) int {
+	return j.Value(field).IntDefault(def)
+}
+
+func (j LenientObject) Int(field string) int {
+	return j.Value(field).Int()
+}
+
+func (j LenientList) IntErr(index int) (int, error) {
+	val, err := j.Value(index).IntErr()
+	return val, withPath(err, FormatPointer(strconv.Itoa(index)))
+}
+
+func (j LenientList) IntOk(index int) (int, bool) {
+	return j.Value(index).IntOk()
+}
+
+func (j LenientList) IntDefault(index int, def int) int {
+	return j.Value(index).IntDefault(def)
+}
+
+func (j LenientList) Int(index int) int {
+	return j.Value(index).Int()
+}
+
+func (v LenientValue) Int64Err() (int64, error) {
+	val, lossy, ok := coerceInt64(v.value.data)
+	if err := v.check("int64", KindNumber, lossy, ok); err != nil {
+		return 0, err
+	}
+	return val, nil
+}
+
+func (v LenientValue) Int64Ok() (int64, bool) {
+	val, err := v.Int64Err()
+	return val, err == nil
+}
+
+func (v LenientValue) Int64Default(def int64) int64 {
+	val, ok := v.Int64Ok()
+	if ok {
+		return val
+	}
+	return def
+}
+
+func (v LenientValue) Int64() int64 {
+	val, _ := v.Int64Ok()
+	return val
+}
+
+func (j LenientObject) Int64Err(field string) (int64, error) {
+	val, err := j.Value(field).Int64Err()
+	return val, withPath(err, FormatPointer(field))
+}
+
+func (j LenientObject) Int64Ok(field string) (int64, bool) {
+	return j.Value(field).Int64Ok()
+}
+
+func (j LenientObject) Int64Default(field string, def int64) int64 {
+	return j.Value(field).Int64Default(def)
+}
+
+func (j LenientObject) Int64(field string) int64 {
+	return j.Value(field).Int64()
+}
+
+func (j LenientList) Int64Err(index int) (int64, error) {
+	val, err := j.Value(index).Int64Err()
+	return val, withPath(err, FormatPointer(strconv.Itoa(index)))
+}
+
+func (j LenientList) Int64Ok(index int) (int64, bool) {
+	return j.Value(index).Int64Ok()
+}
+
+func (j LenientList) Int64Default(index int, def int64) int64 {
+	return j.Value(index).Int64Default(def)
+}
+
+func (j LenientList) Int64(index int) int64 {
+	return j.Value(index).Int64()
+}
+
+func (v LenientValue) Int32Err() (int32, error) {
+	val, lossy, ok := coerceInt32(v.value.data)
+	if err := v.check("int32", KindNumber, lossy, ok); err != nil {
+		return 0, err
+	}
+	return val, nil
+}
+
+func (v LenientValue) Int32Ok() (int32, bool) {
+	val, err := v.Int32Err()
+	return val, err == nil
+}
+
+func (v LenientValue) Int32Default(def int32) int32 {
+	val, ok := v.Int32Ok()
+	if ok {
+		return val
+	}
+	return def
+}
+
+func (v LenientValue) Int32() int32 {
+	val, _ := v.Int32Ok()
+	return val
+}
+
+func (j LenientObject) Int32Err(field string) (int32, error) {
+	val, err := j.Value(field).Int32Err()
+	return val, withPath(err, FormatPointer(field))
+}
+
+func (j LenientObject) Int32Ok(field string) (int32, bool) {
+	return j.Value(field).Int32Ok()
+}
+
+func (j LenientObject) Int32Default(field string, def int32) int32 {
+	return j.Value(field).Int32Default(def)
+}
+
+func (j LenientObject) Int32(field string) int32 {
+	return j.Value(field).Int32()
+}
+
+func (j LenientList) Int32Err(index int) (int32, error) {
+	val, err := j.Value(index).Int32Err()
+	return val, withPath(err, FormatPointer(strconv.Itoa(index)))
+}
+
+func (j LenientList) Int32Ok(index int) (int32, bool) {
+	return j.Value(index).Int32Ok()
+}
+
+func (j LenientList) Int32Default(index int, def int32) int32 {
+	return j.Value(index).Int32Default(def)
+}
+
+func (j LenientList) Int32(index int) int32 {
+	return j.Value(index).Int32()
+}
+
+func (v LenientValue) Uint64Err() (uint64, error) {
+	val, lossy, ok := coerceUint64(v.value.data)
+	if err := v.check("uint64", KindNumber, lossy, ok); err != nil {
+		return 0, err
+	}
+	return val, nil
+}
+
+func (v LenientValue) Uint64Ok() (uint64, bool) {
+	val, err := v.Uint64Err()
+	return val, err == nil
+}
+
+func (v LenientValue) Uint64Default(def uint64) uint64 {
+	val, ok := v.Uint64Ok()
+	if ok {
+		return val
+	}
+	return def
+}
+
+func (v LenientValue) Uint64() uint64 {
+	val, _ := v.Uint64Ok()
+	return val
+}
+
+func (j LenientObject) Uint64Err(field string) (uint64, error) {
+	val, err := j.Value(field).Uint64Err()
+	return val, withPath(err, FormatPointer(field))
+}
+
+func (j LenientObject) Uint64Ok(field string) (uint64, bool) {
+	return j.Value(field).Uint64Ok()
+}
+
+func (j LenientObject) Uint64Default(field string, def uint64) uint64 {
+	return j.Value(field).Uint64Default(def)
+}
+
+func (j LenientObject) Uint64(field string) uint64 {
+	return j.Value(field).Uint64()
+}
+
+func (j LenientList) Uint64Err(index int) (uint64, error) {
+	val, err := j.Value(index).Uint64Err()
+	return val, withPath(err, FormatPointer(strconv.Itoa(index)))
+}
+
+func (j LenientList) Uint64Ok(index int) (uint64, bool) {
+	return j.Value(index).Uint64Ok()
+}
+
+func (j LenientList) Uint64Default(index int, def uint64) uint64 {
+	return j.Value(index).Uint64Default(def)
+}
+
+func (j LenientList) Uint64(index int) uint64 {
+	return j.Value(index).Uint64()
+}
+
+func (v LenientValue) BoolErr() (bool, error) {
+	val, lossy, ok := coerceBool(v.value.data)
+	if err := v.check("bool", KindBool, lossy, ok); err != nil {
+		return false, err
+	}
+	return val, nil
+}
+
+func (v LenientValue) BoolOk() (bool, bool) {
+	val, err := v.BoolErr()
+	return val, err == nil
+}
+
+func (v LenientValue) BoolDefault(def bool) bool {
+	val, ok := v.BoolOk()
+	if ok {
+		return val
+	}
+	return def
+}
+
+func (v LenientValue) Bool() bool {
+	val, _ := v.BoolOk()
+	return val
+}
+
+func (j LenientObject) BoolErr(field string) (bool, error) {
+	val, err := j.Value(field).BoolErr()
+	return val, withPath(err, FormatPointer(field))
+}
+
+func (j LenientObject) BoolOk(field string) (bool, bool) {
+	return j.Value(field).BoolOk()
+}
+
+func (j LenientObject) BoolDefault(field string, def bool) bool {
+	return j.Value(field).BoolDefault(def)
+}
+
+func (j LenientObject) Bool(field string) bool {
+	return j.Value(field).Bool()
+}
+
+func (j LenientList) BoolErr(index int) (bool, error) {
+	val, err := j.Value(index).BoolErr()
+	return val, withPath(err, FormatPointer(strconv.Itoa(index)))
+}
+
+func (j LenientList) BoolOk(index int) (bool, bool) {
+	return j.Value(index).BoolOk()
+}
+
+func (j LenientList) BoolDefault(index int, def bool) bool {
+	return j.Value(index).BoolDefault(def)
+}
+
+func (j LenientList) Bool(index int) bool {
+	return j.Value(index).Bool()
+}
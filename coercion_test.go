@@ -0,0 +1,174 @@
+package dynjson_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/go-schild/dynjson"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLenientObject_Numbers(t *testing.T) {
+	j, _ := dynjson.ParseObject(`{"a": "42", "b": " 1e3 ", "c": "3.7", "d": true, "e": "abc", "f": null, "g": "9007199254740993"}`)
+	l := j.Lenient()
+
+	assert.Equal(t, 42, l.Int("a"))
+	assert.Equal(t, 42.0, l.Float64("a"))
+	assert.Equal(t, int64(1000), l.Int64("b"))
+	assert.Equal(t, 3.7, l.Float64("c"))
+	assert.Equal(t, 1, l.Int("d"))
+	assert.Equal(t, uint64(9007199254740993), l.Uint64("g"))
+
+	_, ok := l.IntOk("c")
+	assert.False(t, ok)
+	_, err := l.IntErr("c")
+	assert.True(t, errors.Is(err, dynjson.ErrLossy))
+	assert.Equal(t, `dynjson: "/c": converting "3.7" into int loses information`, err.Error())
+
+	_, err = l.Float64Err("e")
+	assert.True(t, errors.Is(err, dynjson.ErrTypeMismatch))
+	_, err = l.Float64Err("f")
+	assert.True(t, errors.Is(err, dynjson.ErrTypeMismatch))
+	_, err = l.Float64Err("x")
+	assert.True(t, errors.Is(err, dynjson.ErrMissing))
+
+	assert.Equal(t, 3, j.Lenient(dynjson.AllowLossy()).Int("c"))
+	assert.Equal(t, 5, l.IntDefault("e", 5))
+
+	// The strict getters are not affected.
+	_, ok = j.IntOk("a")
+	assert.False(t, ok)
+}
+
+func TestLenientObject_Strings(t *testing.T) {
+	j, _ := dynjson.ParseObject(`{"a": 42, "b": 1.5, "c": false, "d": "x", "e": [1]}`)
+	l := j.Lenient()
+
+	assert.Equal(t, "42", l.String("a"))
+	assert.Equal(t, "1.5", l.String("b"))
+	assert.Equal(t, "false", l.String("c"))
+	assert.Equal(t, "x", l.String("d"))
+
+	_, err := l.StringErr("e")
+	assert.True(t, errors.Is(err, dynjson.ErrTypeMismatch))
+
+	n, _ := dynjson.ParseObject(`{"a": 9007199254740993}`, dynjson.UseNumber())
+	assert.Equal(t, "9007199254740993", n.Lenient().String("a"))
+}
+
+func TestLenientObject_GoNumbers(t *testing.T) {
+	l := dynjson.JsonObject{"n": 5, "u": uint8(7), "f": float32(1.5), "b": 1}.Lenient()
+
+	assert.Equal(t, "5", l.String("n"))
+	assert.Equal(t, "7", l.String("u"))
+	assert.Equal(t, "1.5", l.String("f"))
+	assert.True(t, l.Bool("b"))
+	assert.Equal(t, 5.0, l.Float64("n"))
+}
+
+func TestLenientObject_Bools(t *testing.T) {
+	j, _ := dynjson.ParseObject(`{"a": "true", "b": "0", "c": 1, "d": 0, "e": 2, "f": "yes"}`)
+	l := j.Lenient()
+
+	assert.True(t, l.Bool("a"))
+	assert.False(t, l.BoolDefault("b", true))
+	assert.True(t, l.Bool("c"))
+	assert.False(t, l.BoolDefault("d", true))
+
+	_, err := l.BoolErr("e")
+	assert.True(t, errors.Is(err, dynjson.ErrLossy))
+	assert.True(t, j.Lenient(dynjson.AllowLossy()).Bool("e"))
+
+	_, ok := l.BoolOk("f")
+	assert.False(t, ok)
+}
+
+func TestLenientObject_Lists(t *testing.T) {
+	j, _ := dynjson.ParseObject(`{"a": [1, 2], "b": "x", "c": null, "d": {"e": "5"}}`)
+	l := j.Lenient()
+
+	assert.Equal(t, 2, l.List("a").Len())
+
+	list, ok := l.ListOk("b")
+	assert.True(t, ok)
+	assert.Equal(t, 1, list.Len())
+	assert.Equal(t, "x", list.String(0))
+
+	list, ok = l.ListOk("c")
+	assert.True(t, ok)
+	assert.Equal(t, 0, list.Len())
+
+	list, ok = l.ListOk("d")
+	assert.True(t, ok)
+	assert.Equal(t, 1, list.Len())
+
+	_, ok = l.ListOk("x")
+	assert.False(t, ok)
+
+	// Nested objects and lists keep the lenient mode.
+	assert.Equal(t, 5, l.Object("d").Int("e"))
+	assert.Equal(t, 5, l.List("d").Object(0).Int("e"))
+}
+
+func TestLenientList(t *testing.T) {
+	j, _ := dynjson.ParseList(`["1", 2.5, "true", {"a": "3"}, [["4"]]]`)
+	l := j.Lenient()
+
+	assert.Equal(t, 1, l.Int(0))
+	assert.Equal(t, "2.5", l.String(1))
+	assert.True(t, l.Bool(2))
+	assert.Equal(t, 3, l.Object(3).Int("a"))
+	assert.Equal(t, int64(4), l.List(4).List(0).Int64(0))
+	assert.Equal(t, 7, l.IntDefault(9, 7))
+
+	_, err := l.IntErr(1)
+	assert.True(t, errors.Is(err, dynjson.ErrLossy))
+	var dErr *dynjson.Error
+	assert.True(t, errors.As(err, &dErr))
+	assert.Equal(t, "/1", dErr.Path)
+
+	_, err = l.Int64Err(5)
+	assert.True(t, errors.Is(err, dynjson.ErrMissing))
+}
+
+func TestLenientValue(t *testing.T) {
+	list, _ := dynjson.ParseList(`["1", "2.5", true]`)
+
+	assert.Equal(t, 1, list[0].Lenient().Int())
+	assert.Equal(t, float32(2.5), list[1].Lenient().Float32())
+	assert.Equal(t, int32(2), list[1].Lenient(dynjson.AllowLossy()).Int32())
+	assert.Equal(t, "true", list[2].Lenient().String())
+
+	v := dynjson.NewValue("1e300")
+	_, err := v.Lenient().Int64Err()
+	assert.True(t, errors.Is(err, dynjson.ErrTypeMismatch))
+	assert.Equal(t, 1e300, v.Lenient().Float64())
+	assert.Equal(t, "1e300", v.Lenient().Value().String())
+}
+
+func TestLenientValue_LossyFloats(t *testing.T) {
+	n, _ := dynjson.Parse([]byte(`9007199254740993`), dynjson.UseNumber())
+	testData := []dynjson.Value{
+		dynjson.NewValue("9007199254740993"),
+		dynjson.NewValue(int64(9007199254740993)),
+		dynjson.NewValue(uint64(18446744073709551615)),
+		n,
+	}
+	for _, v := range testData {
+		_, err := v.Lenient().Float64Err()
+		assert.True(t, errors.Is(err, dynjson.ErrLossy), v.ToString())
+		assert.NotZero(t, v.Lenient(dynjson.AllowLossy()).Float64())
+	}
+
+	assert.Equal(t, 9007199254740992.0, dynjson.NewValue("9007199254740992").Lenient().Float64())
+	assert.Equal(t, 0.1, dynjson.NewValue("0.1").Lenient().Float64())
+
+	_, err := dynjson.NewValue(16777217).Lenient().Float32Err()
+	assert.True(t, errors.Is(err, dynjson.ErrLossy))
+	assert.Equal(t, float32(0.1), dynjson.NewValue(0.1).Lenient().Float32())
+
+	_, err = dynjson.NewValue("1e40").Lenient().Float32Err()
+	assert.NotNil(t, err)
+	_, err = dynjson.NewValue(1e40).Lenient().Float32Err()
+	assert.True(t, errors.Is(err, dynjson.ErrOutOfRange))
+}
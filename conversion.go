@@ -1,5 +1,12 @@
 package dynjson
 
+import (
+	"encoding/json"
+	"math"
+	"math/big"
+	"strconv"
+)
+
 func convToObject(data interface{}) (JsonObject, bool) {
 	if data != nil {
 		if dataObject, ok := data.(map[string]interface{}); ok {
@@ -8,6 +15,9 @@ func convToObject(data interface{}) (JsonObject, bool) {
 		if dataObject, ok := data.(JsonObject); ok {
 			return dataObject, ok
 		}
+		if dataObject, ok := data.(*OrderedObject); ok && dataObject != nil {
+			return dataObject.values, ok
+		}
 	}
 
 	return nil, false
@@ -18,6 +28,9 @@ func convToList(data interface{}) (JsonList, bool) {
 		if dataList, ok := data.([]interface{}); ok {
 			return NewJsonList(dataList), ok
 		}
+		if dataList, ok := data.(JsonListRaw); ok {
+			return NewJsonList(dataList), ok
+		}
 		if dataList, ok := data.(JsonList); ok {
 			return dataList, ok
 		}
@@ -37,15 +50,89 @@ func convToString(data interface{}) (string, bool) {
 }
 
 func convToFloat64(data interface{}) (float64, bool) {
-	if data != nil {
-		if dataFloat64, ok := data.(float64); ok {
-			return dataFloat64, true
+	data = normalize(data)
+	switch d := data.(type) {
+	case float64:
+		return d, true
+	case json.Number:
+		val, err := d.Float64()
+		return val, err == nil
+	case int64:
+		return float64(d), true
+	case uint64:
+		return float64(d), true
+	}
+
+	return 0, false
+}
+
+// convToInt64 converts a number into an int64.
+// In contrast to a type cast, it fails on fractional numbers and on numbers which don't fit into an int64.
+func convToInt64(data interface{}) (int64, bool) {
+	data = normalize(data)
+	switch d := data.(type) {
+	case float64:
+		if d != math.Trunc(d) || d < -(1<<63) || d >= 1<<63 {
+			return 0, false
+		}
+		return int64(d), true
+	case json.Number:
+		if val, err := strconv.ParseInt(string(d), 10, 64); err == nil {
+			return val, true
+		}
+		if f, ok := parseBigFloat(d); ok && f.IsInt() {
+			val, acc := f.Int64()
+			return val, acc == big.Exact
+		}
+	case int64:
+		return d, true
+	case uint64:
+		if d <= math.MaxInt64 {
+			return int64(d), true
 		}
 	}
 
 	return 0, false
 }
 
+// convToUint64 converts a number into an uint64.
+// In contrast to a type cast, it fails on fractional, negative and too large numbers.
+func convToUint64(data interface{}) (uint64, bool) {
+	data = normalize(data)
+	switch d := data.(type) {
+	case float64:
+		if d != math.Trunc(d) || d < 0 || d >= 1<<64 {
+			return 0, false
+		}
+		return uint64(d), true
+	case json.Number:
+		if val, err := strconv.ParseUint(string(d), 10, 64); err == nil {
+			return val, true
+		}
+		if f, ok := parseBigFloat(d); ok && f.IsInt() {
+			val, acc := f.Uint64()
+			return val, acc == big.Exact
+		}
+	case int64:
+		if d >= 0 {
+			return uint64(d), true
+		}
+	case uint64:
+		return d, true
+	}
+
+	return 0, false
+}
+
+// parseBigFloat parses numbers like "1e3" or "42.0", which strconv.ParseInt doesn't accept.
+func parseBigFloat(n json.Number) (*big.Float, bool) {
+	f, _, err := big.ParseFloat(string(n), 10, 256, big.ToNearestEven)
+	if err != nil {
+		return nil, false
+	}
+	return f, true
+}
+
 func convToBool(data interface{}) (bool, bool) {
 	if data != nil {
 		if dataBool, ok := data.(bool); ok {
@@ -55,3 +142,53 @@ func convToBool(data interface{}) (bool, bool) {
 
 	return false, false
 }
+
+// normalize converts Go numbers, which can be part of trees built by hand, into float64, int64 or uint64, so integers
+// keep their exact value. Wrapped values like Value or JsonListItem are unwrapped.
+func normalize(data interface{}) interface{} {
+	switch d := data.(type) {
+	case int:
+		return int64(d)
+	case int8:
+		return int64(d)
+	case int16:
+		return int64(d)
+	case int32:
+		return int64(d)
+	case uint:
+		return uint64(d)
+	case uint8:
+		return uint64(d)
+	case uint16:
+		return uint64(d)
+	case uint32:
+		return uint64(d)
+	case float32:
+		return float64(d)
+	case Value:
+		return d.data
+	case JsonListItem:
+		return d.data
+	}
+
+	return data
+}
+
+func kindOf(data interface{}) Kind {
+	switch data.(type) {
+	case nil:
+		return KindNull
+	case map[string]interface{}, JsonObject, *OrderedObject:
+		return KindObject
+	case []interface{}, JsonListRaw, JsonList:
+		return KindArray
+	case string:
+		return KindString
+	case float64, json.Number, float32, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return KindNumber
+	case bool:
+		return KindBool
+	}
+
+	return KindMissing
+}
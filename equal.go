@@ -0,0 +1,177 @@
+package dynjson
+
+import (
+	"encoding/binary"
+	"hash/fnv"
+	"math"
+)
+
+// Clone returns a deep copy of the object. Changes of the copy don't affect the original and vice versa.
+func (j JsonObject) Clone() JsonObject {
+	if j == nil {
+		return nil
+	}
+	return cloneNode(j).(JsonObject)
+}
+
+// Clone returns a deep copy of the list. Changes of the copy don't affect the original and vice versa.
+func (j JsonList) Clone() JsonList {
+	if j == nil {
+		return nil
+	}
+	return cloneNode(j).(JsonList)
+}
+
+// Clone returns a deep copy of the value.
+func (v Value) Clone() Value {
+	return Value{data: cloneNode(v.data), exists: v.exists}
+}
+
+// EqualOption changes how Equal compares documents.
+type EqualOption func(*equalConfig)
+
+type equalConfig struct {
+	tolerance float64
+}
+
+// FloatTolerance makes Equal treat numbers as equal, when they differ by at most epsilon.
+func FloatTolerance(epsilon float64) EqualOption {
+	return func(config *equalConfig) {
+		config.tolerance = epsilon
+	}
+}
+
+// Equal compares two json documents, e.g. JsonObject, JsonList, *OrderedObject, Value or trees built from
+// map[string]interface{} and []interface{}.
+// Numbers are compared by value, regardless of their Go type, so 1, int64(1), 1.0 and json.Number("1e0") are equal.
+// The order of object keys doesn't matter. A missing Value is only equal to another missing Value.
+func Equal(a, b interface{}, opts ...EqualOption) bool {
+	config := equalConfig{}
+	for _, opt := range opts {
+		opt(&config)
+	}
+
+	valA, okA := a.(Value)
+	valB, okB := b.(Value)
+	if okA && okB && !valA.exists && !valB.exists {
+		return true
+	}
+	if okA && !valA.exists || okB && !valB.exists {
+		return false
+	}
+
+	return nodesEqualTolerance(normalize(a), normalize(b), config.tolerance)
+}
+
+// Hash returns a hash of the object, which is stable across processes. Objects, which are Equal, have the same hash.
+// It can be used for deduplication and as cache key, but different objects can have the same hash, too.
+func (j JsonObject) Hash() uint64 {
+	return hashNode(j)
+}
+
+// Hash returns a hash of the list. See JsonObject.Hash.
+func (j JsonList) Hash() uint64 {
+	return hashNode(j)
+}
+
+// Hash returns a hash of the object, which ignores the order of the keys. See JsonObject.Hash.
+func (o *OrderedObject) Hash() uint64 {
+	return hashNode(o)
+}
+
+// Hash returns a hash of the value. Missing values don't have the same hash as null. See JsonObject.Hash.
+func (v Value) Hash() uint64 {
+	h := hasher{}
+	if v.exists {
+		h.node(v.data)
+	} else {
+		h.buf = append(h.buf, hashTagMissing)
+	}
+	return h.sum()
+}
+
+// Type tags of the hash input, so e.g. the string "1" and the number 1 don't have the same input.
+const (
+	hashTagMissing byte = iota
+	hashTagNull
+	hashTagFalse
+	hashTagTrue
+	hashTagNumber
+	hashTagString
+	hashTagList
+	hashTagObject
+)
+
+func hashNode(data interface{}) uint64 {
+	h := hasher{}
+	h.node(data)
+	return h.sum()
+}
+
+// hasher writes an unambiguous encoding of a json tree, which doesn't depend on the Go types or on the key order.
+type hasher struct {
+	buf []byte
+}
+
+// sum returns the FNV-1a hash of the encoding.
+func (h *hasher) sum() uint64 {
+	f := fnv.New64a()
+	_, _ = f.Write(h.buf)
+	return f.Sum64()
+}
+
+func (h *hasher) node(data interface{}) {
+	if obj, ok := convToObject(data); ok {
+		h.buf = append(h.buf, hashTagObject)
+		h.length(len(obj))
+		for _, key := range objectKeys(obj) {
+			h.string(key)
+			h.node(obj[key])
+		}
+		return
+	}
+
+	if n, ok := listLen(data); ok {
+		h.buf = append(h.buf, hashTagList)
+		h.length(n)
+		for i := 0; i < n; i++ {
+			h.node(listGet(data, i))
+		}
+		return
+	}
+
+	switch kindOf(data) {
+	case KindNumber:
+		// All numbers are hashed as float64, because Equal compares them as float64, when they are not both
+		// integers.
+		f, _ := convToFloat64(data)
+		if f == 0 {
+			// Turns -0 into 0.
+			f = 0
+		}
+		h.buf = append(h.buf, hashTagNumber)
+		h.buf = append(h.buf, make([]byte, 8)...)
+		binary.BigEndian.PutUint64(h.buf[len(h.buf)-8:], math.Float64bits(f))
+	case KindString:
+		h.buf = append(h.buf, hashTagString)
+		h.string(data.(string))
+	case KindBool:
+		if data.(bool) {
+			h.buf = append(h.buf, hashTagTrue)
+		} else {
+			h.buf = append(h.buf, hashTagFalse)
+		}
+	default:
+		h.buf = append(h.buf, hashTagNull)
+	}
+}
+
+func (h *hasher) string(s string) {
+	h.length(len(s))
+	h.buf = append(h.buf, s...)
+}
+
+func (h *hasher) length(n int) {
+	var b [binary.MaxVarintLen64]byte
+	h.buf = append(h.buf, b[:binary.PutUvarint(b[:], uint64(n))]...)
+}
@@ -0,0 +1,121 @@
+package dynjson_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/go-schild/dynjson"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJsonObject_Clone(t *testing.T) {
+	j, _ := dynjson.ParseObject(`{"a": {"b": [1, {"c": 2}]}}`)
+	other := dynjson.JsonObject{"x": 1.0}
+
+	j.SetObject("o", other.Clone())
+	c := j.Clone()
+	other.SetNumber("x", 2)
+	c.Object("a").List("b")[1].Object().SetNumber("c", 3)
+	c.Object("a").SetString("d", "new")
+
+	assert.Equal(t, `{"a":{"b":[1,{"c":2}]},"o":{"x":1}}`, j.ToString())
+	assert.Equal(t, `{"a":{"b":[1,{"c":3}],"d":"new"},"o":{"x":1}}`, c.ToString())
+	assert.Nil(t, dynjson.JsonObject(nil).Clone())
+}
+
+func TestJsonList_Clone(t *testing.T) {
+	j, _ := dynjson.ParseList(`[[1], {"a": 2}]`)
+
+	c := j.Clone()
+	c[1].Object().SetNumber("a", 3)
+	c.Set(0, "x")
+
+	assert.Equal(t, `[[1],{"a":2}]`, j.ToString())
+	assert.Equal(t, `["x",{"a":3}]`, c.ToString())
+	assert.Nil(t, dynjson.JsonList(nil).Clone())
+}
+
+func TestValue_Clone(t *testing.T) {
+	v := dynjson.NewValue(dynjson.JsonObject{"a": 1.0})
+	c := v.Clone()
+	c.Object().SetNumber("a", 2)
+	assert.Equal(t, 1, v.Object().Int("a"))
+
+	assert.False(t, dynjson.Value{}.Clone().Exists())
+}
+
+func TestEqual(t *testing.T) {
+	j, _ := dynjson.ParseObject(`{"a": 1, "b": [1.5, "x", null, true], "c": {}}`)
+	n, _ := dynjson.ParseObject(`{"c": {}, "b": [1.5, "x", null, true], "a": 1.0}`, dynjson.UseNumber())
+	o, _ := dynjson.ParseOrderedObject(`{"b": [1.5, "x", null, true], "c": {}, "a": 1e0}`)
+	hand := dynjson.JsonObject{"a": int64(1), "b": []interface{}{1.5, "x", nil, true}, "c": map[string]interface{}{}}
+
+	assert.True(t, dynjson.Equal(j, n))
+	assert.True(t, dynjson.Equal(j, o))
+	assert.True(t, dynjson.Equal(j, hand))
+	assert.True(t, dynjson.Equal(j, dynjson.NewValue(n)))
+
+	testData := []struct {
+		a, b  string
+		equal bool
+	}{
+		{`1`, `1.0`, true},
+		{`9007199254740993`, `9007199254740992`, false},
+		{`[1, 2]`, `[2, 1]`, false},
+		{`{"a": 1}`, `{"a": 1, "b": 2}`, false},
+		{`{"a": null}`, `{}`, false},
+		{`"1"`, `1`, false},
+		{`0`, `false`, false},
+		{`null`, `null`, true},
+	}
+	for _, data := range testData {
+		a, _ := dynjson.Parse([]byte(data.a), dynjson.UseNumber())
+		b, _ := dynjson.Parse([]byte(data.b), dynjson.UseNumber())
+		assert.Equal(t, data.equal, dynjson.Equal(a, b), data.a+" "+data.b)
+	}
+
+	assert.True(t, dynjson.Equal(dynjson.Value{}, dynjson.Value{}))
+	assert.False(t, dynjson.Equal(dynjson.Value{}, dynjson.NewValue(nil)))
+}
+
+func TestEqual_FloatTolerance(t *testing.T) {
+	a, _ := dynjson.ParseList(`[0.1, 10, {"x": 3.0}]`)
+	b, _ := dynjson.ParseList(`[0.1000001, 10.00000001, {"x": 2.9999}]`)
+
+	assert.False(t, dynjson.Equal(a, b))
+	assert.False(t, dynjson.Equal(a, b, dynjson.FloatTolerance(1e-6)))
+	assert.True(t, dynjson.Equal(a, b, dynjson.FloatTolerance(1e-3)))
+	assert.True(t, dynjson.Equal(dynjson.NewValue(1), dynjson.NewValue(2), dynjson.FloatTolerance(1)))
+}
+
+func TestJsonObject_Hash(t *testing.T) {
+	j, _ := dynjson.ParseObject(`{"a": 1, "b": [1.5, "x", null, true], "c": {"d": -0}}`)
+	n, _ := dynjson.ParseObject(`{"c": {"d": 0}, "b": [1.5, "x", null, true], "a": 1.0}`, dynjson.UseNumber())
+	o, _ := dynjson.ParseOrderedObject(`{"b": [1.5, "x", null, true], "a": 1, "c": {"d": 0.0}}`)
+
+	assert.Equal(t, j.Hash(), n.Hash())
+	assert.Equal(t, j.Hash(), o.Hash())
+	assert.Equal(t, j.Hash(), dynjson.NewValue(j).Hash())
+	// The hash must not change between versions, it may be used as persistent cache key.
+	assert.Equal(t, uint64(0xeda5ecde18b55b35), j.Hash())
+
+	testData := []string{`{}`, `[]`, `null`, `""`, `0`, `false`, `true`, `{"a": "b"}`, `{"ab": ""}`, `["a", "b"]`,
+		`["ab"]`, `[[]]`, `[{}]`, `"0"`, `1`}
+	hashes := map[uint64]string{}
+	for _, data := range testData {
+		v, _ := dynjson.Parse([]byte(data))
+		hash := v.Hash()
+		assert.NotContains(t, hashes, hash, data)
+		hashes[hash] = data
+	}
+	assert.NotEqual(t, dynjson.Value{}.Hash(), dynjson.NewValue(nil).Hash())
+}
+
+func TestJsonList_Hash(t *testing.T) {
+	var raw []interface{}
+	assert.Nil(t, json.Unmarshal([]byte(`[1, {"a": 2}]`), &raw))
+	j, _ := dynjson.ParseList(`[1, {"a": 2}]`)
+
+	assert.Equal(t, j.Hash(), dynjson.NewJsonList(raw).Hash())
+	assert.NotEqual(t, j.Hash(), dynjson.JsonList{j[1], j[0]}.Hash())
+}
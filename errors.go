@@ -0,0 +1,195 @@
+package dynjson
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+)
+
+// ErrorKind classifies an Error.
+type ErrorKind int
+
+const (
+	// ErrorKindSyntax is the kind of errors caused by malformed json.
+	ErrorKindSyntax ErrorKind = iota + 1
+	// ErrorKindTypeMismatch is the kind of errors caused by values of an unexpected type.
+	ErrorKindTypeMismatch
+	// ErrorKindMissing is the kind of errors caused by missing fields.
+	ErrorKindMissing
+	// ErrorKindOutOfRange is the kind of errors caused by numbers which don't fit into the requested type and by
+	// list indices out of range.
+	ErrorKindOutOfRange
+	// ErrorKindLossy is the kind of errors caused by coercions which would lose information, see Lenient.
+	ErrorKindLossy
+	// ErrorKindDuplicateKey is the kind of errors caused by objects with duplicate keys, see DuplicateKeys.
+	ErrorKindDuplicateKey
+)
+
+// Sentinel errors for use with errors.Is, e.g. errors.Is(err, dynjson.ErrMissing).
+var (
+	ErrSyntax       = errors.New("dynjson: syntax error")
+	ErrTypeMismatch = errors.New("dynjson: type mismatch")
+	ErrMissing      = errors.New("dynjson: missing value")
+	ErrOutOfRange   = errors.New("dynjson: out of range")
+	ErrLossy        = errors.New("dynjson: lossy coercion")
+	ErrDuplicateKey = errors.New("dynjson: duplicate key")
+)
+
+var errorKindSentinels = map[ErrorKind]error{
+	ErrorKindSyntax:       ErrSyntax,
+	ErrorKindTypeMismatch: ErrTypeMismatch,
+	ErrorKindMissing:      ErrMissing,
+	ErrorKindOutOfRange:   ErrOutOfRange,
+	ErrorKindLossy:        ErrLossy,
+	ErrorKindDuplicateKey: ErrDuplicateKey,
+}
+
+// Error is returned by the parse functions and by the accessors ending with "Err".
+// Use errors.Is with ErrSyntax, ErrTypeMismatch, ErrMissing, ErrOutOfRange, ErrLossy or ErrDuplicateKey to check its
+// kind.
+type Error struct {
+	Kind ErrorKind
+	// Offset, Line and Column describe the position of syntax errors and duplicate keys. Line and column start with 1,
+	// the column is counted in bytes.
+	Offset int64
+	Line   int
+	Column int
+	// Path is the JSON pointer of the value, which caused an access error. It is empty, when it is unknown.
+	Path string
+	// Expected and Actual are the kinds of the value for type mismatches.
+	Expected Kind
+	Actual   Kind
+	// Reason describes the error in more detail, e.g. "3.5 is not an integer".
+	Reason string
+	// Err is the underlying error, e.g. a *json.SyntaxError or io.ErrUnexpectedEOF.
+	Err error
+}
+
+func (e *Error) Error() string {
+	switch e.Kind {
+	case ErrorKindSyntax:
+		return fmt.Sprintf("dynjson: syntax error at line %d, column %d (offset %d): %s", e.Line, e.Column, e.Offset, e.detail())
+	case ErrorKindTypeMismatch:
+		if e.Reason != "" {
+			break
+		}
+		return fmt.Sprintf("dynjson: %sexpected %s, got %s", e.pathPrefix(), e.Expected, e.Actual)
+	case ErrorKindMissing:
+		return fmt.Sprintf("dynjson: %smissing value", e.pathPrefix())
+	case ErrorKindDuplicateKey:
+		return fmt.Sprintf("dynjson: %sduplicate key at line %d, column %d", e.pathPrefix(), e.Line, e.Column)
+	}
+
+	return fmt.Sprintf("dynjson: %s%s", e.pathPrefix(), e.detail())
+}
+
+func (e *Error) pathPrefix() string {
+	if e.Path == "" {
+		return ""
+	}
+	return strconv.Quote(e.Path) + ": "
+}
+
+func (e *Error) detail() string {
+	if e.Reason != "" {
+		return e.Reason
+	}
+	if e.Err != nil {
+		return e.Err.Error()
+	}
+	return "unknown error"
+}
+
+// Unwrap returns the underlying error.
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether target is the sentinel error of the error's kind.
+func (e *Error) Is(target error) bool {
+	return errorKindSentinels[e.Kind] == target
+}
+
+func missingError() *Error {
+	return &Error{Kind: ErrorKindMissing}
+}
+
+func typeError(expected Kind, data interface{}) *Error {
+	return &Error{Kind: ErrorKindTypeMismatch, Expected: expected, Actual: kindOf(data)}
+}
+
+func rangeError(reason string) *Error {
+	return &Error{Kind: ErrorKindOutOfRange, Reason: reason}
+}
+
+// withPath sets the path of an *Error. Other errors are returned unchanged.
+func withPath(err error, path string) error {
+	if e, ok := err.(*Error); ok {
+		e.Path = path
+	}
+	return err
+}
+
+// syntaxError converts errors of encoding/json into an *Error with the position of the error.
+// Newlines contains the offsets of all newlines read so far.
+func syntaxError(err error, offset int64, newlines []int64) error {
+	var sErr *json.SyntaxError
+	switch {
+	case errors.As(err, &sErr):
+		offset = sErr.Offset
+	case err == io.ErrUnexpectedEOF, err == ErrTrailingData:
+	default:
+		return err
+	}
+
+	line, column := position(offset, newlines)
+	return &Error{Kind: ErrorKindSyntax, Offset: offset, Line: line, Column: column, Err: err}
+}
+
+// position returns line and column of the character before offset.
+func position(offset int64, newlines []int64) (int, int) {
+	// The offset points behind the character, which caused the error.
+	pos := offset - 1
+	if pos < 0 {
+		pos = 0
+	}
+	line := sort.Search(len(newlines), func(i int) bool { return newlines[i] >= pos })
+	lineStart := int64(0)
+	if line > 0 {
+		lineStart = newlines[line-1] + 1
+	}
+
+	return line + 1, int(pos-lineStart) + 1
+}
+
+// newlineOffsets returns the offsets of all newlines in data.
+func newlineOffsets(data []byte) []int64 {
+	var result []int64
+	for i, c := range data {
+		if c == '\n' {
+			result = append(result, int64(i))
+		}
+	}
+	return result
+}
+
+// positionReader remembers the offsets of newlines, so syntax errors can be reported with line and column.
+type positionReader struct {
+	r        io.Reader
+	offset   int64
+	newlines []int64
+}
+
+func (p *positionReader) Read(b []byte) (int, error) {
+	n, err := p.r.Read(b)
+	for i, c := range b[:n] {
+		if c == '\n' {
+			p.newlines = append(p.newlines, p.offset+int64(i))
+		}
+	}
+	p.offset += int64(n)
+	return n, err
+}
@@ -0,0 +1,119 @@
+package dynjson_test
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/go-schild/dynjson"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestError_Syntax(t *testing.T) {
+	_, err := dynjson.ParseObject("{\n  \"a\": 1,\n  \"b\": x\n}")
+	assert.True(t, errors.Is(err, dynjson.ErrSyntax))
+	assert.False(t, errors.Is(err, dynjson.ErrMissing))
+
+	var dErr *dynjson.Error
+	assert.True(t, errors.As(err, &dErr))
+	assert.Equal(t, dynjson.ErrorKindSyntax, dErr.Kind)
+	assert.Equal(t, 3, dErr.Line)
+	assert.Equal(t, 8, dErr.Column)
+	assert.Equal(t, int64(20), dErr.Offset)
+	assert.Contains(t, err.Error(), "line 3, column 8")
+
+	var sErr *json.SyntaxError
+	assert.True(t, errors.As(err, &sErr))
+
+	// The same position is reported, when the input is read by the tokenizer.
+	_, err = dynjson.ParseObjectReader(strings.NewReader("{\n  \"a\": 1,\n  \"b\": x\n}"), dynjson.MaxDepth(5))
+	assert.True(t, errors.As(err, &dErr))
+	assert.Equal(t, 3, dErr.Line)
+	assert.Equal(t, 8, dErr.Column)
+
+	_, err = dynjson.ParseObject(`{"a": `)
+	assert.True(t, errors.Is(err, dynjson.ErrSyntax))
+}
+
+func TestError_TypeMismatch(t *testing.T) {
+	_, err := dynjson.ParseObject(`[1, 2]`)
+	assert.True(t, errors.Is(err, dynjson.ErrTypeMismatch))
+
+	var dErr *dynjson.Error
+	assert.True(t, errors.As(err, &dErr))
+	assert.Equal(t, dynjson.KindObject, dErr.Expected)
+	assert.Equal(t, dynjson.KindArray, dErr.Actual)
+
+	_, err = dynjson.ParseList(`{}`)
+	assert.True(t, errors.Is(err, dynjson.ErrTypeMismatch))
+}
+
+func TestError_IndexOutOfRange(t *testing.T) {
+	list := dynjson.NewJsonList([]interface{}{1, 2})
+	err := list.Set(5, 3)
+	assert.True(t, errors.Is(err, dynjson.ErrOutOfRange))
+	assert.Equal(t, "dynjson: index 5 out of range (length 2)", err.Error())
+}
+
+func TestJsonObject_StringErr(t *testing.T) {
+	j, _ := dynjson.ParseObject(`{"name": "abc", "age": 42, "a/b": null}`)
+
+	val, err := j.StringErr("name")
+	assert.Nil(t, err)
+	assert.Equal(t, "abc", val)
+
+	_, err = j.StringErr("age")
+	assert.True(t, errors.Is(err, dynjson.ErrTypeMismatch))
+	assert.Equal(t, `dynjson: "/age": expected string, got number`, err.Error())
+
+	_, err = j.StringErr("a/b")
+	assert.True(t, errors.Is(err, dynjson.ErrTypeMismatch))
+	assert.Equal(t, `dynjson: "/a~1b": expected string, got null`, err.Error())
+
+	_, err = j.StringErr("missing")
+	assert.True(t, errors.Is(err, dynjson.ErrMissing))
+
+	var dErr *dynjson.Error
+	assert.True(t, errors.As(err, &dErr))
+	assert.Equal(t, "/missing", dErr.Path)
+}
+
+func TestJsonObject_Int64Err(t *testing.T) {
+	j, _ := dynjson.ParseObject(`{"a": 42, "b": 3.5, "c": 1e300, "d": -1, "e": "42"}`)
+
+	val, err := j.Int64Err("a")
+	assert.Nil(t, err)
+	assert.Equal(t, int64(42), val)
+
+	_, err = j.Int64Err("b")
+	assert.True(t, errors.Is(err, dynjson.ErrOutOfRange))
+	assert.Equal(t, `dynjson: "/b": 3.5 doesn't fit into int64`, err.Error())
+
+	_, err = j.Int64Err("c")
+	assert.True(t, errors.Is(err, dynjson.ErrOutOfRange))
+
+	_, err = j.Uint64Err("d")
+	assert.True(t, errors.Is(err, dynjson.ErrOutOfRange))
+
+	_, err = j.Int32Err("e")
+	assert.True(t, errors.Is(err, dynjson.ErrTypeMismatch))
+
+	_, err = j.BoolErr("f")
+	assert.True(t, errors.Is(err, dynjson.ErrMissing))
+}
+
+func TestJsonListItem_Err(t *testing.T) {
+	list, _ := dynjson.ParseList(`[{"a": 1}, true, 2]`)
+
+	obj, err := list[0].ObjectErr()
+	assert.Nil(t, err)
+	assert.Equal(t, 1, obj.Int("a"))
+
+	_, err = list[1].ListErr()
+	assert.True(t, errors.Is(err, dynjson.ErrTypeMismatch))
+
+	val, err := list[2].Float64Err()
+	assert.Nil(t, err)
+	assert.Equal(t, 2.0, val)
+}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"fmt"
+
 	"github.com/go-schild/dynjson"
 )
 
@@ -18,5 +19,7 @@ func main() {
 	obj2.SetString("nname", "Dane")
 
 	list.Append(obj1, obj2)
-	fmt.Println(list.ToString())  // TODO doesn't work properly yet
+
+	// [{"fname":"John","nname":"Doe"},{"fname":"Jane","mname":"Maria","nname":"Dane"}]
+	fmt.Println(list.ToString())
 }
@@ -0,0 +1,18 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/go-schild/dynjson"
+)
+
+func main() {
+	obj := dynjson.NewJsonObject()
+
+	// Missing objects and lists are created on the way.
+	_ = obj.SetPath([]string{"outer", "inner"}, 5)
+	_ = obj.SetPointer("/outer/list/1/name", "John")
+
+	// {"outer":{"inner":5,"list":[null,{"name":"John"}]}}
+	fmt.Println(obj.ToString())
+}
@@ -0,0 +1,114 @@
+//go:build ignore
+// +build ignore
+
+// This program generates coercion_getters.go, the typed getters of LenientValue, LenientObject and LenientList.
+// Run it with "go generate".
+package main
+
+import (
+	"bytes"
+	"go/format"
+	"io/ioutil"
+	"log"
+	"text/template"
+)
+
+type getter struct {
+	Name string
+	Type string
+	Kind string
+	Zero string
+}
+
+var getters = []getter{
+	{"String", "string", "KindString", `""`},
+	{"Float64", "float64", "KindNumber", "0"},
+	{"Float32", "float32", "KindNumber", "0"},
+	{"Int", "int", "KindNumber", "0"},
+	{"Int64", "int64", "KindNumber", "0"},
+	{"Int32", "int32", "KindNumber", "0"},
+	{"Uint64", "uint64", "KindNumber", "0"},
+	{"Bool", "bool", "KindBool", "false"},
+}
+
+var tmpl = template.Must(template.New("").Parse(`// Code generated by gen_coercion.go; DO NOT EDIT.
+
+package dynjson
+
+import "strconv"
+{{range .}}
+func (v LenientValue) {{.Name}}Err() ({{.Type}}, error) {
+	val, lossy, ok := coerce{{.Name}}(v.value.data)
+	if err := v.check("{{.Type}}", {{.Kind}}, lossy, ok); err != nil {
+		return {{.Zero}}, err
+	}
+	return val, nil
+}
+
+func (v LenientValue) {{.Name}}Ok() ({{.Type}}, bool) {
+	val, err := v.{{.Name}}Err()
+	return val, err == nil
+}
+
+func (v LenientValue) {{.Name}}Default(def {{.Type}}) {{.Type}} {
+	val, ok := v.{{.Name}}Ok()
+	if ok {
+		return val
+	}
+	return def
+}
+
+func (v LenientValue) {{.Name}}() {{.Type}} {
+	val, _ := v.{{.Name}}Ok()
+	return val
+}
+
+func (j LenientObject) {{.Name}}Err(field string) ({{.Type}}, error) {
+	val, err := j.Value(field).{{.Name}}Err()
+	return val, withPath(err, FormatPointer(field))
+}
+
+func (j LenientObject) {{.Name}}Ok(field string) ({{.Type}}, bool) {
+	return j.Value(field).{{.Name}}Ok()
+}
+
+func (j LenientObject) {{.Name}}Default(field string, def {{.Type}}) {{.Type}} {
+	return j.Value(field).{{.Name}}Default(def)
+}
+
+func (j LenientObject) {{.Name}}(field string) {{.Type}} {
+	return j.Value(field).{{.Name}}()
+}
+
+func (j LenientList) {{.Name}}Err(index int) ({{.Type}}, error) {
+	val, err := j.Value(index).{{.Name}}Err()
+	return val, withPath(err, FormatPointer(strconv.Itoa(index)))
+}
+
+func (j LenientList) {{.Name}}Ok(index int) ({{.Type}}, bool) {
+	return j.Value(index).{{.Name}}Ok()
+}
+
+func (j LenientList) {{.Name}}Default(index int, def {{.Type}}) {{.Type}} {
+	return j.Value(index).{{.Name}}Default(def)
+}
+
+func (j LenientList) {{.Name}}(index int) {{.Type}} {
+	return j.Value(index).{{.Name}}()
+}
+{{end}}`))
+
+func main() {
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, getters); err != nil {
+		log.Fatal(err)
+	}
+
+	data, err := format.Source(buf.Bytes())
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := ioutil.WriteFile("coercion_getters.go", data, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
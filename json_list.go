@@ -2,6 +2,7 @@ package dynjson
 
 import (
 	"encoding/json"
+	"fmt"
 )
 
 type JsonListRaw []interface{}
@@ -17,87 +18,257 @@ func NewJsonList(raw JsonListRaw) JsonList {
 
 	if raw != nil {
 		for _, data := range raw {
-			result = append(result, JsonListItem{data})
+			result = append(result, JsonListItem{normalize(data)})
 		}
 	}
 
 	return result
 }
 
-func ParseList(jsonString string) (JsonList, error) {
-	obj := JsonListRaw{}
-	err := json.Unmarshal([]byte(jsonString), &obj)
+// ParseList parses a string containing a json array and returns a json list or an error
+func ParseList(jsonString string, opts ...ParseOption) (JsonList, error) {
+	raw, err := parseBytes([]byte(jsonString), newParseConfig(opts))
 	if err != nil {
 		return nil, err
 	}
 
-	return NewJsonList(obj), nil
+	return toList(raw)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (j JsonList) MarshalJSON() ([]byte, error) {
+	if j == nil {
+		return []byte("null"), nil
+	}
+
+	raw := make(JsonListRaw, 0, len(j))
+	for _, item := range j {
+		raw = append(raw, item.data)
+	}
+
+	return json.Marshal(raw)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (j *JsonList) UnmarshalJSON(data []byte) error {
+	raw := JsonListRaw{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	if raw == nil {
+		*j = nil
+		return nil
+	}
+
+	*j = NewJsonList(raw)
+	return nil
 }
 
 // ToString returns the JSON data as string.
-// Returns an empty string, when an error occurred.
+// Returns an empty string, when an error occurred. Use Marshal to get the error or to change the output.
 func (j JsonList) ToString() string {
 	data, _ := json.Marshal(&j)
 	return string(data)
 }
 
+// Append adds the entries to the end of the list.
+// Go integers like int are converted to int64 and float32 to float64.
 func (j *JsonList) Append(data ...interface{}) {
-	var list []JsonListItem
+	for _, dataEntry := range data {
+		*j = append(*j, JsonListItem{data: normalize(dataEntry)})
+	}
+}
 
+// Prepend adds the entries to the beginning of the list, keeping their order.
+func (j *JsonList) Prepend(data ...interface{}) {
+	list := make(JsonList, 0, len(data)+len(*j))
 	for _, dataEntry := range data {
-		switch dataEntry.(type) {
-		case int:
-			list = append(list, JsonListItem{data: float64(dataEntry.(int))})
-		case float32:
-			list = append(list, JsonListItem{data: float64(dataEntry.(float32))})
-		default:
-			list = append(list, JsonListItem{data: data})
-		}
+		list = append(list, JsonListItem{data: normalize(dataEntry)})
+	}
+
+	*j = append(list, *j...)
+}
+
+// Len returns the number of items in the list.
+func (j JsonList) Len() int {
+	return len(j)
+}
+
+// GetOk returns the item at the index and true, or false, when the index is out of range.
+func (j JsonList) GetOk(index int) (JsonListItem, bool) {
+	if index < 0 || index >= len(j) {
+		return JsonListItem{}, false
 	}
+	return j[index], true
+}
 
-	*j = append(*j, list...)
+// Get returns the item at the index. When the index is out of range, the item is null, just like an item, which is
+// null in the list. Use GetOk or Value to tell them apart.
+func (j JsonList) Get(index int) JsonListItem {
+	val, _ := j.GetOk(index)
+	return val
 }
 
-func (j *JsonList) Prepend(data ...interface{}) {
-	var list []JsonListItem
+// Value returns the item at the index as Value. When the index is out of range, the value is missing.
+func (j JsonList) Value(index int) Value {
+	if index < 0 || index >= len(j) {
+		return Value{}
+	}
+	return j[index].Value()
+}
 
-	for _, dataEntry := range data {
-		switch dataEntry.(type) {
-		case int:
-			list = append(list, JsonListItem{data: float64(dataEntry.(int))})
-		case float32:
-			list = append(list, JsonListItem{data: float64(dataEntry.(float32))})
-		default:
-			list = append(list, JsonListItem{data: data})
+// Set replaces the item at the index.
+func (j JsonList) Set(index int, value interface{}) error {
+	if index < 0 || index >= len(j) {
+		return indexError(index, len(j))
+	}
+
+	j[index] = JsonListItem{data: normalize(value)}
+	return nil
+}
+
+// Insert inserts the values at the index. The items behind the index move back.
+// An index equal to the length of the list appends the values.
+func (j *JsonList) Insert(index int, values ...interface{}) error {
+	if index < 0 || index > len(*j) {
+		return indexError(index, len(*j))
+	}
+
+	list := make(JsonList, 0, len(*j)+len(values))
+	list = append(list, (*j)[:index]...)
+	for _, value := range values {
+		list = append(list, JsonListItem{data: normalize(value)})
+	}
+	list = append(list, (*j)[index:]...)
+
+	*j = list
+	return nil
+}
+
+// Remove removes the item at the index. The items behind the index move up.
+// Like Insert, it stores a new list in j, so objects holding the old list aren't changed.
+func (j *JsonList) Remove(index int) error {
+	if index < 0 || index >= len(*j) {
+		return indexError(index, len(*j))
+	}
+
+	*j = listRemove(*j, index).(JsonList)
+	return nil
+}
+
+// RemoveIf removes all items for which pred returns true and returns the number of removed items.
+// Like Remove, it stores a new list in j.
+func (j *JsonList) RemoveIf(pred func(item JsonListItem) bool) int {
+	list := make(JsonList, 0, len(*j))
+	for _, item := range *j {
+		if !pred(item) {
+			list = append(list, item)
 		}
 	}
 
-	*j = append(list, *j...)
+	removed := len(*j) - len(list)
+	*j = list
+	return removed
+}
+
+// Slice returns a copy of the items from index "from" up to, but not including, index "to".
+func (j JsonList) Slice(from, to int) (JsonList, error) {
+	if from < 0 || from > len(j) {
+		return nil, indexError(from, len(j))
+	}
+	if to < from || to > len(j) {
+		return nil, indexError(to, len(j))
+	}
+
+	list := make(JsonList, to-from)
+	copy(list, j[from:to])
+	return list, nil
+}
+
+func (j JsonList) SetObject(index int, value JsonObject) error {
+	return j.Set(index, value)
+}
+
+func (j JsonList) SetList(index int, value JsonList) error {
+	return j.Set(index, value)
+}
+
+// SetNumber writes an integer or float into the list. See JsonObject.SetNumber.
+func (j JsonList) SetNumber(index int, value float64) error {
+	return j.Set(index, value)
+}
+
+// SetInt64 writes an integer into the list without losing precision. See JsonObject.SetInt64.
+func (j JsonList) SetInt64(index int, value int64) error {
+	return j.Set(index, value)
+}
+
+func (j JsonList) SetString(index int, value string) error {
+	return j.Set(index, value)
+}
+
+func (j JsonList) SetBool(index int, value bool) error {
+	return j.Set(index, value)
+}
+
+func (j JsonList) SetNull(index int) error {
+	return j.Set(index, nil)
+}
+
+func indexError(index, length int) error {
+	return rangeError(fmt.Sprintf("index %d out of range (length %d)", index, length))
+}
+
+// MarshalJSON implements json.Marshaler.
+func (j JsonListItem) MarshalJSON() ([]byte, error) {
+	return json.Marshal(j.data)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (j *JsonListItem) UnmarshalJSON(data []byte) error {
+	var raw interface{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	j.data = raw
+	return nil
+}
+
+// Value returns the list item as Value.
+func (j JsonListItem) Value() Value {
+	return Value{data: j.data, exists: true}
+}
+
+// IsNull checks if the list item is null.
+func (j JsonListItem) IsNull() bool {
+	return j.Value().IsNull()
 }
 
-func (j *JsonListItem) ObjectOk() (JsonObject, bool) {
-	return convToObject(j.data)
+func (j JsonListItem) ObjectOk() (JsonObject, bool) {
+	return j.Value().ObjectOk()
 }
 
-func (j *JsonListItem) Object() JsonObject {
+func (j JsonListItem) Object() JsonObject {
 	val, _ := j.ObjectOk()
 	return val
 }
 
-func (j *JsonListItem) ListOk() (JsonList, bool) {
-	return convToList(j.data)
+func (j JsonListItem) ListOk() (JsonList, bool) {
+	return j.Value().ListOk()
 }
 
-func (j *JsonListItem) List() JsonList {
+func (j JsonListItem) List() JsonList {
 	val, _ := j.ListOk()
 	return val
 }
 
-func (j *JsonListItem) StringOk() (string, bool) {
-	return convToString(j.data)
+func (j JsonListItem) StringOk() (string, bool) {
+	return j.Value().StringOk()
 }
 
-func (j *JsonListItem) StringDefault(def string) string {
+func (j JsonListItem) StringDefault(def string) string {
 	val, ok := j.StringOk()
 	if ok {
 		return val
@@ -105,16 +276,16 @@ func (j *JsonListItem) StringDefault(def string) string {
 	return def
 }
 
-func (j *JsonListItem) String() string {
+func (j JsonListItem) String() string {
 	val, _ := j.StringOk()
 	return val
 }
 
-func (j *JsonListItem) Float64Ok() (float64, bool) {
-	return convToFloat64(j.data)
+func (j JsonListItem) Float64Ok() (float64, bool) {
+	return j.Value().Float64Ok()
 }
 
-func (j *JsonListItem) Float64Default(def float64) float64 {
+func (j JsonListItem) Float64Default(def float64) float64 {
 	val, ok := j.Float64Ok()
 	if ok {
 		return val
@@ -122,17 +293,16 @@ func (j *JsonListItem) Float64Default(def float64) float64 {
 	return def
 }
 
-func (j *JsonListItem) Float64() float64 {
+func (j JsonListItem) Float64() float64 {
 	val, _ := j.Float64Ok()
 	return val
 }
 
-func (j *JsonListItem) Float32Ok() (float32, bool) {
-	val, ok := j.Float64Ok()
-	return float32(val), ok
+func (j JsonListItem) Float32Ok() (float32, bool) {
+	return j.Value().Float32Ok()
 }
 
-func (j *JsonListItem) Float32Default(def float32) float32 {
+func (j JsonListItem) Float32Default(def float32) float32 {
 	val, ok := j.Float32Ok()
 	if ok {
 		return val
@@ -140,17 +310,16 @@ func (j *JsonListItem) Float32Default(def float32) float32 {
 	return def
 }
 
-func (j *JsonListItem) Float32() float32 {
+func (j JsonListItem) Float32() float32 {
 	val, _ := j.Float32Ok()
 	return val
 }
 
-func (j *JsonListItem) IntOk() (int, bool) {
-	val, ok := j.Float64Ok()
-	return int(val), ok
+func (j JsonListItem) IntOk() (int, bool) {
+	return j.Value().IntOk()
 }
 
-func (j *JsonListItem) IntDefault(def int) int {
+func (j JsonListItem) IntDefault(def int) int {
 	val, ok := j.IntOk()
 	if ok {
 		return val
@@ -158,7 +327,116 @@ func (j *JsonListItem) IntDefault(def int) int {
 	return def
 }
 
-func (j *JsonListItem) Int() int {
+func (j JsonListItem) Int() int {
 	val, _ := j.IntOk()
 	return val
 }
+
+func (j JsonListItem) Int64Ok() (int64, bool) {
+	return j.Value().Int64Ok()
+}
+
+func (j JsonListItem) Int64Default(def int64) int64 {
+	val, ok := j.Int64Ok()
+	if ok {
+		return val
+	}
+	return def
+}
+
+func (j JsonListItem) Int64() int64 {
+	val, _ := j.Int64Ok()
+	return val
+}
+
+func (j JsonListItem) Int32Ok() (int32, bool) {
+	return j.Value().Int32Ok()
+}
+
+func (j JsonListItem) Int32Default(def int32) int32 {
+	val, ok := j.Int32Ok()
+	if ok {
+		return val
+	}
+	return def
+}
+
+func (j JsonListItem) Int32() int32 {
+	val, _ := j.Int32Ok()
+	return val
+}
+
+func (j JsonListItem) Uint64Ok() (uint64, bool) {
+	return j.Value().Uint64Ok()
+}
+
+func (j JsonListItem) Uint64Default(def uint64) uint64 {
+	val, ok := j.Uint64Ok()
+	if ok {
+		return val
+	}
+	return def
+}
+
+func (j JsonListItem) Uint64() uint64 {
+	val, _ := j.Uint64Ok()
+	return val
+}
+
+func (j JsonListItem) BoolOk() (bool, bool) {
+	return j.Value().BoolOk()
+}
+
+func (j JsonListItem) BoolDefault(def bool) bool {
+	val, ok := j.BoolOk()
+	if ok {
+		return val
+	}
+	return def
+}
+
+func (j JsonListItem) Bool() bool {
+	val, _ := j.BoolOk()
+	return val
+}
+
+// ObjectErr works like ObjectOk, but returns an *Error, which tells why the item couldn't be read.
+func (j JsonListItem) ObjectErr() (JsonObject, error) {
+	return j.Value().ObjectErr()
+}
+
+func (j JsonListItem) ListErr() (JsonList, error) {
+	return j.Value().ListErr()
+}
+
+func (j JsonListItem) StringErr() (string, error) {
+	return j.Value().StringErr()
+}
+
+func (j JsonListItem) Float64Err() (float64, error) {
+	return j.Value().Float64Err()
+}
+
+func (j JsonListItem) Float32Err() (float32, error) {
+	return j.Value().Float32Err()
+}
+
+func (j JsonListItem) IntErr() (int, error) {
+	return j.Value().IntErr()
+}
+
+func (j JsonListItem) Int64Err() (int64, error) {
+	return j.Value().Int64Err()
+}
+
+func (j JsonListItem) Int32Err() (int32, error) {
+	return j.Value().Int32Err()
+}
+
+func (j JsonListItem) Uint64Err() (uint64, error) {
+	return j.Value().Uint64Err()
+}
+
+func (j JsonListItem) BoolErr() (bool, error) {
+	return j.Value().BoolErr()
+}
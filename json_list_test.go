@@ -1,6 +1,7 @@
 package dynjson_test
 
 import (
+	"encoding/json"
 	"testing"
 
 	"github.com/go-schild/dynjson"
@@ -344,3 +345,285 @@ func TestJsonList_Int(t *testing.T) {
 		assert.Equal(t, index+1, item.Int())
 	}
 }
+
+func TestJsonList_MarshalJSON(t *testing.T) {
+	testData := []string{
+		`[]`,
+		`[1, "Hello", true, null]`,
+		`[{"a": 1}, {"b": "Hello"}]`,
+		`[[1, 2], [3, [4, 5]], []]`,
+		`[{"a": [1, {"b": [2, 3]}]}, [{"c": {}}]]`,
+	}
+
+	for _, data := range testData {
+		j, err := dynjson.ParseList(data)
+		assert.Nil(t, err)
+
+		b, err := json.Marshal(j)
+		assert.Nil(t, err)
+		assert.JSONEq(t, data, string(b))
+		assert.JSONEq(t, data, j.ToString())
+	}
+}
+
+func TestJsonList_UnmarshalJSON(t *testing.T) {
+	testData := []string{
+		`[]`,
+		`[1, "Hello", true, null]`,
+		`[{"a": 1}, {"b": "Hello"}]`,
+		`[[1, 2], [3, [4, 5]], []]`,
+		`[{"a": [1, {"b": [2, 3]}]}, [{"c": {}}]]`,
+	}
+
+	for _, data := range testData {
+		var j dynjson.JsonList
+		err := json.Unmarshal([]byte(data), &j)
+		assert.Nil(t, err)
+		assert.JSONEq(t, data, j.ToString())
+	}
+
+	var j dynjson.JsonList
+	assert.NotNil(t, json.Unmarshal([]byte(`{"a": 1}`), &j))
+}
+
+func TestJsonList_MarshalJSON_Created(t *testing.T) {
+	o := dynjson.NewJsonObject()
+	o.SetString("a", "Hello")
+
+	inner := dynjson.NewJsonList(dynjson.JsonListRaw{float64(1), float64(2)})
+	j := dynjson.NewJsonList(dynjson.JsonListRaw{o, inner, nil})
+
+	assert.JSONEq(t, `[{"a": "Hello"}, [1, 2], null]`, j.ToString())
+}
+
+func TestJsonList_MarshalJSON_Struct(t *testing.T) {
+	type container struct {
+		List   dynjson.JsonList     `json:"list"`
+		Item   dynjson.JsonListItem `json:"item"`
+		Object dynjson.JsonObject   `json:"object"`
+	}
+
+	const testData = `{"list": [{"a": [1, 2]}, [3]], "item": {"b": [4]}, "object": {"c": [[5], {"d": 6}]}}`
+
+	var c container
+	err := json.Unmarshal([]byte(testData), &c)
+	assert.Nil(t, err)
+
+	assert.Equal(t, 2, len(c.List))
+	assert.Equal(t, 2, c.List[0].Object().List("a")[1].Int())
+	assert.Equal(t, 4, c.Item.Object().List("b")[0].Int())
+	assert.Equal(t, 6, c.Object.List("c")[1].Object().Int("d"))
+
+	b, err := json.Marshal(c)
+	assert.Nil(t, err)
+	assert.JSONEq(t, testData, string(b))
+}
+
+func TestJsonList_BoolOk(t *testing.T) {
+	const testData = `[true, false, "Hello"]`
+
+	j, err := dynjson.ParseList(testData)
+	assert.Nil(t, err)
+
+	a, ok := j[0].BoolOk()
+	assert.True(t, ok)
+	assert.True(t, a)
+
+	a, ok = j[1].BoolOk()
+	assert.True(t, ok)
+	assert.False(t, a)
+
+	a, ok = j[2].BoolOk()
+	assert.False(t, ok)
+	assert.False(t, a)
+
+	assert.True(t, j[2].BoolDefault(true))
+	assert.False(t, j[2].Bool())
+}
+
+func TestJsonList_IsNull(t *testing.T) {
+	const testData = `[null, 0, "", {"a": null}, [null]]`
+
+	j, err := dynjson.ParseList(testData)
+	assert.Nil(t, err)
+
+	assert.True(t, j[0].IsNull())
+	assert.False(t, j[1].IsNull())
+	assert.False(t, j[2].IsNull())
+	assert.False(t, j[3].IsNull())
+	assert.True(t, j[3].Object().IsNull("a"))
+	assert.True(t, j[4].List()[0].IsNull())
+
+	j2, err := dynjson.ParseList(j.ToString())
+	assert.Nil(t, err)
+	assert.JSONEq(t, testData, j2.ToString())
+	assert.True(t, j2[0].IsNull())
+}
+
+func TestParseList_UseNumber(t *testing.T) {
+	const testData = `[9007199254740993, 1.5, "Hello"]`
+
+	j, err := dynjson.ParseList(testData, dynjson.UseNumber())
+	assert.Nil(t, err)
+
+	assert.Equal(t, int64(9007199254740993), j[0].Int64())
+	assert.Equal(t, float64(1.5), j[1].Float64())
+	assert.Equal(t, `[9007199254740993,1.5,"Hello"]`, j.ToString())
+}
+
+func TestJsonList_Int64Ok(t *testing.T) {
+	const testData = `[3, 3.9, "3", 1e20]`
+
+	j, err := dynjson.ParseList(testData)
+	assert.Nil(t, err)
+
+	a, ok := j[0].Int64Ok()
+	assert.True(t, ok)
+	assert.Equal(t, int64(3), a)
+
+	_, ok = j[1].Int64Ok()
+	assert.False(t, ok)
+	assert.Equal(t, int64(-1), j[1].Int64Default(-1))
+
+	_, ok = j[2].Int64Ok()
+	assert.False(t, ok)
+
+	_, ok = j[3].Int64Ok()
+	assert.False(t, ok)
+	_, ok = j[3].Uint64Ok()
+	assert.False(t, ok)
+
+	assert.Equal(t, int32(3), j[0].Int32())
+	assert.Equal(t, uint64(3), j[0].Uint64())
+}
+
+func TestJsonList_Append_Objects(t *testing.T) {
+	o1 := dynjson.NewJsonObject()
+	o1.SetString("name", "John")
+	o2 := dynjson.NewJsonObject()
+	o2.SetString("name", "Jane")
+
+	j := dynjson.NewJsonList(nil)
+	j.Append(o1, o2)
+	j.Append("Hello", float32(1.5), nil)
+
+	assert.Equal(t, 5, j.Len())
+	assert.Equal(t, "John", j[0].Object().String("name"))
+	assert.Equal(t, "Jane", j[1].Object().String("name"))
+	assert.JSONEq(t, `[{"name": "John"}, {"name": "Jane"}, "Hello", 1.5, null]`, j.ToString())
+
+	j.Prepend(o2, 3)
+	assert.JSONEq(t, `[{"name": "Jane"}, 3, {"name": "John"}, {"name": "Jane"}, "Hello", 1.5, null]`, j.ToString())
+}
+
+func TestJsonList_Get(t *testing.T) {
+	j, err := dynjson.ParseList(`["a", "b"]`)
+	assert.Nil(t, err)
+
+	item, ok := j.GetOk(1)
+	assert.True(t, ok)
+	assert.Equal(t, "b", item.String())
+
+	_, ok = j.GetOk(2)
+	assert.False(t, ok)
+	_, ok = j.GetOk(-1)
+	assert.False(t, ok)
+
+	assert.Equal(t, "a", j.Get(0).String())
+	assert.True(t, j.Get(5).IsNull())
+
+	assert.Equal(t, dynjson.KindString, j.Value(0).Kind())
+	assert.Equal(t, dynjson.KindMissing, j.Value(2).Kind())
+}
+
+func TestJsonList_Set(t *testing.T) {
+	j := dynjson.NewJsonList(dynjson.JsonListRaw{1, 2, 3, 4, 5, 6, 7, 8})
+
+	assert.Nil(t, j.Set(0, "a"))
+	assert.Nil(t, j.SetString(1, "b"))
+	assert.Nil(t, j.SetNumber(2, 1.5))
+	assert.Nil(t, j.SetInt64(3, 9007199254740993))
+	assert.Nil(t, j.SetBool(4, true))
+	assert.Nil(t, j.SetNull(5))
+	assert.Nil(t, j.SetObject(6, dynjson.NewJsonObject()))
+	assert.Nil(t, j.SetList(7, dynjson.NewJsonList(nil)))
+
+	assert.Equal(t, `["a","b",1.5,9007199254740993,true,null,{},[]]`, j.ToString())
+
+	assert.NotNil(t, j.Set(8, 1))
+	assert.NotNil(t, j.SetString(-1, "x"))
+}
+
+func TestJsonList_Insert(t *testing.T) {
+	j := dynjson.NewJsonList(dynjson.JsonListRaw{1, 4})
+
+	assert.Nil(t, j.Insert(1, 2, 3))
+	assert.Nil(t, j.Insert(0, 0))
+	assert.Nil(t, j.Insert(5, 5))
+
+	for index, item := range j {
+		assert.Equal(t, index, item.Int())
+	}
+
+	assert.NotNil(t, j.Insert(7, 1))
+	assert.NotNil(t, j.Insert(-1, 1))
+	assert.Equal(t, 6, j.Len())
+}
+
+func TestJsonList_Remove(t *testing.T) {
+	j := dynjson.NewJsonList(dynjson.JsonListRaw{0, 1, 2, 3})
+
+	assert.Nil(t, j.Remove(1))
+	assert.Nil(t, j.Remove(2))
+	assert.Equal(t, `[0,2]`, j.ToString())
+
+	assert.NotNil(t, j.Remove(2))
+	assert.NotNil(t, j.Remove(-1))
+}
+
+func TestJsonList_RemoveIf(t *testing.T) {
+	j, err := dynjson.ParseList(`[1, "a", 2, null, "b", 3]`)
+	assert.Nil(t, err)
+
+	removed := j.RemoveIf(func(item dynjson.JsonListItem) bool {
+		_, ok := item.Float64Ok()
+		return !ok
+	})
+
+	assert.Equal(t, 3, removed)
+	assert.Equal(t, `[1,2,3]`, j.ToString())
+}
+
+func TestJsonList_Remove_Shared(t *testing.T) {
+	l := dynjson.NewJsonList(dynjson.JsonListRaw{1, 2, 3})
+	obj := dynjson.NewJsonObject()
+	obj.SetList("l", l)
+
+	assert.Nil(t, l.Remove(0))
+	assert.Equal(t, 2, l.RemoveIf(func(item dynjson.JsonListItem) bool { return true }))
+
+	assert.Equal(t, `[]`, l.ToString())
+	assert.Equal(t, `{"l":[1,2,3]}`, obj.ToString())
+}
+
+func TestJsonList_Slice(t *testing.T) {
+	j := dynjson.NewJsonList(dynjson.JsonListRaw{0, 1, 2, 3})
+
+	s, err := j.Slice(1, 3)
+	assert.Nil(t, err)
+	assert.Equal(t, `[1,2]`, s.ToString())
+
+	assert.Nil(t, s.Set(0, "x"))
+	assert.Equal(t, `[0,1,2,3]`, j.ToString())
+
+	s, err = j.Slice(4, 4)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, s.Len())
+
+	_, err = j.Slice(2, 1)
+	assert.NotNil(t, err)
+	_, err = j.Slice(0, 5)
+	assert.NotNil(t, err)
+	_, err = j.Slice(-1, 2)
+	assert.NotNil(t, err)
+}
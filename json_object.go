@@ -11,18 +11,36 @@ func NewJsonObject() JsonObject {
 }
 
 // ParseObject parses a string containing a json and returns a json object or an error
-func ParseObject(jsonString string) (JsonObject, error) {
-	obj := JsonObject{}
-	err := json.Unmarshal([]byte(jsonString), &obj)
+func ParseObject(jsonString string, opts ...ParseOption) (JsonObject, error) {
+	raw, err := parseBytes([]byte(jsonString), newParseConfig(opts))
 	if err != nil {
 		return nil, err
 	}
 
-	return obj, nil
+	return toObject(raw)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (j JsonObject) MarshalJSON() ([]byte, error) {
+	if j == nil {
+		return []byte("null"), nil
+	}
+	return json.Marshal(map[string]interface{}(j))
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (j *JsonObject) UnmarshalJSON(data []byte) error {
+	raw := map[string]interface{}{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	*j = raw
+	return nil
 }
 
 // ToString returns the JSON data as string.
-// Returns an empty string, when an error occurred.
+// Returns an empty string, when an error occurred. Use Marshal to get the error or to change the output.
 func (j JsonObject) ToString() string {
 	data, _ := json.Marshal(&j)
 	return string(data)
@@ -34,12 +52,21 @@ func (j JsonObject) Has(field string) bool {
 	return ok
 }
 
+// IsNull checks if a json object contains a specific field, which is explicitly set to null.
+// Fields which don't exist are not null.
+func (j JsonObject) IsNull(field string) bool {
+	return j.Value(field).IsNull()
+}
+
+// Value returns a field as Value. When the field does not exist, the value is missing.
+func (j JsonObject) Value(field string) Value {
+	data, ok := j[field]
+	return Value{data: data, exists: ok}
+}
+
 // ObjectOk returns a field which contains an object and return it with true, when it matches.
 func (j JsonObject) ObjectOk(field string) (JsonObject, bool) {
-	if val, ok := j[field]; ok {
-		return convToObject(val)
-	}
-	return nil, false
+	return j.Value(field).ObjectOk()
 }
 
 // Object returns an object inside the current object
@@ -50,11 +77,7 @@ func (j JsonObject) Object(field string) JsonObject {
 
 // ListOk returns a list / array from the json object and a boolean which indicates, whether the result is ok.
 func (j JsonObject) ListOk(field string) (JsonList, bool) {
-	if val, ok := j[field]; ok {
-		return convToList(val)
-	}
-
-	return nil, false
+	return j.Value(field).ListOk()
 }
 
 // List returns a list / object from the json object.
@@ -65,10 +88,7 @@ func (j JsonObject) List(field string) JsonList {
 
 // StringOk returns a string from the json object and a boolean which indicates, whether the result is ok.
 func (j JsonObject) StringOk(field string) (string, bool) {
-	if val, ok := j[field]; ok {
-		return convToString(val)
-	}
-	return "", false
+	return j.Value(field).StringOk()
 }
 
 func (j JsonObject) StringDefault(field, def string) string {
@@ -87,10 +107,7 @@ func (j JsonObject) String(field string) string {
 // Float64Ok returns a number from the json object, converted to float64 and a boolean which indicates, whether the
 // result is ok.
 func (j JsonObject) Float64Ok(field string) (float64, bool) {
-	if val, ok := j[field]; ok {
-		return convToFloat64(val)
-	}
-	return 0, false
+	return j.Value(field).Float64Ok()
 }
 
 func (j JsonObject) Float64Default(field string, def float64) float64 {
@@ -109,8 +126,7 @@ func (j JsonObject) Float64(field string) float64 {
 // Float32Ok returns a number from the json object, converted to float32 and a boolean which indicates, whether the
 // result is ok.
 func (j JsonObject) Float32Ok(field string) (float32, bool) {
-	val, ok := j.Float64Ok(field)
-	return float32(val), ok
+	return j.Value(field).Float32Ok()
 }
 
 func (j JsonObject) Float32Default(field string, def float32) float32 {
@@ -127,10 +143,9 @@ func (j JsonObject) Float32(field string) float32 {
 }
 
 // IntOk returns a number from the json object, converted to int and a boolean which indicates, whether the
-// result is ok.
+// result is ok. Fractional digits are truncated, use Int64Ok to reject them.
 func (j JsonObject) IntOk(field string) (int, bool) {
-	val, ok := j.Float64Ok(field)
-	return int(val), ok
+	return j.Value(field).IntOk()
 }
 
 func (j JsonObject) IntDefault(field string, def int) int {
@@ -146,11 +161,65 @@ func (j JsonObject) Int(field string) int {
 	return val
 }
 
-func (j JsonObject) BoolOk(field string) (bool, bool) {
-	if val, ok := j[field]; ok {
-		return convToBool(val)
+// Int64Ok returns a number from the json object, converted to int64 and a boolean which indicates, whether the
+// result is ok. Fractional numbers and numbers which don't fit into an int64 are not ok.
+func (j JsonObject) Int64Ok(field string) (int64, bool) {
+	return j.Value(field).Int64Ok()
+}
+
+func (j JsonObject) Int64Default(field string, def int64) int64 {
+	val, ok := j.Int64Ok(field)
+	if ok {
+		return val
+	}
+	return def
+}
+
+func (j JsonObject) Int64(field string) int64 {
+	val, _ := j.Int64Ok(field)
+	return val
+}
+
+// Int32Ok returns a number from the json object, converted to int32 and a boolean which indicates, whether the
+// result is ok. Fractional numbers and numbers which don't fit into an int32 are not ok.
+func (j JsonObject) Int32Ok(field string) (int32, bool) {
+	return j.Value(field).Int32Ok()
+}
+
+func (j JsonObject) Int32Default(field string, def int32) int32 {
+	val, ok := j.Int32Ok(field)
+	if ok {
+		return val
+	}
+	return def
+}
+
+func (j JsonObject) Int32(field string) int32 {
+	val, _ := j.Int32Ok(field)
+	return val
+}
+
+// Uint64Ok returns a number from the json object, converted to uint64 and a boolean which indicates, whether the
+// result is ok. Fractional, negative and too large numbers are not ok.
+func (j JsonObject) Uint64Ok(field string) (uint64, bool) {
+	return j.Value(field).Uint64Ok()
+}
+
+func (j JsonObject) Uint64Default(field string, def uint64) uint64 {
+	val, ok := j.Uint64Ok(field)
+	if ok {
+		return val
 	}
-	return false, false
+	return def
+}
+
+func (j JsonObject) Uint64(field string) uint64 {
+	val, _ := j.Uint64Ok(field)
+	return val
+}
+
+func (j JsonObject) BoolOk(field string) (bool, bool) {
+	return j.Value(field).BoolOk()
 }
 
 func (j JsonObject) BoolDefault(field string, def bool) bool {
@@ -166,10 +235,12 @@ func (j JsonObject) Bool(field string) bool {
 	return val
 }
 
+// SetObject stores the object by reference, so later changes of value affect j, too. Use Clone to store a copy.
 func (j JsonObject) SetObject(field string, value JsonObject) {
 	j[field] = value
 }
 
+// SetList stores the list by reference. Use Clone to store a copy.
 func (j JsonObject) SetList(field string, value JsonList) {
 	j[field] = value
 }
@@ -181,6 +252,12 @@ func (j JsonObject) SetNumber(field string, value float64) {
 	j[field] = value
 }
 
+// SetInt64 writes an integer into the json object.
+// In contrast to SetNumber, integers above 2^53 are serialised without losing precision.
+func (j JsonObject) SetInt64(field string, value int64) {
+	j[field] = value
+}
+
 func (j JsonObject) SetString(field, value string) {
 	j[field] = value
 }
@@ -189,6 +266,11 @@ func (j JsonObject) SetBool(field string, value bool) {
 	j[field] = value
 }
 
+// SetNull sets a field explicitly to null. Use delete to remove a field completely.
+func (j JsonObject) SetNull(field string) {
+	j[field] = nil
+}
+
 // Chain receives multiple field names, which represent a json object hierarchy.
 // E.g. when you have an object, containing another object in field "a", which contains another object in field "b",
 // you can use Chain("a", "b") to walk down the hierarchy.
@@ -205,3 +287,54 @@ func (j JsonObject) Chain(field ...string) JsonObject {
 
 	return result
 }
+
+// ObjectErr works like ObjectOk, but returns an *Error with the path of the field, which tells why it couldn't be read.
+func (j JsonObject) ObjectErr(field string) (JsonObject, error) {
+	val, err := j.Value(field).ObjectErr()
+	return val, withPath(err, FormatPointer(field))
+}
+
+func (j JsonObject) ListErr(field string) (JsonList, error) {
+	val, err := j.Value(field).ListErr()
+	return val, withPath(err, FormatPointer(field))
+}
+
+func (j JsonObject) StringErr(field string) (string, error) {
+	val, err := j.Value(field).StringErr()
+	return val, withPath(err, FormatPointer(field))
+}
+
+func (j JsonObject) Float64Err(field string) (float64, error) {
+	val, err := j.Value(field).Float64Err()
+	return val, withPath(err, FormatPointer(field))
+}
+
+func (j JsonObject) Float32Err(field string) (float32, error) {
+	val, err := j.Value(field).Float32Err()
+	return val, withPath(err, FormatPointer(field))
+}
+
+func (j JsonObject) IntErr(field string) (int, error) {
+	val, err := j.Value(field).IntErr()
+	return val, withPath(err, FormatPointer(field))
+}
+
+func (j JsonObject) Int64Err(field string) (int64, error) {
+	val, err := j.Value(field).Int64Err()
+	return val, withPath(err, FormatPointer(field))
+}
+
+func (j JsonObject) Int32Err(field string) (int32, error) {
+	val, err := j.Value(field).Int32Err()
+	return val, withPath(err, FormatPointer(field))
+}
+
+func (j JsonObject) Uint64Err(field string) (uint64, error) {
+	val, err := j.Value(field).Uint64Err()
+	return val, withPath(err, FormatPointer(field))
+}
+
+func (j JsonObject) BoolErr(field string) (bool, error) {
+	val, err := j.Value(field).BoolErr()
+	return val, withPath(err, FormatPointer(field))
+}
@@ -1,6 +1,7 @@
 package dynjson_test
 
 import (
+	"encoding/json"
 	"testing"
 
 	"github.com/go-schild/dynjson"
@@ -323,3 +324,215 @@ func TestJsonObject_Chain(t *testing.T) {
 	j3 := j1.Chain("a", "b", "c") // c is not an object
 	assert.Nil(t, j3)
 }
+
+func TestJsonObject_MarshalJSON(t *testing.T) {
+	testData := []string{
+		`{}`,
+		`{"a": 1, "b": "Hello", "c": true, "d": null}`,
+		`{"a": {"b": {"c": 5}}}`,
+		`{"a": [1, 2, 3]}`,
+		`{"a": [{"b": 1}, {"c": [2, 3]}]}`,
+		`{"a": [[1, 2], [{"b": [[3]]}]]}`,
+	}
+
+	for _, data := range testData {
+		j, err := dynjson.ParseObject(data)
+		assert.Nil(t, err)
+
+		b, err := json.Marshal(j)
+		assert.Nil(t, err)
+		assert.JSONEq(t, data, string(b))
+		assert.JSONEq(t, data, j.ToString())
+	}
+}
+
+func TestJsonObject_UnmarshalJSON(t *testing.T) {
+	testData := []string{
+		`{}`,
+		`{"a": 1, "b": "Hello", "c": true, "d": null}`,
+		`{"a": {"b": {"c": 5}}}`,
+		`{"a": [{"b": 1}, {"c": [2, 3]}]}`,
+		`{"a": [[1, 2], [{"b": [[3]]}]]}`,
+	}
+
+	for _, data := range testData {
+		var j dynjson.JsonObject
+		err := json.Unmarshal([]byte(data), &j)
+		assert.Nil(t, err)
+		assert.JSONEq(t, data, j.ToString())
+	}
+
+	var j dynjson.JsonObject
+	assert.NotNil(t, json.Unmarshal([]byte(`[1, 2]`), &j))
+}
+
+func TestJsonObject_MarshalJSON_Created(t *testing.T) {
+	inner := dynjson.NewJsonObject()
+	inner.SetNumber("b", 1)
+
+	list := dynjson.NewJsonList(dynjson.JsonListRaw{inner, dynjson.NewJsonList(dynjson.JsonListRaw{"c"})})
+
+	j := dynjson.NewJsonObject()
+	j.SetObject("inner", inner)
+	j.SetList("list", list)
+
+	assert.JSONEq(t, `{"inner": {"b": 1}, "list": [{"b": 1}, ["c"]]}`, j.ToString())
+}
+
+func TestJsonObject_MarshalJSON_Struct(t *testing.T) {
+	type container struct {
+		Name    string               `json:"name"`
+		Objects []dynjson.JsonObject `json:"objects"`
+		Lists   []dynjson.JsonList   `json:"lists"`
+	}
+
+	const testData = `{"name": "test", "objects": [{"a": [1]}, {"b": {"c": [2]}}], "lists": [[{"d": 3}], [[4]]]}`
+
+	var c container
+	err := json.Unmarshal([]byte(testData), &c)
+	assert.Nil(t, err)
+
+	assert.Equal(t, 1, c.Objects[0].List("a")[0].Int())
+	assert.Equal(t, 3, c.Lists[0][0].Object().Int("d"))
+	assert.Equal(t, 4, c.Lists[1][0].List()[0].Int())
+
+	b, err := json.Marshal(c)
+	assert.Nil(t, err)
+	assert.JSONEq(t, testData, string(b))
+}
+
+func TestJsonObject_IsNull(t *testing.T) {
+	const testData = `{"a": null, "b": 0, "c": ""}`
+
+	j, err := dynjson.ParseObject(testData)
+	assert.Nil(t, err)
+
+	assert.True(t, j.Has("a"))
+	assert.True(t, j.IsNull("a"))
+	assert.False(t, j.IsNull("b"))
+	assert.False(t, j.IsNull("c"))
+	assert.False(t, j.Has("d"))
+	assert.False(t, j.IsNull("d"))
+
+	assert.Equal(t, dynjson.KindNull, j.Value("a").Kind())
+	assert.Equal(t, dynjson.KindMissing, j.Value("d").Kind())
+}
+
+func TestJsonObject_SetNull(t *testing.T) {
+	const testData = `{"a": "Hello"}`
+
+	j, err := dynjson.ParseObject(testData)
+	assert.Nil(t, err)
+
+	j.SetNull("a")
+	j.SetNull("b")
+
+	assert.True(t, j.IsNull("a"))
+	assert.True(t, j.IsNull("b"))
+	assert.JSONEq(t, `{"a": null, "b": null}`, j.ToString())
+
+	j2, err := dynjson.ParseObject(j.ToString())
+	assert.Nil(t, err)
+	assert.True(t, j2.IsNull("a"))
+	assert.True(t, j2.IsNull("b"))
+}
+
+func TestParseObject_UseNumber(t *testing.T) {
+	const testData = `{"id": 1234567890123456789, "pi": 3.14, "list": [9007199254740993]}`
+
+	j, err := dynjson.ParseObject(testData, dynjson.UseNumber())
+	assert.Nil(t, err)
+
+	assert.Equal(t, int64(1234567890123456789), j.Int64("id"))
+	assert.Equal(t, uint64(1234567890123456789), j.Uint64("id"))
+	assert.Equal(t, float64(3.14), j.Float64("pi"))
+	assert.Equal(t, int64(9007199254740993), j.List("list")[0].Int64())
+	assert.JSONEq(t, testData, j.ToString())
+	assert.Contains(t, j.ToString(), "1234567890123456789")
+
+	_, err = dynjson.ParseObject(`{"a": 1} {}`, dynjson.UseNumber())
+	assert.NotNil(t, err)
+
+	_, err = dynjson.ParseObject(``, dynjson.UseNumber())
+	assert.NotNil(t, err)
+}
+
+func TestJsonObject_Int64Ok(t *testing.T) {
+	const testData = `{"a": 3, "b": 3.9, "c": "3", "d": 1e3, "e": 1e300, "f": -4}`
+
+	for _, opts := range [][]dynjson.ParseOption{nil, {dynjson.UseNumber()}} {
+		j, err := dynjson.ParseObject(testData, opts...)
+		assert.Nil(t, err)
+
+		a, ok := j.Int64Ok("a")
+		assert.True(t, ok)
+		assert.Equal(t, int64(3), a)
+
+		a, ok = j.Int64Ok("b")
+		assert.False(t, ok)
+		assert.Equal(t, int64(0), a)
+
+		_, ok = j.Int64Ok("c")
+		assert.False(t, ok)
+
+		a, ok = j.Int64Ok("d")
+		assert.True(t, ok)
+		assert.Equal(t, int64(1000), a)
+
+		_, ok = j.Int64Ok("e")
+		assert.False(t, ok)
+
+		assert.Equal(t, int64(-4), j.Int64("f"))
+		assert.Equal(t, int64(-1), j.Int64Default("b", -1))
+	}
+}
+
+func TestJsonObject_Int32Ok(t *testing.T) {
+	const testData = `{"a": 2147483647, "b": 2147483648, "c": -2147483648, "d": 1.5}`
+
+	j, err := dynjson.ParseObject(testData)
+	assert.Nil(t, err)
+
+	a, ok := j.Int32Ok("a")
+	assert.True(t, ok)
+	assert.Equal(t, int32(2147483647), a)
+
+	_, ok = j.Int32Ok("b")
+	assert.False(t, ok)
+
+	assert.Equal(t, int32(-2147483648), j.Int32("c"))
+	assert.Equal(t, int32(0), j.Int32("d"))
+	assert.Equal(t, int32(-1), j.Int32Default("d", -1))
+}
+
+func TestJsonObject_Uint64Ok(t *testing.T) {
+	const testData = `{"a": 18446744073709551615, "b": 18446744073709551616, "c": -1, "d": 2.5}`
+
+	j, err := dynjson.ParseObject(testData, dynjson.UseNumber())
+	assert.Nil(t, err)
+
+	a, ok := j.Uint64Ok("a")
+	assert.True(t, ok)
+	assert.Equal(t, uint64(18446744073709551615), a)
+
+	_, ok = j.Uint64Ok("b")
+	assert.False(t, ok)
+
+	_, ok = j.Uint64Ok("c")
+	assert.False(t, ok)
+
+	assert.Equal(t, uint64(0), j.Uint64("d"))
+	assert.Equal(t, uint64(7), j.Uint64Default("d", 7))
+}
+
+func TestJsonObject_SetInt64(t *testing.T) {
+	j := dynjson.NewJsonObject()
+	j.SetInt64("id", 9007199254740993)
+
+	assert.Equal(t, int64(9007199254740993), j.Int64("id"))
+	assert.Equal(t, `{"id":9007199254740993}`, j.ToString())
+
+	j2, err := dynjson.ParseObject(j.ToString(), dynjson.UseNumber())
+	assert.Nil(t, err)
+	assert.Equal(t, int64(9007199254740993), j2.Int64("id"))
+}
@@ -0,0 +1,863 @@
+package dynjson
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// JsonPath is a compiled JSONPath expression like `$.orders[*].lines[?(@.qty > 1)].sku`.
+// It can be reused for many documents and is safe for concurrent use.
+//
+// Supported are the root `$`, member names (`.name`, `['name']`), wildcards (`.*`, `[*]`), recursive descent (`..`),
+// indices (`[0]`, `[-1]`), slices (`[start:end:step]`), unions (`[0,2,'name']`) and filters (`[?(@.price < 10)]`).
+// Filters support comparisons (==, !=, <, <=, >, >=), the boolean operators &&, || and !, parentheses,
+// existence tests (`[?(@.isbn)]`) and literals (numbers, strings, true, false, null).
+type JsonPath struct {
+	expr     string
+	segments []pathSegment
+}
+
+// PathMatch is a value found by a JsonPath.
+type PathMatch struct {
+	// Path is the normalized path of the value, e.g. `$['orders'][0]['sku']`.
+	Path string
+	// Pointer is the JSON pointer of the value, e.g. "/orders/0/sku".
+	Pointer string
+	// Value is the matched value.
+	Value Value
+}
+
+// JsonPathError is returned when a JSONPath expression can't be compiled.
+type JsonPathError struct {
+	Expr   string
+	Offset int
+	Reason string
+}
+
+func (e *JsonPathError) Error() string {
+	return fmt.Sprintf("dynjson: jsonpath %q: offset %d: %s", e.Expr, e.Offset, e.Reason)
+}
+
+// CompileJsonPath compiles a JSONPath expression.
+func CompileJsonPath(expr string) (*JsonPath, error) {
+	p := &pathParser{expr: expr}
+
+	p.skipSpace()
+	if !p.consume("$") {
+		return nil, p.errorf("expression must start with '$'")
+	}
+
+	segments, err := p.parseSegments()
+	if err != nil {
+		return nil, err
+	}
+
+	p.skipSpace()
+	if p.pos < len(p.expr) {
+		return nil, p.errorf("unexpected %q", p.expr[p.pos:])
+	}
+
+	return &JsonPath{expr: expr, segments: segments}, nil
+}
+
+// MustCompileJsonPath is like CompileJsonPath, but panics when the expression can't be compiled.
+func MustCompileJsonPath(expr string) *JsonPath {
+	path, err := CompileJsonPath(expr)
+	if err != nil {
+		panic(err)
+	}
+	return path
+}
+
+// String returns the source of the expression.
+func (p *JsonPath) String() string {
+	return p.expr
+}
+
+// Query returns all values of doc matching the expression in document order.
+// Doc can be a JsonObject, a JsonList, a Value or any raw json value.
+func (p *JsonPath) Query(doc interface{}) []PathMatch {
+	root := normalize(doc)
+	nodes := p.eval(root, root)
+
+	matches := make([]PathMatch, 0, len(nodes))
+	for _, n := range nodes {
+		matches = append(matches, PathMatch{
+			Path:    n.location.normalizedPath(),
+			Pointer: n.location.pointer(),
+			Value:   Value{data: n.data, exists: true},
+		})
+	}
+
+	return matches
+}
+
+// Query compiles the JSONPath expression and returns all matching values of the json object.
+func (j JsonObject) Query(expr string) ([]PathMatch, error) {
+	path, err := CompileJsonPath(expr)
+	if err != nil {
+		return nil, err
+	}
+	return path.Query(j), nil
+}
+
+// Query compiles the JSONPath expression and returns all matching values of the json list.
+func (j JsonList) Query(expr string) ([]PathMatch, error) {
+	path, err := CompileJsonPath(expr)
+	if err != nil {
+		return nil, err
+	}
+	return path.Query(j), nil
+}
+
+func (p *JsonPath) eval(node, root interface{}) []pathNode {
+	return evalSegments(p.segments, []pathNode{{data: node}}, root)
+}
+
+func evalSegments(segments []pathSegment, current []pathNode, root interface{}) []pathNode {
+	for _, segment := range segments {
+		var next []pathNode
+		for _, n := range current {
+			if segment.descendant {
+				for _, d := range descendants(n, nil) {
+					next = segment.apply(d, root, next)
+				}
+			} else {
+				next = segment.apply(n, root, next)
+			}
+		}
+		current = next
+	}
+
+	return current
+}
+
+// pathLocation is the location of a node inside the document. Its entries are member names (string) or indices (int).
+type pathLocation []interface{}
+
+func (l pathLocation) child(segment interface{}) pathLocation {
+	result := make(pathLocation, len(l), len(l)+1)
+	copy(result, l)
+	return append(result, segment)
+}
+
+func (l pathLocation) normalizedPath() string {
+	var sb strings.Builder
+	sb.WriteByte('$')
+	for _, segment := range l {
+		switch s := segment.(type) {
+		case string:
+			sb.WriteString("['")
+			writePathString(&sb, s)
+			sb.WriteString("']")
+		case int:
+			sb.WriteByte('[')
+			sb.WriteString(strconv.Itoa(s))
+			sb.WriteByte(']')
+		}
+	}
+	return sb.String()
+}
+
+func (l pathLocation) pointer() string {
+	segments := make([]string, 0, len(l))
+	for _, segment := range l {
+		switch s := segment.(type) {
+		case string:
+			segments = append(segments, s)
+		case int:
+			segments = append(segments, strconv.Itoa(s))
+		}
+	}
+	return FormatPointer(segments...)
+}
+
+func writePathString(sb *strings.Builder, s string) {
+	for _, r := range s {
+		switch r {
+		case '\'':
+			sb.WriteString(`\'`)
+		case '\\':
+			sb.WriteString(`\\`)
+		case '\b':
+			sb.WriteString(`\b`)
+		case '\f':
+			sb.WriteString(`\f`)
+		case '\n':
+			sb.WriteString(`\n`)
+		case '\r':
+			sb.WriteString(`\r`)
+		case '\t':
+			sb.WriteString(`\t`)
+		default:
+			if r < 0x20 {
+				fmt.Fprintf(sb, `\u%04x`, r)
+			} else {
+				sb.WriteRune(r)
+			}
+		}
+	}
+}
+
+type pathNode struct {
+	data     interface{}
+	location pathLocation
+}
+
+// children returns the member values of an object or the elements of a list. Members of an *OrderedObject are
+// returned in their order, members of other objects in sorted key order.
+func children(n pathNode) []pathNode {
+	if obj, ok := convToObject(n.data); ok {
+		result := make([]pathNode, 0, len(obj))
+		for _, key := range memberKeys(n.data) {
+			result = append(result, pathNode{data: obj[key], location: n.location.child(key)})
+		}
+		return result
+	}
+
+	if length, ok := listLen(n.data); ok {
+		result := make([]pathNode, 0, length)
+		for i := 0; i < length; i++ {
+			result = append(result, pathNode{data: listGet(n.data, i), location: n.location.child(i)})
+		}
+		return result
+	}
+
+	return nil
+}
+
+// descendants returns the node itself and all nodes below it in document order.
+func descendants(n pathNode, result []pathNode) []pathNode {
+	result = append(result, n)
+	for _, child := range children(n) {
+		result = descendants(child, result)
+	}
+	return result
+}
+
+type pathSegment struct {
+	descendant bool
+	selectors  []pathSelector
+}
+
+func (s pathSegment) apply(n pathNode, root interface{}, result []pathNode) []pathNode {
+	for _, selector := range s.selectors {
+		result = selector.apply(n, root, result)
+	}
+	return result
+}
+
+type pathSelector interface {
+	apply(n pathNode, root interface{}, result []pathNode) []pathNode
+}
+
+type nameSelector struct {
+	name string
+}
+
+func (s nameSelector) apply(n pathNode, root interface{}, result []pathNode) []pathNode {
+	if obj, ok := convToObject(n.data); ok {
+		if val, ok := obj[s.name]; ok {
+			result = append(result, pathNode{data: val, location: n.location.child(s.name)})
+		}
+	}
+	return result
+}
+
+type wildcardSelector struct{}
+
+func (s wildcardSelector) apply(n pathNode, root interface{}, result []pathNode) []pathNode {
+	return append(result, children(n)...)
+}
+
+type indexSelector struct {
+	index int
+}
+
+func (s indexSelector) apply(n pathNode, root interface{}, result []pathNode) []pathNode {
+	length, ok := listLen(n.data)
+	if !ok {
+		return result
+	}
+
+	index := s.index
+	if index < 0 {
+		index += length
+	}
+	if index < 0 || index >= length {
+		return result
+	}
+
+	return append(result, pathNode{data: listGet(n.data, index), location: n.location.child(index)})
+}
+
+type sliceSelector struct {
+	start, end *int
+	step       int
+}
+
+func (s sliceSelector) apply(n pathNode, root interface{}, result []pathNode) []pathNode {
+	length, ok := listLen(n.data)
+	if !ok || s.step == 0 {
+		return result
+	}
+
+	normalizeBound := func(bound *int, def int) int {
+		if bound == nil {
+			return def
+		}
+		if *bound < 0 {
+			return *bound + length
+		}
+		return *bound
+	}
+
+	if s.step > 0 {
+		lower := clamp(normalizeBound(s.start, 0), 0, length)
+		upper := clamp(normalizeBound(s.end, length), 0, length)
+		for i := lower; i < upper; i += s.step {
+			result = append(result, pathNode{data: listGet(n.data, i), location: n.location.child(i)})
+		}
+	} else {
+		upper := clamp(normalizeBound(s.start, length-1), -1, length-1)
+		lower := clamp(normalizeBound(s.end, -length-1), -1, length-1)
+		for i := upper; i > lower; i += s.step {
+			result = append(result, pathNode{data: listGet(n.data, i), location: n.location.child(i)})
+		}
+	}
+
+	return result
+}
+
+func clamp(val, min, max int) int {
+	if val < min {
+		return min
+	}
+	if val > max {
+		return max
+	}
+	return val
+}
+
+type filterSelector struct {
+	expr filterExpr
+}
+
+func (s filterSelector) apply(n pathNode, root interface{}, result []pathNode) []pathNode {
+	for _, child := range children(n) {
+		if s.expr.test(child.data, root) {
+			result = append(result, child)
+		}
+	}
+	return result
+}
+
+// filterExpr is a logical expression inside a filter selector.
+type filterExpr interface {
+	test(current, root interface{}) bool
+}
+
+type orExpr struct {
+	left, right filterExpr
+}
+
+func (e orExpr) test(current, root interface{}) bool {
+	return e.left.test(current, root) || e.right.test(current, root)
+}
+
+type andExpr struct {
+	left, right filterExpr
+}
+
+func (e andExpr) test(current, root interface{}) bool {
+	return e.left.test(current, root) && e.right.test(current, root)
+}
+
+type notExpr struct {
+	expr filterExpr
+}
+
+func (e notExpr) test(current, root interface{}) bool {
+	return !e.expr.test(current, root)
+}
+
+// existsExpr tests whether a query selects at least one node.
+type existsExpr struct {
+	query queryExpr
+}
+
+func (e existsExpr) test(current, root interface{}) bool {
+	return len(e.query.eval(current, root)) > 0
+}
+
+type compareExpr struct {
+	left, right operand
+	op          string
+}
+
+func (e compareExpr) test(current, root interface{}) bool {
+	left, leftOk := e.left.value(current, root)
+	right, rightOk := e.right.value(current, root)
+
+	switch e.op {
+	case "==":
+		return compareEqual(left, leftOk, right, rightOk)
+	case "!=":
+		return !compareEqual(left, leftOk, right, rightOk)
+	case "<":
+		return compareLess(left, leftOk, right, rightOk)
+	case ">":
+		return compareLess(right, rightOk, left, leftOk)
+	case "<=":
+		return compareLess(left, leftOk, right, rightOk) || compareEqual(left, leftOk, right, rightOk)
+	case ">=":
+		return compareLess(right, rightOk, left, leftOk) || compareEqual(left, leftOk, right, rightOk)
+	}
+
+	return false
+}
+
+// compareEqual compares two operands. Two missing operands are equal, a missing and an existing one are not.
+func compareEqual(left interface{}, leftOk bool, right interface{}, rightOk bool) bool {
+	if !leftOk || !rightOk {
+		return leftOk == rightOk
+	}
+	return nodesEqual(left, right)
+}
+
+// compareLess compares numbers by value and strings by their code points. Other types can't be ordered.
+func compareLess(left interface{}, leftOk bool, right interface{}, rightOk bool) bool {
+	if !leftOk || !rightOk {
+		return false
+	}
+
+	if kindOf(left) == KindNumber && kindOf(right) == KindNumber {
+		a, _ := convToFloat64(normalize(left))
+		b, _ := convToFloat64(normalize(right))
+		return a < b
+	}
+
+	a, okA := left.(string)
+	b, okB := right.(string)
+	return okA && okB && a < b
+}
+
+// operand is one side of a comparison.
+type operand interface {
+	value(current, root interface{}) (interface{}, bool)
+}
+
+type literalExpr struct {
+	data interface{}
+}
+
+func (e literalExpr) value(current, root interface{}) (interface{}, bool) {
+	return e.data, true
+}
+
+// queryExpr is a query relative to the current node (`@`) or to the root (`$`) inside a filter.
+type queryExpr struct {
+	absolute bool
+	segments []pathSegment
+}
+
+func (e queryExpr) eval(current, root interface{}) []pathNode {
+	start := current
+	if e.absolute {
+		start = root
+	}
+	return evalSegments(e.segments, []pathNode{{data: start}}, root)
+}
+
+// value returns the result of the query, when it selects exactly one node.
+func (e queryExpr) value(current, root interface{}) (interface{}, bool) {
+	nodes := e.eval(current, root)
+	if len(nodes) != 1 {
+		return nil, false
+	}
+	return nodes[0].data, true
+}
+
+type pathParser struct {
+	expr string
+	pos  int
+}
+
+func (p *pathParser) errorf(format string, args ...interface{}) error {
+	return &JsonPathError{Expr: p.expr, Offset: p.pos, Reason: fmt.Sprintf(format, args...)}
+}
+
+func (p *pathParser) skipSpace() {
+	for p.pos < len(p.expr) && strings.IndexByte(" \t\n\r", p.expr[p.pos]) >= 0 {
+		p.pos++
+	}
+}
+
+func (p *pathParser) peek(s string) bool {
+	return strings.HasPrefix(p.expr[p.pos:], s)
+}
+
+func (p *pathParser) consume(s string) bool {
+	if p.peek(s) {
+		p.pos += len(s)
+		return true
+	}
+	return false
+}
+
+// parseSegments parses child and descendant segments until something else follows.
+func (p *pathParser) parseSegments() ([]pathSegment, error) {
+	var segments []pathSegment
+
+	for {
+		start := p.pos
+		p.skipSpace()
+
+		switch {
+		case p.consume(".."):
+			segment := pathSegment{descendant: true}
+			if p.peek("[") {
+				selectors, err := p.parseBracket()
+				if err != nil {
+					return nil, err
+				}
+				segment.selectors = selectors
+			} else {
+				selector, err := p.parseDotSelector()
+				if err != nil {
+					return nil, err
+				}
+				segment.selectors = []pathSelector{selector}
+			}
+			segments = append(segments, segment)
+		case p.consume("."):
+			selector, err := p.parseDotSelector()
+			if err != nil {
+				return nil, err
+			}
+			segments = append(segments, pathSegment{selectors: []pathSelector{selector}})
+		case p.peek("["):
+			selectors, err := p.parseBracket()
+			if err != nil {
+				return nil, err
+			}
+			segments = append(segments, pathSegment{selectors: selectors})
+		default:
+			p.pos = start
+			return segments, nil
+		}
+	}
+}
+
+func (p *pathParser) parseDotSelector() (pathSelector, error) {
+	if p.consume("*") {
+		return wildcardSelector{}, nil
+	}
+
+	start := p.pos
+	for p.pos < len(p.expr) {
+		r, size := utf8.DecodeRuneInString(p.expr[p.pos:])
+		if r == '_' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || r >= 0x80 || (p.pos > start && r >= '0' && r <= '9') {
+			p.pos += size
+			continue
+		}
+		break
+	}
+
+	if p.pos == start {
+		return nil, p.errorf("expected member name or '*'")
+	}
+	return nameSelector{name: p.expr[start:p.pos]}, nil
+}
+
+// parseBracket parses a bracketed selection like `['a', 0, 1:3, ?(@.b)]`.
+func (p *pathParser) parseBracket() ([]pathSelector, error) {
+	p.consume("[")
+
+	var selectors []pathSelector
+	for {
+		p.skipSpace()
+		selector, err := p.parseSelector()
+		if err != nil {
+			return nil, err
+		}
+		selectors = append(selectors, selector)
+
+		p.skipSpace()
+		if p.consume("]") {
+			return selectors, nil
+		}
+		if !p.consume(",") {
+			return nil, p.errorf("expected ',' or ']'")
+		}
+	}
+}
+
+func (p *pathParser) parseSelector() (pathSelector, error) {
+	switch {
+	case p.consume("*"):
+		return wildcardSelector{}, nil
+	case p.peek("'") || p.peek(`"`):
+		name, err := p.parseString()
+		if err != nil {
+			return nil, err
+		}
+		return nameSelector{name: name}, nil
+	case p.consume("?"):
+		p.skipSpace()
+		expr, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		return filterSelector{expr: expr}, nil
+	}
+
+	return p.parseIndexOrSlice()
+}
+
+func (p *pathParser) parseIndexOrSlice() (pathSelector, error) {
+	var bounds [3]*int
+	colons := 0
+
+	for {
+		p.skipSpace()
+		if p.pos < len(p.expr) && (p.expr[p.pos] == '-' || (p.expr[p.pos] >= '0' && p.expr[p.pos] <= '9')) {
+			n, err := p.parseInt()
+			if err != nil {
+				return nil, err
+			}
+			bounds[colons] = &n
+			p.skipSpace()
+		}
+
+		if colons < 2 && p.consume(":") {
+			colons++
+			continue
+		}
+		break
+	}
+
+	if colons == 0 {
+		if bounds[0] == nil {
+			return nil, p.errorf("expected selector")
+		}
+		return indexSelector{index: *bounds[0]}, nil
+	}
+
+	step := 1
+	if bounds[2] != nil {
+		step = *bounds[2]
+	}
+	return sliceSelector{start: bounds[0], end: bounds[1], step: step}, nil
+}
+
+func (p *pathParser) parseInt() (int, error) {
+	start := p.pos
+	p.consume("-")
+	for p.pos < len(p.expr) && p.expr[p.pos] >= '0' && p.expr[p.pos] <= '9' {
+		p.pos++
+	}
+
+	n, err := strconv.Atoi(p.expr[start:p.pos])
+	if err != nil {
+		p.pos = start
+		return 0, p.errorf("invalid integer")
+	}
+	return n, nil
+}
+
+func (p *pathParser) parseString() (string, error) {
+	quote := p.expr[p.pos]
+	p.pos++
+
+	var sb strings.Builder
+	for p.pos < len(p.expr) {
+		c := p.expr[p.pos]
+		switch {
+		case c == quote:
+			p.pos++
+			return sb.String(), nil
+		case c == '\\':
+			p.pos++
+			if p.pos >= len(p.expr) {
+				return "", p.errorf("unterminated string")
+			}
+			escaped := p.expr[p.pos]
+			p.pos++
+			switch escaped {
+			case '\'', '"', '\\', '/':
+				sb.WriteByte(escaped)
+			case 'b':
+				sb.WriteByte('\b')
+			case 'f':
+				sb.WriteByte('\f')
+			case 'n':
+				sb.WriteByte('\n')
+			case 'r':
+				sb.WriteByte('\r')
+			case 't':
+				sb.WriteByte('\t')
+			case 'u':
+				if p.pos+4 > len(p.expr) {
+					return "", p.errorf("invalid unicode escape")
+				}
+				r, err := strconv.ParseUint(p.expr[p.pos:p.pos+4], 16, 32)
+				if err != nil {
+					return "", p.errorf("invalid unicode escape")
+				}
+				p.pos += 4
+				sb.WriteRune(rune(r))
+			default:
+				return "", p.errorf("invalid escape sequence '\\%c'", escaped)
+			}
+		default:
+			sb.WriteByte(c)
+			p.pos++
+		}
+	}
+
+	return "", p.errorf("unterminated string")
+}
+
+func (p *pathParser) parseOr() (filterExpr, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		p.skipSpace()
+		if !p.consume("||") {
+			return left, nil
+		}
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = orExpr{left: left, right: right}
+	}
+}
+
+func (p *pathParser) parseAnd() (filterExpr, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		p.skipSpace()
+		if !p.consume("&&") {
+			return left, nil
+		}
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		left = andExpr{left: left, right: right}
+	}
+}
+
+func (p *pathParser) parseNot() (filterExpr, error) {
+	p.skipSpace()
+	if p.peek("!") && !p.peek("!=") {
+		p.pos++
+		expr, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return notExpr{expr: expr}, nil
+	}
+
+	return p.parseComparison()
+}
+
+var comparisonOperators = []string{"==", "!=", "<=", ">=", "<", ">"}
+
+func (p *pathParser) parseComparison() (filterExpr, error) {
+	p.skipSpace()
+	if p.consume("(") {
+		expr, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		p.skipSpace()
+		if !p.consume(")") {
+			return nil, p.errorf("expected ')'")
+		}
+		return expr, nil
+	}
+
+	start := p.pos
+	left, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+
+	p.skipSpace()
+	for _, op := range comparisonOperators {
+		if !p.consume(op) {
+			continue
+		}
+
+		p.skipSpace()
+		right, err := p.parseOperand()
+		if err != nil {
+			return nil, err
+		}
+		return compareExpr{left: left, right: right, op: op}, nil
+	}
+
+	query, ok := left.(queryExpr)
+	if !ok {
+		p.pos = start
+		return nil, p.errorf("literal must be compared to something")
+	}
+	return existsExpr{query: query}, nil
+}
+
+func (p *pathParser) parseOperand() (operand, error) {
+	switch {
+	case p.consume("@"):
+		segments, err := p.parseSegments()
+		if err != nil {
+			return nil, err
+		}
+		return queryExpr{segments: segments}, nil
+	case p.consume("$"):
+		segments, err := p.parseSegments()
+		if err != nil {
+			return nil, err
+		}
+		return queryExpr{absolute: true, segments: segments}, nil
+	case p.peek("'") || p.peek(`"`):
+		s, err := p.parseString()
+		if err != nil {
+			return nil, err
+		}
+		return literalExpr{data: s}, nil
+	case p.consume("true"):
+		return literalExpr{data: true}, nil
+	case p.consume("false"):
+		return literalExpr{data: false}, nil
+	case p.consume("null"):
+		return literalExpr{data: nil}, nil
+	}
+
+	return p.parseNumber()
+}
+
+func (p *pathParser) parseNumber() (operand, error) {
+	start := p.pos
+	for p.pos < len(p.expr) && strings.IndexByte("+-0123456789.eE", p.expr[p.pos]) >= 0 {
+		p.pos++
+	}
+
+	n, err := strconv.ParseFloat(p.expr[start:p.pos], 64)
+	if err != nil || start == p.pos {
+		p.pos = start
+		return nil, p.errorf("expected operand")
+	}
+	return literalExpr{data: n}, nil
+}
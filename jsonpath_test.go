@@ -0,0 +1,216 @@
+package dynjson_test
+
+import (
+	"testing"
+
+	"github.com/go-schild/dynjson"
+	"github.com/stretchr/testify/assert"
+)
+
+const storeData = `{
+	"store": {
+		"book": [
+			{"category": "reference", "author": "Nigel Rees", "title": "Sayings of the Century", "price": 8.95},
+			{"category": "fiction", "author": "Evelyn Waugh", "title": "Sword of Honour", "price": 12.99},
+			{"category": "fiction", "author": "Herman Melville", "title": "Moby Dick", "isbn": "0-553-21311-3", "price": 8.99},
+			{"category": "fiction", "author": "J. R. R. Tolkien", "title": "The Lord of the Rings", "isbn": "0-395-19395-8", "price": 22.99}
+		],
+		"bicycle": {"color": "red", "price": 399}
+	},
+	"expensive": 10
+}`
+
+func queryPaths(t *testing.T, j dynjson.JsonObject, expr string) []string {
+	matches, err := j.Query(expr)
+	assert.Nil(t, err, expr)
+
+	paths := make([]string, 0, len(matches))
+	for _, match := range matches {
+		paths = append(paths, match.Path)
+	}
+	return paths
+}
+
+func TestCompileJsonPath(t *testing.T) {
+	valid := []string{
+		`$`,
+		`$.a.b`,
+		`$['a']["b"]`,
+		`$..*`,
+		`$..a[0]`,
+		`$[0, 1, 'a']`,
+		`$[1:2]`,
+		`$[::-1]`,
+		`$[?(@.a > 1 && (@.b == 'x' || !@.c))]`,
+		`$[?@.a]`,
+		`$[?(@.a == $.b)]`,
+	}
+	for _, expr := range valid {
+		p, err := dynjson.CompileJsonPath(expr)
+		assert.Nil(t, err, expr)
+		assert.Equal(t, expr, p.String())
+	}
+
+	invalid := []string{
+		``,
+		`a.b`,
+		`$.`,
+		`$[`,
+		`$['a`,
+		`$[1 2]`,
+		`$[?(@.a > )]`,
+		`$[?(5)]`,
+		`$[?(@.a]`,
+		`$ a`,
+	}
+	for _, expr := range invalid {
+		_, err := dynjson.CompileJsonPath(expr)
+		assert.NotNil(t, err, expr)
+	}
+
+	_, err := dynjson.CompileJsonPath(`$.a[`)
+	pErr, ok := err.(*dynjson.JsonPathError)
+	assert.True(t, ok)
+	assert.Equal(t, 4, pErr.Offset)
+
+	assert.Panics(t, func() { dynjson.MustCompileJsonPath(`$[`) })
+}
+
+func TestJsonPath_Query(t *testing.T) {
+	j, err := dynjson.ParseObject(storeData)
+	assert.Nil(t, err)
+
+	assert.Equal(t, []string{"$"}, queryPaths(t, j, `$`))
+
+	assert.Equal(t, []string{
+		"$['store']['book'][0]['author']",
+		"$['store']['book'][1]['author']",
+		"$['store']['book'][2]['author']",
+		"$['store']['book'][3]['author']",
+	}, queryPaths(t, j, `$.store.book[*].author`))
+
+	assert.Equal(t, queryPaths(t, j, `$.store.book[*].author`), queryPaths(t, j, `$..author`))
+
+	assert.Equal(t, []string{
+		"$['store']['bicycle']",
+		"$['store']['book']",
+	}, queryPaths(t, j, `$.store.*`))
+
+	assert.Equal(t, []string{
+		"$['store']['bicycle']['price']",
+		"$['store']['book'][0]['price']",
+		"$['store']['book'][1]['price']",
+		"$['store']['book'][2]['price']",
+		"$['store']['book'][3]['price']",
+	}, queryPaths(t, j, `$.store..price`))
+
+	assert.Equal(t, []string{"$['store']['book'][2]"}, queryPaths(t, j, `$..book[2]`))
+	assert.Equal(t, []string{"$['store']['book'][3]"}, queryPaths(t, j, `$..book[-1]`))
+	assert.Equal(t, []string{"$['store']['book'][0]", "$['store']['book'][1]"}, queryPaths(t, j, `$..book[0,1]`))
+	assert.Equal(t, []string{"$['store']['book'][0]", "$['store']['book'][1]"}, queryPaths(t, j, `$..book[:2]`))
+	assert.Equal(t, []string{"$['store']['book'][2]", "$['store']['book'][3]"}, queryPaths(t, j, `$..book[-2:]`))
+	assert.Equal(t, []string{"$['store']['book'][3]", "$['store']['book'][1]"}, queryPaths(t, j, `$..book[::-2]`))
+	assert.Equal(t, []string{}, queryPaths(t, j, `$..book[::0]`))
+	assert.Equal(t, []string{}, queryPaths(t, j, `$..book[7]`))
+	assert.Equal(t, []string{}, queryPaths(t, j, `$.none`))
+
+	assert.Equal(t, []string{
+		"$['store']['book'][2]",
+		"$['store']['book'][3]",
+	}, queryPaths(t, j, `$..book[?(@.isbn)]`))
+
+	assert.Equal(t, []string{
+		"$['store']['book'][0]",
+		"$['store']['book'][2]",
+	}, queryPaths(t, j, `$..book[?(@.price < 10)]`))
+
+	assert.Equal(t, []string{
+		"$['store']['book'][0]",
+		"$['store']['book'][2]",
+	}, queryPaths(t, j, `$..book[?(@.price < $.expensive)]`))
+
+	assert.Equal(t, []string{
+		"$['store']['book'][1]",
+	}, queryPaths(t, j, `$..book[?(@.category == "fiction" && !@.isbn)]`))
+
+	assert.Equal(t, []string{
+		"$['store']['book'][0]",
+		"$['store']['book'][3]",
+	}, queryPaths(t, j, `$..book[?(@.price > 20 || @.category != 'fiction')]`))
+
+	assert.Equal(t, []string{
+		"$['store']['book'][1]",
+		"$['store']['book'][3]",
+	}, queryPaths(t, j, `$..book[?(@.price >= 12.99)]`))
+
+	assert.Equal(t, 28, len(queryPaths(t, j, `$..*`)))
+}
+
+func TestJsonPath_Query_Values(t *testing.T) {
+	const testData = `{
+		"orders": [
+			{"id": 1, "lines": [{"sku": "a", "qty": 1}, {"sku": "b", "qty": 2}]},
+			{"id": 2, "lines": [{"sku": "c", "qty": 5}, {"sku": "d", "qty": 0}]}
+		]
+	}`
+
+	j, err := dynjson.ParseObject(testData)
+	assert.Nil(t, err)
+
+	path := dynjson.MustCompileJsonPath(`$.orders[*].lines[?(@.qty > 1)].sku`)
+	matches := path.Query(j)
+
+	skus := make([]string, 0)
+	pointers := make([]string, 0)
+	for _, match := range matches {
+		skus = append(skus, match.Value.String())
+		pointers = append(pointers, match.Pointer)
+	}
+
+	assert.Equal(t, []string{"b", "c"}, skus)
+	assert.Equal(t, []string{"/orders/0/lines/1/sku", "/orders/1/lines/0/sku"}, pointers)
+
+	other, err := dynjson.ParseObject(`{"orders": [{"lines": [{"sku": "x", "qty": 3}]}]}`)
+	assert.Nil(t, err)
+
+	matches = path.Query(other)
+	assert.Equal(t, 1, len(matches))
+	assert.Equal(t, "x", matches[0].Value.String())
+}
+
+func TestJsonPath_Query_Special(t *testing.T) {
+	j, err := dynjson.ParseObject(`{"a'b": {"c\\d": [null, true, "x"]}, "n": [1, 2.0, "2"]}`)
+	assert.Nil(t, err)
+
+	assert.Equal(t, []string{`$['a\'b']['c\\d'][0]`}, queryPaths(t, j, `$["a'b"]['c\\d'][?(@ == null)]`))
+	assert.Equal(t, []string{`$['a\'b']['c\\d'][1]`}, queryPaths(t, j, `$..[?(@ == true)]`))
+	assert.Equal(t, []string{`$['n'][1]`}, queryPaths(t, j, `$.n[?(@ == 2)]`))
+	assert.Equal(t, []string{`$['n'][2]`}, queryPaths(t, j, `$.n[?(@ == '2')]`))
+
+	_, err = j.Query(`$[`)
+	assert.NotNil(t, err)
+}
+
+func TestJsonPath_Query_Ordered(t *testing.T) {
+	o, err := dynjson.ParseOrderedObject(`{"z": {"y": 1, "b": 2}, "a": [{"x": 3}]}`)
+	assert.Nil(t, err)
+	p, err := dynjson.CompileJsonPath(`$..*`)
+	assert.Nil(t, err)
+
+	var paths []string
+	for _, match := range p.Query(o) {
+		paths = append(paths, match.Path)
+	}
+	assert.Equal(t, []string{`$['z']`, `$['a']`, `$['z']['y']`, `$['z']['b']`, `$['a'][0]`, `$['a'][0]['x']`}, paths)
+}
+
+func TestJsonList_Query(t *testing.T) {
+	j, err := dynjson.ParseList(`[{"a": 1}, {"a": 2}, {"b": 3}]`)
+	assert.Nil(t, err)
+
+	matches, err := j.Query(`$[*].a`)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(matches))
+	assert.Equal(t, 2, matches[1].Value.Int())
+	assert.Equal(t, "$[1]['a']", matches[1].Path)
+}
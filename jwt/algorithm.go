@@ -0,0 +1,116 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/sha512"
+	"fmt"
+	"hash"
+	"math/big"
+)
+
+// The supported algorithms.
+const (
+	HS256 = "HS256"
+	HS384 = "HS384"
+	HS512 = "HS512"
+	RS256 = "RS256"
+	ES256 = "ES256"
+)
+
+var hmacHashes = map[string]func() hash.Hash{
+	HS256: sha256.New,
+	HS384: sha512.New384,
+	HS512: sha512.New,
+}
+
+// es256Size is the size of R and S in an ES256 signature.
+const es256Size = 32
+
+// Verify verifies the signature of the token. The key is a []byte for HS256, HS384 and HS512, an *rsa.PublicKey for
+// RS256 and an *ecdsa.PublicKey with the curve P-256 for ES256. It fails with ErrAlgorithm, when the algorithm of the
+// token doesn't fit to the key.
+func (t *Token) Verify(key interface{}) error {
+	alg := t.Algorithm()
+	input := []byte(t.signingInput())
+
+	switch k := key.(type) {
+	case []byte:
+		newHash, ok := hmacHashes[alg]
+		if !ok {
+			return algorithmError(alg, key)
+		}
+		if !hmac.Equal(t.Signature, hmacSum(newHash, k, input)) {
+			return ErrSignature
+		}
+	case *rsa.PublicKey:
+		if alg != RS256 {
+			return algorithmError(alg, key)
+		}
+		digest := sha256.Sum256(input)
+		if rsa.VerifyPKCS1v15(k, crypto.SHA256, digest[:], t.Signature) != nil {
+			return ErrSignature
+		}
+	case *ecdsa.PublicKey:
+		if alg != ES256 || k.Curve != elliptic.P256() {
+			return algorithmError(alg, key)
+		}
+		if len(t.Signature) != 2*es256Size {
+			return ErrSignature
+		}
+		digest := sha256.Sum256(input)
+		r := new(big.Int).SetBytes(t.Signature[:es256Size])
+		s := new(big.Int).SetBytes(t.Signature[es256Size:])
+		if !ecdsa.Verify(k, digest[:], r, s) {
+			return ErrSignature
+		}
+	default:
+		return algorithmError(alg, key)
+	}
+
+	return nil
+}
+
+func sign(alg string, key interface{}, input []byte) ([]byte, error) {
+	switch k := key.(type) {
+	case []byte:
+		if newHash, ok := hmacHashes[alg]; ok {
+			return hmacSum(newHash, k, input), nil
+		}
+	case *rsa.PrivateKey:
+		if alg == RS256 {
+			digest := sha256.Sum256(input)
+			return rsa.SignPKCS1v15(rand.Reader, k, crypto.SHA256, digest[:])
+		}
+	case *ecdsa.PrivateKey:
+		if alg == ES256 && k.Curve == elliptic.P256() {
+			digest := sha256.Sum256(input)
+			r, s, err := ecdsa.Sign(rand.Reader, k, digest[:])
+			if err != nil {
+				return nil, err
+			}
+			signature := make([]byte, 2*es256Size)
+			rBytes, sBytes := r.Bytes(), s.Bytes()
+			copy(signature[es256Size-len(rBytes):es256Size], rBytes)
+			copy(signature[2*es256Size-len(sBytes):], sBytes)
+			return signature, nil
+		}
+	}
+
+	return nil, algorithmError(alg, key)
+}
+
+func hmacSum(newHash func() hash.Hash, key, input []byte) []byte {
+	mac := hmac.New(newHash, key)
+	mac.Write(input)
+	return mac.Sum(nil)
+}
+
+func algorithmError(alg string, key interface{}) error {
+	return fmt.Errorf("%w: %q with a key of type %T", ErrAlgorithm, alg, key)
+}
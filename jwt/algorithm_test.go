@@ -0,0 +1,82 @@
+package jwt_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/go-schild/dynjson"
+	"github.com/go-schild/dynjson/jwt"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestToken_Verify_RS256(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.Nil(t, err)
+	other, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.Nil(t, err)
+
+	raw, err := jwt.Sign(dynjson.JsonObject{"sub": "a"}, jwt.RS256, key)
+	assert.Nil(t, err)
+
+	token, err := jwt.Verify(raw, &key.PublicKey)
+	assert.Nil(t, err)
+	assert.Equal(t, "a", token.Claims.String("sub"))
+
+	_, err = jwt.Verify(raw, &other.PublicKey)
+	assert.True(t, errors.Is(err, jwt.ErrSignature))
+
+	// The public key must not be usable as HMAC secret.
+	_, err = jwt.Verify(raw, []byte("secret"))
+	assert.True(t, errors.Is(err, jwt.ErrAlgorithm))
+	_, err = jwt.Sign(dynjson.JsonObject{}, jwt.ES256, key)
+	assert.True(t, errors.Is(err, jwt.ErrAlgorithm))
+}
+
+func TestToken_Verify_ES256(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.Nil(t, err)
+	other, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.Nil(t, err)
+
+	for i := 0; i < 10; i++ {
+		raw, err := jwt.Sign(dynjson.JsonObject{"sub": "a"}, jwt.ES256, key)
+		assert.Nil(t, err)
+
+		token, err := jwt.Verify(raw, &key.PublicKey)
+		assert.Nil(t, err)
+		assert.Equal(t, 64, len(token.Signature))
+
+		_, err = jwt.Verify(raw, &other.PublicKey)
+		assert.True(t, errors.Is(err, jwt.ErrSignature))
+	}
+
+	raw, _ := jwt.Sign(dynjson.JsonObject{"sub": "a"}, jwt.ES256, key)
+	_, err = jwt.Verify(raw[:len(raw)-4], &key.PublicKey)
+	assert.True(t, errors.Is(err, jwt.ErrSignature))
+
+	p384, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	assert.Nil(t, err)
+	_, err = jwt.Sign(dynjson.JsonObject{}, jwt.ES256, p384)
+	assert.True(t, errors.Is(err, jwt.ErrAlgorithm))
+	_, err = jwt.Verify(raw, &p384.PublicKey)
+	assert.True(t, errors.Is(err, jwt.ErrAlgorithm))
+}
+
+func TestToken_Verify_None(t *testing.T) {
+	raw, err := jwt.Sign(dynjson.JsonObject{"admin": true}, jwt.HS256, []byte("secret"))
+	assert.Nil(t, err)
+
+	// Replace the header by {"alg":"none"} and remove the signature.
+	parts := strings.Split(raw, ".")
+	raw = "eyJhbGciOiJub25lIn0." + parts[1] + "."
+
+	_, err = jwt.Verify(raw, []byte("secret"))
+	assert.True(t, errors.Is(err, jwt.ErrAlgorithm))
+	_, err = jwt.Verify(raw, nil)
+	assert.True(t, errors.Is(err, jwt.ErrAlgorithm))
+}
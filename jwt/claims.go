@@ -0,0 +1,159 @@
+package jwt
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/go-schild/dynjson"
+)
+
+// ValidateOption changes the checks of Token.Validate and Verify.
+type ValidateOption func(*validateConfig)
+
+type validateConfig struct {
+	now      func() time.Time
+	skew     time.Duration
+	audience string
+	issuer   string
+	required []string
+}
+
+// Now sets the function, which returns the current time. It defaults to time.Now.
+func Now(now func() time.Time) ValidateOption {
+	return func(config *validateConfig) {
+		config.now = now
+	}
+}
+
+// ClockSkew tolerates clocks, which differ by up to d, when "exp", "nbf" and "iat" are checked.
+func ClockSkew(d time.Duration) ValidateOption {
+	return func(config *validateConfig) {
+		config.skew = d
+	}
+}
+
+// Audience requires the "aud" claim to be or to contain the audience.
+func Audience(audience string) ValidateOption {
+	return func(config *validateConfig) {
+		config.audience = audience
+	}
+}
+
+// Issuer requires the "iss" claim to be the issuer.
+func Issuer(issuer string) ValidateOption {
+	return func(config *validateConfig) {
+		config.issuer = issuer
+	}
+}
+
+// Require fails with ErrMissing, when one of the claims doesn't exist, e.g. Require("exp", "sub").
+func Require(claims ...string) ValidateOption {
+	return func(config *validateConfig) {
+		config.required = append(config.required, claims...)
+	}
+}
+
+// Validate checks the claims of the token:
+//   - "exp" has to be in the future, otherwise it fails with ErrExpired
+//   - "nbf" has to be in the past, otherwise it fails with ErrNotYetValid
+//   - "iat" has to be in the past, otherwise it fails with ErrIssuedAt
+//   - "aud" and "iss" are checked, when Audience or Issuer are set
+//
+// Claims, which don't exist, are not checked, unless they are required by Require, Audience or Issuer.
+// Claims of the wrong type fail with ErrMalformed.
+func (t *Token) Validate(opts ...ValidateOption) error {
+	config := validateConfig{now: time.Now}
+	for _, opt := range opts {
+		opt(&config)
+	}
+
+	for _, claim := range config.required {
+		if !t.Claims.Has(claim) {
+			return fmt.Errorf("%w: %q", ErrMissing, claim)
+		}
+	}
+
+	now := config.now()
+	if exp, ok, err := t.numericDate("exp"); err != nil {
+		return err
+	} else if ok && !now.Before(exp.Add(config.skew)) {
+		return fmt.Errorf("%w: expired at %s", ErrExpired, exp.UTC().Format(time.RFC3339))
+	}
+	if nbf, ok, err := t.numericDate("nbf"); err != nil {
+		return err
+	} else if ok && now.Add(config.skew).Before(nbf) {
+		return fmt.Errorf("%w: valid from %s", ErrNotYetValid, nbf.UTC().Format(time.RFC3339))
+	}
+	if iat, ok, err := t.numericDate("iat"); err != nil {
+		return err
+	} else if ok && now.Add(config.skew).Before(iat) {
+		return fmt.Errorf("%w: issued at %s", ErrIssuedAt, iat.UTC().Format(time.RFC3339))
+	}
+
+	if config.issuer != "" {
+		if iss, err := t.Claims.Value("iss").StringErr(); err != nil {
+			return claimError(ErrIssuer, "iss", err)
+		} else if iss != config.issuer {
+			return fmt.Errorf("%w: %q", ErrIssuer, iss)
+		}
+	}
+	if config.audience != "" {
+		if err := t.checkAudience(config.audience); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// maxNumericDate is the largest number of seconds, whose time can be compared without overflowing time.Duration.
+const maxNumericDate = math.MaxInt64 / 1e9
+
+// numericDate reads a claim containing the seconds since the epoch. It returns false, when the claim doesn't exist.
+// Dates further away from the epoch than about 292 years are malformed.
+func (t *Token) numericDate(claim string) (time.Time, bool, error) {
+	v := t.Claims.Value(claim)
+	if !v.Exists() {
+		return time.Time{}, false, nil
+	}
+
+	seconds, err := v.Float64Err()
+	if err != nil || math.IsNaN(seconds) || math.Abs(seconds) > maxNumericDate {
+		return time.Time{}, false, claimError(ErrMalformed, claim, err)
+	}
+	whole, frac := math.Modf(seconds)
+	return time.Unix(int64(whole), int64(frac*1e9)), true, nil
+}
+
+// checkAudience checks whether "aud" is the audience or a list containing it.
+func (t *Token) checkAudience(audience string) error {
+	v := t.Claims.Value("aud")
+	if aud, ok := v.StringOk(); ok {
+		if aud != audience {
+			return fmt.Errorf("%w: %q", ErrAudience, aud)
+		}
+		return nil
+	}
+
+	list, err := v.ListErr()
+	if err != nil {
+		return claimError(ErrAudience, "aud", err)
+	}
+	for _, item := range list {
+		if item.String() == audience {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %s doesn't contain %q", ErrAudience, list.ToString(), audience)
+}
+
+func claimError(sentinel error, claim string, err error) error {
+	if err == nil {
+		return fmt.Errorf("%w: %q", sentinel, claim)
+	}
+	if e, ok := err.(*dynjson.Error); ok && e.Kind == dynjson.ErrorKindMissing {
+		return fmt.Errorf("%w: %q", ErrMissing, claim)
+	}
+	return fmt.Errorf("%w: %q: %v", sentinel, claim, err)
+}
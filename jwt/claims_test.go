@@ -0,0 +1,76 @@
+package jwt_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-schild/dynjson"
+	"github.com/go-schild/dynjson/jwt"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestToken_Validate(t *testing.T) {
+	now := time.Unix(1600000000, 0)
+	at := jwt.Now(func() time.Time { return now })
+	key := []byte("secret")
+
+	testData := []struct {
+		claims dynjson.JsonObject
+		opts   []jwt.ValidateOption
+		err    error
+	}{
+		{dynjson.JsonObject{}, nil, nil},
+		{dynjson.JsonObject{"exp": 1600000001}, nil, nil},
+		{dynjson.JsonObject{"exp": 1600000000}, nil, jwt.ErrExpired},
+		{dynjson.JsonObject{"exp": 1599999990}, []jwt.ValidateOption{jwt.ClockSkew(10 * time.Second)}, jwt.ErrExpired},
+		{dynjson.JsonObject{"exp": 1599999990}, []jwt.ValidateOption{jwt.ClockSkew(11 * time.Second)}, nil},
+		{dynjson.JsonObject{"exp": 1599999999.5}, nil, jwt.ErrExpired},
+		{dynjson.JsonObject{"exp": 1600000000.5}, nil, nil},
+		{dynjson.JsonObject{"exp": "tomorrow"}, nil, jwt.ErrMalformed},
+		{dynjson.JsonObject{"exp": 1e300}, nil, jwt.ErrMalformed},
+		{dynjson.JsonObject{"exp": -1e19}, nil, jwt.ErrMalformed},
+		{dynjson.JsonObject{"nbf": 1e19}, nil, jwt.ErrMalformed},
+		{dynjson.JsonObject{"iat": 9.3e9}, nil, jwt.ErrMalformed},
+		{dynjson.JsonObject{"nbf": 1600000000}, nil, nil},
+		{dynjson.JsonObject{"nbf": 1600000060}, nil, jwt.ErrNotYetValid},
+		{dynjson.JsonObject{"nbf": 1600000060}, []jwt.ValidateOption{jwt.ClockSkew(time.Minute)}, nil},
+		{dynjson.JsonObject{"iat": 1600000000}, nil, nil},
+		{dynjson.JsonObject{"iat": 1600000060}, nil, jwt.ErrIssuedAt},
+		{dynjson.JsonObject{"iat": 1600000060}, []jwt.ValidateOption{jwt.ClockSkew(time.Minute)}, nil},
+		{dynjson.JsonObject{"iss": "a"}, []jwt.ValidateOption{jwt.Issuer("a")}, nil},
+		{dynjson.JsonObject{"iss": "b"}, []jwt.ValidateOption{jwt.Issuer("a")}, jwt.ErrIssuer},
+		{dynjson.JsonObject{"iss": 1}, []jwt.ValidateOption{jwt.Issuer("a")}, jwt.ErrIssuer},
+		{dynjson.JsonObject{}, []jwt.ValidateOption{jwt.Issuer("a")}, jwt.ErrMissing},
+		{dynjson.JsonObject{"aud": "api"}, []jwt.ValidateOption{jwt.Audience("api")}, nil},
+		{dynjson.JsonObject{"aud": []interface{}{"web", "api"}}, []jwt.ValidateOption{jwt.Audience("api")}, nil},
+		{dynjson.JsonObject{"aud": []interface{}{"web"}}, []jwt.ValidateOption{jwt.Audience("api")}, jwt.ErrAudience},
+		{dynjson.JsonObject{"aud": "web"}, []jwt.ValidateOption{jwt.Audience("api")}, jwt.ErrAudience},
+		{dynjson.JsonObject{"aud": true}, []jwt.ValidateOption{jwt.Audience("api")}, jwt.ErrAudience},
+		{dynjson.JsonObject{}, []jwt.ValidateOption{jwt.Audience("api")}, jwt.ErrMissing},
+		{dynjson.JsonObject{"sub": "a"}, []jwt.ValidateOption{jwt.Require("sub", "exp")}, jwt.ErrMissing},
+	}
+
+	for _, data := range testData {
+		raw, err := jwt.Sign(data.claims, jwt.HS256, key)
+		assert.Nil(t, err)
+
+		_, err = jwt.Verify(raw, key, append(data.opts, at)...)
+		if data.err == nil {
+			assert.Nil(t, err, data.claims.ToString())
+		} else {
+			assert.True(t, errors.Is(err, data.err), "%s: %v", data.claims.ToString(), err)
+		}
+	}
+}
+
+func TestToken_Validate_Message(t *testing.T) {
+	token := &jwt.Token{Claims: dynjson.JsonObject{"exp": 1600000000, "iss": 1}}
+	at := jwt.Now(func() time.Time { return time.Unix(1700000000, 0) })
+
+	assert.EqualError(t, token.Validate(at), "jwt: token is expired: expired at 2020-09-13T12:26:40Z")
+
+	token.Claims.SetNumber("exp", 1800000000)
+	assert.EqualError(t, token.Validate(at, jwt.Issuer("a")),
+		`jwt: invalid issuer: "iss": dynjson: expected string, got number`)
+}
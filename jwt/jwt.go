@@ -0,0 +1,154 @@
+// Package jwt parses, verifies and signs JSON Web Tokens in the compact JWS serialization (RFC 7515, RFC 7519).
+//
+// Header and claims are dynjson.JsonObject values, so claims are read like any other json document:
+//
+//	token, err := jwt.Verify(raw, key, jwt.Issuer("https://auth.example.com"), jwt.Audience("api"))
+//	if err != nil {
+//		return err
+//	}
+//	subject := token.Claims.String("sub")
+//
+// The supported algorithms are HS256, HS384, HS512, RS256 and ES256. The type of the key decides which algorithms
+// are accepted, so a token can't switch from RS256 to HS256 or to "none".
+package jwt
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/go-schild/dynjson"
+)
+
+// Errors returned by Parse, Verify and Token.Validate. Use errors.Is to check them.
+var (
+	ErrMalformed   = errors.New("jwt: malformed token")
+	ErrAlgorithm   = errors.New("jwt: unsupported algorithm")
+	ErrSignature   = errors.New("jwt: invalid signature")
+	ErrExpired     = errors.New("jwt: token is expired")
+	ErrNotYetValid = errors.New("jwt: token is not valid yet")
+	ErrIssuedAt    = errors.New("jwt: token is issued in the future")
+	ErrAudience    = errors.New("jwt: invalid audience")
+	ErrIssuer      = errors.New("jwt: invalid issuer")
+	ErrMissing     = errors.New("jwt: missing claim")
+)
+
+// Token is a parsed token.
+type Token struct {
+	// Header is the JOSE header, e.g. {"alg": "HS256", "typ": "JWT"}.
+	Header dynjson.JsonObject
+	// Claims is the payload of the token.
+	Claims dynjson.JsonObject
+	// Signature is the decoded signature.
+	Signature []byte
+	// Raw is the token as it was parsed.
+	Raw string
+}
+
+// Algorithm returns the "alg" header.
+func (t *Token) Algorithm() string {
+	return t.Header.String("alg")
+}
+
+// signingInput returns the part of the token, which is signed: the encoded header and claims.
+func (t *Token) signingInput() string {
+	return t.Raw[:strings.LastIndexByte(t.Raw, '.')]
+}
+
+// Parse parses a token without verifying its signature or claims. Use it only to look into a token, e.g. to find
+// its key by the "kid" header, and call Token.Verify and Token.Validate before trusting the claims.
+// Objects with duplicate keys are rejected, because other parsers might read them differently.
+func Parse(raw string) (*Token, error) {
+	parts := strings.Split(raw, ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("%w: expected 3 parts, got %d", ErrMalformed, len(parts))
+	}
+
+	header, err := decodeObject(parts[0], "header")
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := header.StringOk("alg"); !ok {
+		return nil, fmt.Errorf("%w: header: missing \"alg\"", ErrMalformed)
+	}
+	if header.Has("crit") {
+		return nil, fmt.Errorf("%w: header: critical extensions are not supported", ErrMalformed)
+	}
+
+	claims, err := decodeObject(parts[1], "claims")
+	if err != nil {
+		return nil, err
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("%w: signature: %v", ErrMalformed, err)
+	}
+
+	return &Token{Header: header, Claims: claims, Signature: signature, Raw: raw}, nil
+}
+
+func decodeObject(part, name string) (dynjson.JsonObject, error) {
+	data, err := base64.RawURLEncoding.DecodeString(part)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s: %v", ErrMalformed, name, err)
+	}
+
+	obj, err := dynjson.ParseObject(string(data), dynjson.UseNumber(), dynjson.DuplicateKeys(dynjson.DuplicateKeysError))
+	if err == nil && obj == nil {
+		err = errors.New("expected an object, got null")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s: %v", ErrMalformed, name, err)
+	}
+	return obj, nil
+}
+
+// Verify parses a token, verifies its signature with the key and validates its claims.
+// See Token.Verify for the supported keys and Token.Validate for the options.
+func Verify(raw string, key interface{}, opts ...ValidateOption) (*Token, error) {
+	token, err := Parse(raw)
+	if err != nil {
+		return nil, err
+	}
+	if err := token.Verify(key); err != nil {
+		return nil, err
+	}
+	if err := token.Validate(opts...); err != nil {
+		return nil, err
+	}
+	return token, nil
+}
+
+// Sign creates a signed token with the header {"alg": alg, "typ": "JWT"}. See SignWithHeader.
+func Sign(claims dynjson.JsonObject, alg string, key interface{}) (string, error) {
+	return SignWithHeader(dynjson.JsonObject{"alg": alg, "typ": "JWT"}, claims, key)
+}
+
+// SignWithHeader creates a signed token with the algorithm of the "alg" header, e.g. to add a "kid" header.
+// The key is a []byte for HS256, HS384 and HS512, an *rsa.PrivateKey for RS256 and an *ecdsa.PrivateKey with
+// the curve P-256 for ES256.
+func SignWithHeader(header, claims dynjson.JsonObject, key interface{}) (string, error) {
+	alg, ok := header.StringOk("alg")
+	if !ok {
+		return "", fmt.Errorf("%w: header: missing \"alg\"", ErrMalformed)
+	}
+
+	headerData, err := header.Marshal()
+	if err != nil {
+		return "", err
+	}
+	claimsData, err := claims.Marshal()
+	if err != nil {
+		return "", err
+	}
+
+	input := base64.RawURLEncoding.EncodeToString(headerData) + "." + base64.RawURLEncoding.EncodeToString(claimsData)
+	signature, err := sign(alg, key, []byte(input))
+	if err != nil {
+		return "", err
+	}
+
+	return input + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
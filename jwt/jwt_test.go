@@ -0,0 +1,108 @@
+package jwt_test
+
+import (
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/go-schild/dynjson"
+	"github.com/go-schild/dynjson/jwt"
+	"github.com/stretchr/testify/assert"
+)
+
+// The example of RFC 7515, Appendix A.1.
+const (
+	rfcToken = "eyJ0eXAiOiJKV1QiLA0KICJhbGciOiJIUzI1NiJ9" +
+		".eyJpc3MiOiJqb2UiLA0KICJleHAiOjEzMDA4MTkzODAsDQogImh0dHA6Ly9leGFtcGxlLmNvbS9pc19yb290Ijp0cnVlfQ" +
+		".dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk"
+	rfcKey = "AyM1SysPpbyDfgZld3umj1qzKObwVMkoqQ-EstJQLr_T-1qS0gZH75aKtMN3Yj0iPS4hcgUuTwjAzZr1Z9CAow"
+)
+
+func rfcKeyBytes(t *testing.T) []byte {
+	key, err := base64.RawURLEncoding.DecodeString(rfcKey)
+	assert.Nil(t, err)
+	return key
+}
+
+func TestParse(t *testing.T) {
+	token, err := jwt.Parse(rfcToken)
+	assert.Nil(t, err)
+	assert.Equal(t, jwt.HS256, token.Algorithm())
+	assert.Equal(t, "JWT", token.Header.String("typ"))
+	assert.Equal(t, "joe", token.Claims.String("iss"))
+	assert.Equal(t, int64(1300819380), token.Claims.Int64("exp"))
+	assert.True(t, token.Claims.Bool("http://example.com/is_root"))
+	assert.Equal(t, 32, len(token.Signature))
+}
+
+func TestParse_Malformed(t *testing.T) {
+	encode := func(s string) string {
+		return base64.RawURLEncoding.EncodeToString([]byte(s))
+	}
+	header := encode(`{"alg":"HS256"}`)
+
+	testData := []string{
+		"",
+		"a.b",
+		header + "." + encode(`{}`) + ".sig.x",
+		"!." + encode(`{}`) + ".",
+		header + "." + encode(`[]`) + ".",
+		header + "." + encode(`null`) + ".",
+		header + "." + encode(`{"sub": "a", "sub": "b"}`) + ".",
+		encode(`{}`) + "." + encode(`{}`) + ".",
+		encode(`{"alg":"HS256","crit":["exp"]}`) + "." + encode(`{}`) + ".",
+		header + "." + encode(`{}`) + ".a=",
+		header + "=." + encode(`{}`) + ".",
+	}
+
+	for _, data := range testData {
+		_, err := jwt.Parse(data)
+		assert.True(t, errors.Is(err, jwt.ErrMalformed), data)
+	}
+}
+
+func TestVerify(t *testing.T) {
+	key := rfcKeyBytes(t)
+
+	token, err := jwt.Parse(rfcToken)
+	assert.Nil(t, err)
+	assert.Nil(t, token.Verify(key))
+	assert.True(t, errors.Is(token.Verify([]byte("wrong")), jwt.ErrSignature))
+
+	// The token of the RFC is expired.
+	_, err = jwt.Verify(rfcToken, key)
+	assert.True(t, errors.Is(err, jwt.ErrExpired))
+
+	// A changed payload doesn't match the signature.
+	parts := strings.Split(rfcToken, ".")
+	parts[1] = base64.RawURLEncoding.EncodeToString([]byte(`{"iss":"joe","exp":9999999999}`))
+	_, err = jwt.Verify(strings.Join(parts, "."), key)
+	assert.True(t, errors.Is(err, jwt.ErrSignature))
+}
+
+func TestSign(t *testing.T) {
+	key := []byte("secret")
+	claims := dynjson.JsonObject{"sub": "1234567890", "name": "John Doe", "iat": 1516239022}
+
+	for _, alg := range []string{jwt.HS256, jwt.HS384, jwt.HS512} {
+		raw, err := jwt.Sign(claims, alg, key)
+		assert.Nil(t, err, alg)
+
+		token, err := jwt.Verify(raw, key)
+		assert.Nil(t, err, alg)
+		assert.Equal(t, alg, token.Algorithm())
+		assert.Equal(t, "John Doe", token.Claims.String("name"))
+	}
+
+	raw, err := jwt.SignWithHeader(dynjson.JsonObject{"alg": jwt.HS256, "kid": "k1"}, claims, key)
+	assert.Nil(t, err)
+	assert.Equal(t, "eyJhbGciOiJIUzI1NiIsImtpZCI6ImsxIn0."+
+		"eyJpYXQiOjE1MTYyMzkwMjIsIm5hbWUiOiJKb2huIERvZSIsInN1YiI6IjEyMzQ1Njc4OTAifQ."+
+		"pmVDlfNI1wyVKWvqPnacSt-r_6tesyGhQ9ee7IUuvn0", raw)
+
+	_, err = jwt.Sign(claims, "none", key)
+	assert.True(t, errors.Is(err, jwt.ErrAlgorithm))
+	_, err = jwt.SignWithHeader(dynjson.JsonObject{}, claims, key)
+	assert.True(t, errors.Is(err, jwt.ErrMalformed))
+}
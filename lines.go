@@ -0,0 +1,327 @@
+package dynjson
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// LineOption changes the behaviour of a LineReader.
+type LineOption func(*lineConfig)
+
+type lineConfig struct {
+	parse     []ParseOption
+	skip      bool
+	onInvalid func(*LineError)
+}
+
+// LineParseOptions sets the options used to parse each value, e.g. UseNumber or MaxDepth.
+// MaxBytes limits the size of each value instead of the whole input.
+func LineParseOptions(opts ...ParseOption) LineOption {
+	return func(config *lineConfig) {
+		config.parse = append(config.parse, opts...)
+	}
+}
+
+// SkipInvalidLines skips values, which can't be read, instead of failing. A value with a syntax error is skipped
+// together with the rest of the line it starts on, so the next line is read, even if the value seemed to continue.
+// The handler is called with the error of each skipped value, it may be nil.
+func SkipInvalidLines(handler func(*LineError)) LineOption {
+	return func(config *lineConfig) {
+		config.skip = true
+		config.onInvalid = handler
+	}
+}
+
+// LineError is returned by a LineReader, when a value can't be read.
+type LineError struct {
+	// Line is the line number where the error occurred, starting with 1.
+	Line int
+	// Err is the error, e.g. an *Error or a *LimitError. Offset, line and column of syntax errors refer to the
+	// whole input.
+	Err error
+}
+
+func (e *LineError) Error() string {
+	if err, ok := e.Err.(*Error); ok && err.Kind == ErrorKindSyntax {
+		// The line is part of the message already.
+		return err.Error()
+	}
+	return fmt.Sprintf("dynjson: line %d: %s", e.Line, strings.TrimPrefix(e.Err.Error(), "dynjson: "))
+}
+
+// Unwrap returns the underlying error.
+func (e *LineError) Unwrap() error {
+	return e.Err
+}
+
+// LineReader reads a stream of json values, e.g. newline-delimited json (NDJSON, JSON Lines) or concatenated values
+// like {"a": 1}{"b": 2}. Values may span several lines, e.g. in a stream of indented documents.
+type LineReader struct {
+	r      *bufio.Reader
+	config lineConfig
+	parse  parseConfig
+	eof    bool
+
+	// pending contains the data, which was read but not parsed yet. It starts at offset, in the line number line,
+	// at the column column.
+	pending []byte
+	offset  int64
+	line    int
+	column  int
+
+	// valueLine is the line of the last value returned by Read.
+	valueLine int
+}
+
+// NewLineReader creates a LineReader reading from r.
+func NewLineReader(r io.Reader, opts ...LineOption) *LineReader {
+	config := lineConfig{}
+	for _, opt := range opts {
+		opt(&config)
+	}
+
+	return &LineReader{
+		r:      bufio.NewReader(r),
+		config: config,
+		parse:  newParseConfig(config.parse),
+		line:   1,
+		column: 1,
+	}
+}
+
+// Line returns the line number, where the value returned by the last call of Read or ReadObject starts.
+func (l *LineReader) Line() int {
+	return l.valueLine
+}
+
+// Read returns the next value. At the end of the input, it returns io.EOF. Other errors are of the type *LineError
+// or come from the underlying reader.
+func (l *LineReader) Read() (Value, error) {
+	for {
+		raw, err := l.next()
+		if err == nil {
+			return Value{data: raw, exists: true}, nil
+		}
+		if lErr, ok := err.(*LineError); ok && l.config.skip {
+			l.skipped(lErr)
+			continue
+		}
+		return Value{}, err
+	}
+}
+
+// ReadObject returns the next value, which has to be an object. At the end of the input, it returns io.EOF.
+// Other values fail with a *LineError of the kind ErrorKindTypeMismatch or are skipped by SkipInvalidLines.
+func (l *LineReader) ReadObject() (JsonObject, error) {
+	for {
+		v, err := l.Read()
+		if err != nil {
+			return nil, err
+		}
+
+		if obj, ok := v.ObjectOk(); ok {
+			return obj, nil
+		}
+		lErr := &LineError{Line: l.valueLine, Err: typeError(KindObject, v.data)}
+		if !l.config.skip {
+			return nil, lErr
+		}
+		l.skipped(lErr)
+	}
+}
+
+func (l *LineReader) skipped(err *LineError) {
+	if l.config.onInvalid != nil {
+		l.config.onInvalid(err)
+	}
+}
+
+// next parses the next value. On syntax errors, the line the value starts on is dropped.
+func (l *LineReader) next() (interface{}, error) {
+	if err := l.skipSpace(); err != nil {
+		return nil, err
+	}
+	l.valueLine = l.line
+
+	for {
+		if l.exceeded() {
+			limitErr := &LimitError{Limit: LimitBytes, Max: l.parse.maxBytes, Offset: l.offset + l.parse.maxBytes}
+			if err := l.dropLine(); err != nil {
+				return nil, err
+			}
+			return nil, &LineError{Line: l.valueLine, Err: limitErr}
+		}
+
+		pos := &positionReader{r: bytes.NewReader(l.pending)}
+		dec := newDecoder(pos, l.parse)
+		raw, err := decodeNext(dec, pos, l.parse)
+		if err == nil {
+			l.consume(int(dec.InputOffset()))
+			return raw, nil
+		}
+
+		if errors.Is(err, io.ErrUnexpectedEOF) && !l.eof {
+			// The value continues on the next lines. Doubling the data avoids parsing long values over and over.
+			for target := 2 * len(l.pending); len(l.pending) < target && !l.eof && !l.exceeded(); {
+				if err := l.readLine(); err != nil {
+					return nil, err
+				}
+			}
+			continue
+		}
+
+		err = l.absoluteError(err)
+		if dropErr := l.dropLine(); dropErr != nil {
+			return nil, dropErr
+		}
+		return nil, err
+	}
+}
+
+// absoluteError converts the position of an error inside of pending into a position inside of the input.
+func (l *LineReader) absoluteError(err error) error {
+	line := l.valueLine
+	switch e := err.(type) {
+	case *Error:
+		if e.Kind == ErrorKindSyntax || e.Kind == ErrorKindDuplicateKey {
+			if e.Line == 1 {
+				e.Column += l.column - 1
+			}
+			e.Line += l.line - 1
+			e.Offset += l.offset
+			line = e.Line
+		}
+	case *LimitError:
+		e.Offset += l.offset
+	}
+
+	return &LineError{Line: line, Err: err}
+}
+
+// skipSpace removes whitespace from the beginning of pending and reads more lines, until pending starts with
+// a value. It returns io.EOF at the end of the input.
+func (l *LineReader) skipSpace() error {
+	for {
+		n := 0
+		for n < len(l.pending) && isSpace(l.pending[n]) {
+			n++
+		}
+		l.consume(n)
+
+		if len(l.pending) > 0 {
+			return nil
+		}
+		if l.eof {
+			return io.EOF
+		}
+		if err := l.readLine(); err != nil {
+			return err
+		}
+	}
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\r' || c == '\n'
+}
+
+// exceeded returns true, when pending is longer than the limit set by MaxBytes.
+func (l *LineReader) exceeded() bool {
+	return l.parse.maxBytes > 0 && int64(len(l.pending)) > l.parse.maxBytes
+}
+
+// readLine appends the next line to pending. The line is read in chunks, which stop as soon as pending exceeds
+// MaxBytes, so a huge line isn't buffered completely.
+func (l *LineReader) readLine() error {
+	for {
+		chunk, err := l.r.ReadSlice('\n')
+		l.pending = append(l.pending, chunk...)
+		switch {
+		case err == bufio.ErrBufferFull && !l.exceeded():
+			continue
+		case err == bufio.ErrBufferFull:
+			return nil
+		case err == io.EOF:
+			l.eof = true
+			return nil
+		}
+		return err
+	}
+}
+
+// consume removes n bytes from pending and keeps track of the position.
+func (l *LineReader) consume(n int) {
+	for _, c := range l.pending[:n] {
+		if c == '\n' {
+			l.line++
+			l.column = 1
+		} else {
+			l.column++
+		}
+	}
+	l.offset += int64(n)
+	l.pending = l.pending[n:]
+}
+
+// dropLine removes the rest of the current line from pending. When pending ends within the line, the rest of the
+// line is skipped in the underlying reader.
+func (l *LineReader) dropLine() error {
+	if n := bytes.IndexByte(l.pending, '\n'); n >= 0 {
+		l.consume(n + 1)
+		return nil
+	}
+	l.consume(len(l.pending))
+
+	for !l.eof {
+		chunk, err := l.r.ReadSlice('\n')
+		l.offset += int64(len(chunk))
+		l.column += len(chunk)
+		switch {
+		case err == bufio.ErrBufferFull:
+			continue
+		case err == io.EOF:
+			l.eof = true
+		case err != nil:
+			return err
+		default:
+			l.line++
+			l.column = 1
+		}
+		return nil
+	}
+	return nil
+}
+
+// LineWriter writes json values as newline-delimited json, one compact value per line.
+// The output is buffered, call Flush after writing the last value.
+type LineWriter struct {
+	w      *bufio.Writer
+	config marshalConfig
+}
+
+// NewLineWriter creates a LineWriter writing to w. The options change the encoding of the values, e.g. SortKeys.
+// Indent and TrailingNewline are ignored, because every value has to be on a single line.
+func NewLineWriter(w io.Writer, opts ...MarshalOption) *LineWriter {
+	config := newMarshalConfig(opts)
+	config.indent = ""
+	config.trailingNewline = true
+	return &LineWriter{w: bufio.NewWriter(w), config: config}
+}
+
+// WriteValue writes a value, e.g. a JsonObject, a JsonList or a Value, followed by a newline.
+func (l *LineWriter) WriteValue(v interface{}) error {
+	data, err := marshalNode(v, l.config)
+	if err != nil {
+		return err
+	}
+	_, err = l.w.Write(data)
+	return err
+}
+
+// Flush writes the buffered values to the underlying writer.
+func (l *LineWriter) Flush() error {
+	return l.w.Flush()
+}
@@ -0,0 +1,190 @@
+package dynjson_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"math"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/go-schild/dynjson"
+	"github.com/stretchr/testify/assert"
+)
+
+func readAll(t *testing.T, r *dynjson.LineReader) ([]string, []int, error) {
+	var values []string
+	var lines []int
+	for {
+		v, err := r.Read()
+		if err == io.EOF {
+			return values, lines, nil
+		}
+		if err != nil {
+			return values, lines, err
+		}
+		values = append(values, v.ToString())
+		lines = append(lines, r.Line())
+	}
+}
+
+func TestLineReader_Read(t *testing.T) {
+	input := "{\"a\": 1}\n\n[1, 2]\r\n  \"x\"\n{\"b\": {\n  \"c\": true\n}}\n{\"d\": 1}{\"e\": 2} 3 null\n4"
+
+	values, lines, err := readAll(t, dynjson.NewLineReader(strings.NewReader(input)))
+	assert.Nil(t, err)
+	assert.Equal(t, []string{`{"a":1}`, `[1,2]`, `"x"`, `{"b":{"c":true}}`, `{"d":1}`, `{"e":2}`, `3`, `null`, `4`},
+		values)
+	assert.Equal(t, []int{1, 3, 4, 5, 8, 8, 8, 8, 9}, lines)
+
+	values, _, err = readAll(t, dynjson.NewLineReader(strings.NewReader("")))
+	assert.Nil(t, err)
+	assert.Empty(t, values)
+
+	values, _, err = readAll(t, dynjson.NewLineReader(strings.NewReader(`{"a":1}{"b":2}`)))
+	assert.Nil(t, err)
+	assert.Equal(t, []string{`{"a":1}`, `{"b":2}`}, values)
+}
+
+func TestLineReader_Errors(t *testing.T) {
+	input := "{\"a\": 1}\n  {\"b\": x}\n{\"c\": 3}\n"
+
+	r := dynjson.NewLineReader(strings.NewReader(input))
+	values, _, err := readAll(t, r)
+	assert.Equal(t, []string{`{"a":1}`}, values)
+	assert.True(t, errors.Is(err, dynjson.ErrSyntax))
+	assert.EqualError(t, err,
+		"dynjson: syntax error at line 2, column 9 (offset 18): invalid character 'x' looking for beginning of value")
+
+	var lErr *dynjson.LineError
+	assert.True(t, errors.As(err, &lErr))
+	assert.Equal(t, 2, lErr.Line)
+
+	_, _, err = readAll(t, dynjson.NewLineReader(strings.NewReader("{\"a\": 1}\n{\"b\": [1,\n")))
+	assert.True(t, errors.Is(err, io.ErrUnexpectedEOF))
+	assert.True(t, errors.As(err, &lErr))
+	assert.Equal(t, 2, lErr.Line)
+
+	_, _, err = readAll(t, dynjson.NewLineReader(strings.NewReader("1\n[[[1]]]"),
+		dynjson.LineParseOptions(dynjson.MaxDepth(2))))
+	assert.EqualError(t, err, "dynjson: line 2: maximum nesting depth of 2 exceeded at offset 5")
+
+	_, _, err = readAll(t, dynjson.NewLineReader(strings.NewReader("1\n{\"a\": 1, \"a\": 2}"),
+		dynjson.LineParseOptions(dynjson.DuplicateKeys(dynjson.DuplicateKeysError))))
+	assert.EqualError(t, err, `dynjson: line 2: "/a": duplicate key at line 2, column 12`)
+}
+
+func TestLineReader_SkipInvalidLines(t *testing.T) {
+	input := "{\"a\": 1}\n{\"b\": [1, 2\n{\"c\": 3}\nnot json\n{\"d\": 4} x {\"e\": 5}\n{\"f\": \n6}\n{\"g\": "
+
+	var skipped []int
+	r := dynjson.NewLineReader(strings.NewReader(input), dynjson.SkipInvalidLines(func(err *dynjson.LineError) {
+		skipped = append(skipped, err.Line)
+	}))
+	values, lines, err := readAll(t, r)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{`{"a":1}`, `{"c":3}`, `{"d":4}`, `{"f":6}`}, values)
+	assert.Equal(t, []int{1, 3, 5, 6}, lines)
+	assert.Equal(t, []int{3, 4, 5, 8}, skipped)
+
+	values, _, err = readAll(t, dynjson.NewLineReader(strings.NewReader("[1]\n[2]"), dynjson.SkipInvalidLines(nil),
+		dynjson.LineParseOptions(dynjson.MaxBytes(2))))
+	assert.Nil(t, err)
+	assert.Empty(t, values)
+}
+
+// longLine is a reader returning a line of n times 'x' followed by rest.
+type longLine struct {
+	n    int
+	rest io.Reader
+}
+
+func (l *longLine) Read(p []byte) (int, error) {
+	if l.n == 0 {
+		return l.rest.Read(p)
+	}
+	if len(p) > l.n {
+		p = p[:l.n]
+	}
+	for i := range p {
+		p[i] = 'x'
+	}
+	l.n -= len(p)
+	return len(p), nil
+}
+
+func TestLineReader_MaxBytes(t *testing.T) {
+	const n = 64 << 20
+	r := dynjson.NewLineReader(&longLine{n: n, rest: strings.NewReader("\n{\"a\": 1}\n")},
+		dynjson.LineParseOptions(dynjson.MaxBytes(100)))
+
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	_, err := r.Read()
+	runtime.ReadMemStats(&after)
+
+	var limitErr *dynjson.LimitError
+	assert.True(t, errors.As(err, &limitErr))
+	assert.Equal(t, int64(100), limitErr.Offset)
+	assert.Less(t, after.TotalAlloc-before.TotalAlloc, uint64(1<<20))
+
+	v, err := r.Read()
+	assert.Nil(t, err)
+	assert.Equal(t, `{"a":1}`, v.ToString())
+	assert.Equal(t, 2, r.Line())
+}
+
+func TestLineReader_ReadObject(t *testing.T) {
+	r := dynjson.NewLineReader(strings.NewReader("{\"a\": 1}\n[1]\n{\"b\": 2}"))
+
+	j, err := r.ReadObject()
+	assert.Nil(t, err)
+	assert.Equal(t, 1, j.Int("a"))
+	_, err = r.ReadObject()
+	assert.True(t, errors.Is(err, dynjson.ErrTypeMismatch))
+	assert.EqualError(t, err, "dynjson: line 2: expected object, got array")
+	j, err = r.ReadObject()
+	assert.Nil(t, err)
+	assert.Equal(t, 2, j.Int("b"))
+	_, err = r.ReadObject()
+	assert.Equal(t, io.EOF, err)
+
+	r = dynjson.NewLineReader(strings.NewReader("[1]\n{\"b\": 2}\n"), dynjson.SkipInvalidLines(nil))
+	j, err = r.ReadObject()
+	assert.Nil(t, err)
+	assert.Equal(t, 2, j.Int("b"))
+	assert.Equal(t, 2, r.Line())
+}
+
+func TestLineReader_LongValue(t *testing.T) {
+	var buf bytes.Buffer
+	buf.WriteString("[\n")
+	for i := 0; i < 10000; i++ {
+		buf.WriteString("  {\"a\": 1},\n")
+	}
+	buf.WriteString("  null\n]\n\"end\"")
+
+	values, lines, err := readAll(t, dynjson.NewLineReader(&buf))
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(values))
+	assert.Equal(t, []int{1, 10004}, lines)
+}
+
+func TestLineWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w := dynjson.NewLineWriter(&buf, dynjson.Indent("  "), dynjson.EscapeHTML(false))
+
+	assert.Nil(t, w.WriteValue(dynjson.JsonObject{"a": 1, "b": []interface{}{"<x>"}}))
+	assert.Nil(t, w.WriteValue(dynjson.JsonList{}))
+	assert.Nil(t, w.WriteValue(dynjson.NewValue("line\nbreak")))
+	assert.True(t, errors.Is(w.WriteValue(math.Inf(1)), dynjson.ErrOutOfRange))
+	assert.Equal(t, "", buf.String())
+
+	assert.Nil(t, w.Flush())
+	assert.Equal(t, "{\"a\":1,\"b\":[\"<x>\"]}\n[]\n\"line\\nbreak\"\n", buf.String())
+
+	values, _, err := readAll(t, dynjson.NewLineReader(&buf))
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(values))
+}
@@ -0,0 +1,375 @@
+package dynjson
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// MarshalOption changes the output of Marshal and WriteJSON.
+type MarshalOption func(*marshalConfig)
+
+type marshalConfig struct {
+	indent          string
+	sortKeys        bool
+	escapeHTML      bool
+	asciiOnly       bool
+	floatFormat     byte
+	floatPrecision  int
+	trailingNewline bool
+	canonical       bool
+}
+
+func newMarshalConfig(opts []MarshalOption) marshalConfig {
+	config := marshalConfig{escapeHTML: true, floatPrecision: -1}
+	for _, opt := range opts {
+		opt(&config)
+	}
+	return config
+}
+
+// Indent puts every member and element on its own line, indented by the given string per level, e.g. "  ".
+func Indent(indent string) MarshalOption {
+	return func(config *marshalConfig) {
+		config.indent = indent
+	}
+}
+
+// SortKeys writes the members of objects sorted by their keys.
+// Objects based on maps don't have an order, so their keys are always sorted.
+func SortKeys() MarshalOption {
+	return func(config *marshalConfig) {
+		config.sortKeys = true
+	}
+}
+
+// EscapeHTML sets whether <, > and & are escaped inside of strings, so the json can be embedded into HTML safely.
+// They are escaped by default, like encoding/json does.
+func EscapeHTML(escape bool) MarshalOption {
+	return func(config *marshalConfig) {
+		config.escapeHTML = escape
+	}
+}
+
+// ASCIIOnly escapes all non-ASCII characters inside of strings, e.g. "ä" is written as "\u00e4".
+func ASCIIOnly() MarshalOption {
+	return func(config *marshalConfig) {
+		config.asciiOnly = true
+	}
+}
+
+// FloatFixed writes floats with the given number of fractional digits and without exponent, e.g. 1.50 for
+// a precision of 2. Integers and numbers parsed with UseNumber are written unchanged.
+func FloatFixed(precision int) MarshalOption {
+	return func(config *marshalConfig) {
+		config.floatFormat = 'f'
+		config.floatPrecision = precision
+	}
+}
+
+// FloatNoExponent writes floats with the shortest representation, which doesn't use an exponent, e.g. 1e21 is written
+// as 1000000000000000000000.
+func FloatNoExponent() MarshalOption {
+	return func(config *marshalConfig) {
+		config.floatFormat = 'f'
+		config.floatPrecision = -1
+	}
+}
+
+// TrailingNewline adds a newline after the json value.
+func TrailingNewline() MarshalOption {
+	return func(config *marshalConfig) {
+		config.trailingNewline = true
+	}
+}
+
+// Marshal returns the json encoding of the object. By default, it is compact, the keys are sorted and floats use
+// the shortest representation, just like encoding/json. Options change the output, e.g. Indent("  ").
+// In contrast to ToString, it returns an error, e.g. for NaN.
+func (j JsonObject) Marshal(opts ...MarshalOption) ([]byte, error) {
+	return marshalNode(j, newMarshalConfig(opts))
+}
+
+// WriteJSON writes the json encoding of the object to w. See Marshal.
+func (j JsonObject) WriteJSON(w io.Writer, opts ...MarshalOption) (int64, error) {
+	return writeNode(w, j, newMarshalConfig(opts))
+}
+
+// WriteTo implements io.WriterTo. It writes the compact json encoding of the object to w.
+func (j JsonObject) WriteTo(w io.Writer) (int64, error) {
+	return j.WriteJSON(w)
+}
+
+// Marshal returns the json encoding of the list. See JsonObject.Marshal.
+func (j JsonList) Marshal(opts ...MarshalOption) ([]byte, error) {
+	return marshalNode(j, newMarshalConfig(opts))
+}
+
+// WriteJSON writes the json encoding of the list to w. See JsonObject.Marshal.
+func (j JsonList) WriteJSON(w io.Writer, opts ...MarshalOption) (int64, error) {
+	return writeNode(w, j, newMarshalConfig(opts))
+}
+
+// WriteTo implements io.WriterTo. It writes the compact json encoding of the list to w.
+func (j JsonList) WriteTo(w io.Writer) (int64, error) {
+	return j.WriteJSON(w)
+}
+
+// Marshal returns the json encoding of the value. A missing value is encoded as null. See JsonObject.Marshal.
+func (v Value) Marshal(opts ...MarshalOption) ([]byte, error) {
+	return marshalNode(v.data, newMarshalConfig(opts))
+}
+
+// WriteJSON writes the json encoding of the value to w. See JsonObject.Marshal.
+func (v Value) WriteJSON(w io.Writer, opts ...MarshalOption) (int64, error) {
+	return writeNode(w, v.data, newMarshalConfig(opts))
+}
+
+// WriteTo implements io.WriterTo. It writes the compact json encoding of the value to w.
+func (v Value) WriteTo(w io.Writer) (int64, error) {
+	return v.WriteJSON(w)
+}
+
+func marshalNode(node interface{}, config marshalConfig) ([]byte, error) {
+	e := encoder{config: config}
+	if err := e.encode(node, ""); err != nil {
+		return nil, err
+	}
+	if config.trailingNewline {
+		e.buf.WriteByte('\n')
+	}
+	return e.buf.Bytes(), nil
+}
+
+func writeNode(w io.Writer, node interface{}, config marshalConfig) (int64, error) {
+	data, err := marshalNode(node, config)
+	if err != nil {
+		return 0, err
+	}
+	n, err := w.Write(data)
+	return int64(n), err
+}
+
+// encoder writes a json tree into a buffer.
+type encoder struct {
+	config marshalConfig
+	buf    bytes.Buffer
+}
+
+func (e *encoder) encode(node interface{}, path string) error {
+	node = normalize(node)
+
+	if obj, ok := node.(*OrderedObject); ok && obj != nil {
+		return e.encodeObject(obj.values, obj.Keys(), path)
+	}
+	if obj, ok := convToObject(node); ok {
+		return e.encodeObject(obj, nil, path)
+	}
+	if n, ok := listLen(node); ok {
+		return e.encodeList(node, n, path)
+	}
+	if e.config.canonical && kindOf(node) == KindNumber {
+		return e.encodeCanonicalNumber(node, path)
+	}
+
+	switch d := node.(type) {
+	case nil:
+		e.buf.WriteString("null")
+	case bool:
+		e.buf.WriteString(strconv.FormatBool(d))
+	case string:
+		e.encodeString(d)
+	case float64:
+		return e.encodeFloat(d, path)
+	case int64:
+		e.buf.WriteString(strconv.FormatInt(d, 10))
+	case uint64:
+		e.buf.WriteString(strconv.FormatUint(d, 10))
+	case json.Number:
+		if d == "" {
+			d = "0"
+		}
+		e.buf.WriteString(string(d))
+	default:
+		// Other Go values, e.g. structs, are converted with encoding/json first.
+		data, err := json.Marshal(d)
+		if err != nil {
+			return err
+		}
+		var raw interface{}
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.UseNumber()
+		if err := dec.Decode(&raw); err != nil {
+			return err
+		}
+		return e.encode(raw, path)
+	}
+
+	return nil
+}
+
+// encodeObject writes the members of obj. Unless SortKeys is set, they are written in the order of keys.
+// Without keys, they are sorted. Canonical json has its own order.
+func (e *encoder) encodeObject(obj JsonObject, keys []string, path string) error {
+	if obj == nil {
+		e.buf.WriteString("null")
+		return nil
+	}
+	if len(obj) == 0 {
+		e.buf.WriteString("{}")
+		return nil
+	}
+
+	switch {
+	case e.config.canonical:
+		keys = canonicalKeys(obj)
+	case keys == nil || e.config.sortKeys:
+		keys = objectKeys(obj)
+	}
+
+	e.buf.WriteByte('{')
+	for i, key := range keys {
+		if i > 0 {
+			e.buf.WriteByte(',')
+		}
+		e.newline(path + "/")
+		e.encodeString(key)
+		e.buf.WriteByte(':')
+		if e.config.indent != "" {
+			e.buf.WriteByte(' ')
+		}
+		if err := e.encode(obj[key], path+"/"+EscapePointerSegment(key)); err != nil {
+			return err
+		}
+	}
+	e.newline(path)
+	e.buf.WriteByte('}')
+	return nil
+}
+
+func (e *encoder) encodeList(list interface{}, n int, path string) error {
+	if isNilList(list) {
+		e.buf.WriteString("null")
+		return nil
+	}
+	if n == 0 {
+		e.buf.WriteString("[]")
+		return nil
+	}
+
+	e.buf.WriteByte('[')
+	for i := 0; i < n; i++ {
+		if i > 0 {
+			e.buf.WriteByte(',')
+		}
+		e.newline(path + "/")
+		if err := e.encode(listGet(list, i), path+"/"+strconv.Itoa(i)); err != nil {
+			return err
+		}
+	}
+	e.newline(path)
+	e.buf.WriteByte(']')
+	return nil
+}
+
+// newline starts a new line, when the output is indented. The depth is the number of slashes in the path.
+func (e *encoder) newline(path string) {
+	if e.config.indent == "" {
+		return
+	}
+
+	e.buf.WriteByte('\n')
+	for i := 0; i < len(path); i++ {
+		if path[i] == '/' {
+			e.buf.WriteString(e.config.indent)
+		}
+	}
+}
+
+func (e *encoder) encodeFloat(f float64, path string) error {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return &Error{Kind: ErrorKindOutOfRange, Path: path, Reason: fmt.Sprintf("%v can't be represented in json", f)}
+	}
+
+	format := e.config.floatFormat
+	if format == 0 {
+		// Like encoding/json, use an exponent for very small and very large numbers.
+		format = 'f'
+		if abs := math.Abs(f); abs != 0 && (abs < 1e-6 || abs >= 1e21) {
+			format = 'e'
+		}
+	}
+
+	b := strconv.AppendFloat(nil, f, format, e.config.floatPrecision, 64)
+	if format == 'e' {
+		// Clean up e-09 to e-9.
+		n := len(b)
+		if n >= 4 && b[n-4] == 'e' && b[n-3] == '-' && b[n-2] == '0' {
+			b[n-2] = b[n-1]
+			b = b[:n-1]
+		}
+	}
+	e.buf.Write(b)
+	return nil
+}
+
+const hexDigits = "0123456789abcdef"
+
+func (e *encoder) encodeString(s string) {
+	e.buf.WriteByte('"')
+	for i := 0; i < len(s); {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		i += size
+
+		switch {
+		case r == '"' || r == '\\':
+			e.buf.WriteByte('\\')
+			e.buf.WriteByte(byte(r))
+		case r == '\n':
+			e.buf.WriteString(`\n`)
+		case r == '\r':
+			e.buf.WriteString(`\r`)
+		case r == '\t':
+			e.buf.WriteString(`\t`)
+		case e.config.canonical && r == '\b':
+			e.buf.WriteString(`\b`)
+		case e.config.canonical && r == '\f':
+			e.buf.WriteString(`\f`)
+		case r < 0x20, e.config.escapeHTML && (r == '<' || r == '>' || r == '&'):
+			e.writeUnicodeEscape(r)
+		case !e.config.canonical && (r == '\u2028' || r == '\u2029'):
+			e.writeUnicodeEscape(r)
+		case r == utf8.RuneError && size == 1:
+			// Invalid UTF-8 is replaced, like encoding/json does.
+			if e.config.asciiOnly {
+				e.writeUnicodeEscape(utf8.RuneError)
+			} else {
+				e.buf.WriteRune(utf8.RuneError)
+			}
+		case r >= utf8.RuneSelf && e.config.asciiOnly:
+			if r > 0xffff {
+				r1, r2 := utf16.EncodeRune(r)
+				e.writeUnicodeEscape(r1)
+				e.writeUnicodeEscape(r2)
+			} else {
+				e.writeUnicodeEscape(r)
+			}
+		default:
+			e.buf.WriteString(string(r))
+		}
+	}
+	e.buf.WriteByte('"')
+}
+
+func (e *encoder) writeUnicodeEscape(r rune) {
+	e.buf.WriteString(`\u`)
+	e.buf.WriteByte(hexDigits[r>>12&0xf])
+	e.buf.WriteByte(hexDigits[r>>8&0xf])
+	e.buf.WriteByte(hexDigits[r>>4&0xf])
+	e.buf.WriteByte(hexDigits[r&0xf])
+}
@@ -0,0 +1,92 @@
+package dynjson_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"math"
+	"testing"
+
+	"github.com/go-schild/dynjson"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJsonObject_Marshal(t *testing.T) {
+	j, _ := dynjson.ParseObject(`{"b": [1, {"c": null}, []], "a": "x", "e": {}}`)
+
+	data, err := j.Marshal()
+	assert.Nil(t, err)
+	assert.Equal(t, `{"a":"x","b":[1,{"c":null},[]],"e":{}}`, string(data))
+
+	data, err = j.Marshal(dynjson.Indent("  "), dynjson.TrailingNewline())
+	assert.Nil(t, err)
+	assert.Equal(t, "{\n  \"a\": \"x\",\n  \"b\": [\n    1,\n    {\n      \"c\": null\n    },\n    []\n  ],\n  \"e\": {}\n}\n", string(data))
+
+	// The default output is the same as the one of encoding/json.
+	j, _ = dynjson.ParseObject(`{"s": "<a&b>   ä \u0001 \"\\", "f": [1e21, 1e-7, 0.1, -0, 123456789]}`)
+	data, err = j.Marshal()
+	assert.Nil(t, err)
+	assert.Equal(t, j.ToString(), string(data))
+
+	j = dynjson.JsonObject{"f": math.NaN()}
+	_, err = j.Marshal()
+	assert.True(t, errors.Is(err, dynjson.ErrOutOfRange))
+	assert.Equal(t, "", j.ToString())
+}
+
+func TestMarshal_Strings(t *testing.T) {
+	v := dynjson.NewValue("<ä😀>")
+
+	data, _ := v.Marshal()
+	assert.Equal(t, `"\u003cä😀\u003e"`, string(data))
+
+	data, _ = v.Marshal(dynjson.EscapeHTML(false))
+	assert.Equal(t, `"<ä😀>"`, string(data))
+
+	data, _ = v.Marshal(dynjson.EscapeHTML(false), dynjson.ASCIIOnly())
+	assert.Equal(t, `"<\u00e4\ud83d\ude00>"`, string(data))
+
+	data, _ = dynjson.NewValue("a\xffb").Marshal()
+	assert.Equal(t, "\"a�b\"", string(data))
+}
+
+func TestMarshal_Floats(t *testing.T) {
+	list := dynjson.NewJsonList([]interface{}{1.5, 1e21, 2.0, int64(3), 1e-7})
+
+	data, _ := list.Marshal()
+	assert.Equal(t, `[1.5,1e+21,2,3,1e-7]`, string(data))
+
+	data, _ = list.Marshal(dynjson.FloatNoExponent())
+	assert.Equal(t, `[1.5,1000000000000000000000,2,3,0.0000001]`, string(data))
+
+	data, _ = list.Marshal(dynjson.FloatFixed(2))
+	assert.Equal(t, `[1.50,1000000000000000000000.00,2.00,3,0.00]`, string(data))
+
+	n, _ := dynjson.ParseList(`[1.500]`, dynjson.UseNumber())
+	data, _ = n.Marshal(dynjson.FloatFixed(1))
+	assert.Equal(t, `[1.500]`, string(data))
+}
+
+func TestMarshal_WriteTo(t *testing.T) {
+	j := dynjson.JsonObject{"a": 1}
+
+	var buf bytes.Buffer
+	n, err := j.WriteJSON(&buf, dynjson.TrailingNewline())
+	assert.Nil(t, err)
+	assert.Equal(t, int64(8), n)
+	assert.Equal(t, "{\"a\":1}\n", buf.String())
+
+	var w io.WriterTo = dynjson.NewValue(j)
+	buf.Reset()
+	_, err = w.WriteTo(&buf)
+	assert.Nil(t, err)
+	assert.Equal(t, `{"a":1}`, buf.String())
+
+	buf.Reset()
+	_, err = dynjson.NewJsonList(nil).WriteTo(&buf)
+	assert.Nil(t, err)
+	assert.Equal(t, `[]`, buf.String())
+
+	data, _ := dynjson.Value{}.Marshal()
+	assert.Equal(t, `null`, string(data))
+}
@@ -0,0 +1,311 @@
+package dynjson
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+// MergePatch applies a JSON Merge Patch (RFC 7386) to the json object.
+// Fields of the patch replace the fields of the object, nested objects are merged recursively and
+// fields which are null in the patch are removed from the object. Lists are replaced as a whole.
+func (j JsonObject) MergePatch(patch JsonObject) {
+	result, _ := convToObject(mergePatchNode(j, patch))
+
+	for key := range j {
+		if _, ok := result[key]; !ok {
+			delete(j, key)
+		}
+	}
+	for key, val := range result {
+		j[key] = val
+	}
+}
+
+// MergePatch applies a JSON Merge Patch (RFC 7386) to any json value and returns the result.
+// When the patch is not an object, it replaces the target completely. Ordered objects stay ordered: existing keys
+// keep their place and new keys are added in the order of the patch.
+func MergePatch(target, patch interface{}) Value {
+	return Value{data: mergePatchNode(normalize(target), normalize(patch)), exists: true}
+}
+
+func mergePatchNode(target, patch interface{}) interface{} {
+	patchObj, ok := convToObject(patch)
+	if !ok {
+		return cloneNode(patch)
+	}
+
+	// A patch applied to a non-object creates a new object, which is ordered like the patch.
+	ordered := isOrdered(target)
+	if _, ok := convToObject(target); !ok {
+		ordered = isOrdered(patch)
+	}
+
+	result := copyObject(target)
+	for _, key := range memberKeys(patch) {
+		val := patchObj[key]
+		if val == nil {
+			result.Delete(key)
+			continue
+		}
+		result.Set(key, mergePatchNode(result.values[key], val))
+	}
+
+	return objectResult(result, ordered)
+}
+
+// CreateMergePatch creates a JSON Merge Patch (RFC 7386), which turns from into to.
+// Merge patches can't set a field to null, because null removes fields. When to contains null values which are
+// not part of from, an error is returned.
+func CreateMergePatch(from, to JsonObject) (JsonObject, error) {
+	return createMergePatch("", from, to)
+}
+
+func createMergePatch(path string, from, to JsonObject) (JsonObject, error) {
+	patch := JsonObject{}
+
+	for _, key := range objectKeys(from) {
+		if _, ok := to[key]; !ok {
+			patch[key] = nil
+		}
+	}
+
+	for _, key := range objectKeys(to) {
+		childPath := path + "/" + EscapePointerSegment(key)
+		toVal := to[key]
+		fromVal, exists := from[key]
+
+		if exists && nodesEqual(fromVal, toVal) {
+			continue
+		}
+
+		fromObj, fromIsObj := convToObject(fromVal)
+		toObj, toIsObj := convToObject(toVal)
+		if exists && fromIsObj && toIsObj {
+			childPatch, err := createMergePatch(childPath, fromObj, toObj)
+			if err != nil {
+				return nil, err
+			}
+			patch[key] = childPatch
+			continue
+		}
+
+		if toVal == nil {
+			return nil, fmt.Errorf("dynjson: merge patch can't set %q to null", childPath)
+		}
+		if toIsObj {
+			// The patch is applied to a non-object, so all of its null fields would vanish.
+			if nullPath, ok := findNullMember(childPath, toObj); ok {
+				return nil, fmt.Errorf("dynjson: merge patch can't set %q to null", nullPath)
+			}
+		}
+		patch[key] = cloneNode(toVal)
+	}
+
+	return patch, nil
+}
+
+func findNullMember(path string, obj JsonObject) (string, bool) {
+	for _, key := range objectKeys(obj) {
+		childPath := path + "/" + EscapePointerSegment(key)
+		if obj[key] == nil {
+			return childPath, true
+		}
+		if child, ok := convToObject(obj[key]); ok {
+			if nullPath, ok := findNullMember(childPath, child); ok {
+				return nullPath, true
+			}
+		}
+	}
+	return "", false
+}
+
+// ArrayStrategy decides how DeepMerge combines two lists.
+type ArrayStrategy int
+
+const (
+	// ArrayReplace replaces the destination list by the source list.
+	ArrayReplace ArrayStrategy = iota
+	// ArrayAppend appends the elements of the source list to the destination list.
+	ArrayAppend
+	// ArrayMergeByIndex merges elements with the same index, additional source elements are appended.
+	ArrayMergeByIndex
+	// ArrayMergeByKey merges objects which have the same value in the key field, see WithArrayMergeKey.
+	// Other source elements are appended.
+	ArrayMergeByKey
+)
+
+// ConflictFunc is called by DeepMerge, when both objects contain a value at the same path, which can't be merged,
+// e.g. two different strings or a number and an object. Path is the JSON pointer of the value.
+// It returns the value to use or an error, which aborts the merge.
+type ConflictFunc func(path string, dst, src Value) (Value, error)
+
+// MergeOption changes the behaviour of DeepMerge.
+type MergeOption func(*mergeConfig)
+
+type mergeConfig struct {
+	arrays   ArrayStrategy
+	key      string
+	conflict ConflictFunc
+}
+
+// WithArrayStrategy sets how lists are merged. The default is ArrayReplace.
+func WithArrayStrategy(strategy ArrayStrategy) MergeOption {
+	return func(config *mergeConfig) {
+		config.arrays = strategy
+	}
+}
+
+// WithArrayMergeKey merges lists of objects by the value of the given field, e.g. "id".
+func WithArrayMergeKey(field string) MergeOption {
+	return func(config *mergeConfig) {
+		config.arrays = ArrayMergeByKey
+		config.key = field
+	}
+}
+
+// WithConflictHandler sets a function which resolves conflicts. By default, the source value wins.
+func WithConflictHandler(fn ConflictFunc) MergeOption {
+	return func(config *mergeConfig) {
+		config.conflict = fn
+	}
+}
+
+// DeepMerge merges src into the json object. Nested objects are merged recursively, lists according to the
+// array strategy. In contrast to MergePatch, null values in src don't remove fields, they are merged like any other
+// value. When the conflict handler returns an error, the json object stays untouched.
+// Nested ordered objects stay ordered, new keys are added in the order of src.
+func (j JsonObject) DeepMerge(src JsonObject, opts ...MergeOption) error {
+	config := mergeConfig{}
+	for _, opt := range opts {
+		opt(&config)
+	}
+	if config.arrays == ArrayMergeByKey && config.key == "" {
+		return errors.New("dynjson: ArrayMergeByKey requires a key, use WithArrayMergeKey")
+	}
+
+	merged, err := deepMergeObjects("", j, src, &config)
+	if err != nil {
+		return err
+	}
+
+	result, _ := convToObject(merged)
+	for key, val := range result {
+		j[key] = val
+	}
+	return nil
+}
+
+func deepMergeObjects(path string, dst, src interface{}, config *mergeConfig) (interface{}, error) {
+	dstObj, _ := convToObject(dst)
+	srcObj, _ := convToObject(src)
+
+	result := copyObject(dst)
+	for _, key := range memberKeys(src) {
+		srcVal := srcObj[key]
+		dstVal, exists := dstObj[key]
+		if !exists {
+			result.Set(key, cloneNode(srcVal))
+			continue
+		}
+
+		merged, err := deepMergeNodes(path+"/"+EscapePointerSegment(key), dstVal, srcVal, config)
+		if err != nil {
+			return nil, err
+		}
+		result.Set(key, merged)
+	}
+
+	return objectResult(result, isOrdered(dst)), nil
+}
+
+func deepMergeNodes(path string, dst, src interface{}, config *mergeConfig) (interface{}, error) {
+	if _, ok := convToObject(dst); ok {
+		if _, ok := convToObject(src); ok {
+			return deepMergeObjects(path, dst, src, config)
+		}
+	}
+
+	if _, ok := listLen(dst); ok {
+		if _, ok := listLen(src); ok {
+			return deepMergeLists(path, dst, src, config)
+		}
+	}
+
+	if nodesEqual(dst, src) {
+		return dst, nil
+	}
+
+	if config.conflict != nil {
+		resolved, err := config.conflict(path, Value{data: dst, exists: true}, Value{data: src, exists: true})
+		if err != nil {
+			return nil, err
+		}
+		return cloneNode(normalize(resolved)), nil
+	}
+
+	return cloneNode(src), nil
+}
+
+func deepMergeLists(path string, dst, src interface{}, config *mergeConfig) (interface{}, error) {
+	dstLen, _ := listLen(dst)
+	srcLen, _ := listLen(src)
+
+	if config.arrays == ArrayReplace {
+		return cloneNode(src), nil
+	}
+
+	result := make([]interface{}, 0, dstLen+srcLen)
+	for i := 0; i < dstLen; i++ {
+		result = append(result, listGet(dst, i))
+	}
+
+	for i := 0; i < srcLen; i++ {
+		srcVal := listGet(src, i)
+
+		target := -1
+		switch config.arrays {
+		case ArrayMergeByIndex:
+			if i < dstLen {
+				target = i
+			}
+		case ArrayMergeByKey:
+			target = findByKey(result[:dstLen], config.key, srcVal)
+		}
+
+		if target < 0 {
+			result = append(result, cloneNode(srcVal))
+			continue
+		}
+
+		merged, err := deepMergeNodes(path+"/"+strconv.Itoa(target), result[target], srcVal, config)
+		if err != nil {
+			return nil, err
+		}
+		result[target] = merged
+	}
+
+	return NewJsonList(result), nil
+}
+
+// findByKey returns the index of the object in list, whose key field equals the key field of val.
+func findByKey(list []interface{}, key string, val interface{}) int {
+	obj, ok := convToObject(val)
+	if !ok {
+		return -1
+	}
+	keyVal, ok := obj[key]
+	if !ok {
+		return -1
+	}
+
+	for i, item := range list {
+		if itemObj, ok := convToObject(item); ok {
+			if itemKey, ok := itemObj[key]; ok && nodesEqual(itemKey, keyVal) {
+				return i
+			}
+		}
+	}
+
+	return -1
+}
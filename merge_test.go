@@ -0,0 +1,157 @@
+package dynjson_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/go-schild/dynjson"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMergePatch(t *testing.T) {
+	// Examples from RFC 7386, appendix A
+	testData := [][3]string{
+		{`{"a":"b"}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"b"}`, `{"b":"c"}`, `{"a":"b","b":"c"}`},
+		{`{"a":"b"}`, `{"a":null}`, `{}`},
+		{`{"a":"b","b":"c"}`, `{"a":null}`, `{"b":"c"}`},
+		{`{"a":["b"]}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"c"}`, `{"a":["b"]}`, `{"a":["b"]}`},
+		{`{"a":{"b":"c"}}`, `{"a":{"b":"d","c":null}}`, `{"a":{"b":"d"}}`},
+		{`{"a":[{"b":"c"}]}`, `{"a":[1]}`, `{"a":[1]}`},
+		{`["a","b"]`, `["c","d"]`, `["c","d"]`},
+		{`{"a":"b"}`, `["c"]`, `["c"]`},
+		{`{"a":"foo"}`, `null`, `null`},
+		{`{"a":"foo"}`, `"bar"`, `"bar"`},
+		{`{"e":null}`, `{"a":1}`, `{"e":null,"a":1}`},
+		{`[1,2]`, `{"a":"b","c":null}`, `{"a":"b"}`},
+		{`{}`, `{"a":{"bb":{"ccc":null}}}`, `{"a":{"bb":{}}}`},
+	}
+
+	for _, data := range testData {
+		target, err := dynjson.Parse([]byte(data[0]))
+		assert.Nil(t, err)
+		patch, err := dynjson.Parse([]byte(data[1]))
+		assert.Nil(t, err)
+
+		result := dynjson.MergePatch(target, patch)
+		assert.JSONEq(t, data[2], result.ToString(), data[1])
+		assert.JSONEq(t, data[0], target.ToString())
+	}
+}
+
+func TestJsonObject_MergePatch(t *testing.T) {
+	j, err := dynjson.ParseObject(`{"title": "Goodbye!", "author": {"givenName": "John", "familyName": "Doe"}, "tags": ["example", "sample"], "content": "This will be unchanged"}`)
+	assert.Nil(t, err)
+
+	patch, err := dynjson.ParseObject(`{"title": "Hello!", "phoneNumber": "+01-123-456-7890", "author": {"familyName": null}, "tags": ["example"]}`)
+	assert.Nil(t, err)
+
+	j.MergePatch(patch)
+	assert.JSONEq(t, `{"title": "Hello!", "author": {"givenName": "John"}, "tags": ["example"], "content": "This will be unchanged", "phoneNumber": "+01-123-456-7890"}`, j.ToString())
+}
+
+func TestCreateMergePatch(t *testing.T) {
+	testData := [][2]string{
+		{`{}`, `{}`},
+		{`{"a": 1}`, `{"a": 2}`},
+		{`{"a": 1, "b": 2}`, `{"a": 1}`},
+		{`{"a": {"b": 1, "c": 2}}`, `{"a": {"b": 1, "c": 3, "d": [1]}}`},
+		{`{"a": [1, 2]}`, `{"a": [1, null]}`},
+		{`{"a": 1}`, `{"a": {"b": {"c": 1}}}`},
+		{`{"a": null}`, `{"a": null, "b": 1}`},
+	}
+
+	for _, data := range testData {
+		from, err := dynjson.ParseObject(data[0])
+		assert.Nil(t, err)
+		to, err := dynjson.ParseObject(data[1])
+		assert.Nil(t, err)
+
+		patch, err := dynjson.CreateMergePatch(from, to)
+		assert.Nil(t, err, data[1])
+
+		from.MergePatch(patch)
+		assert.JSONEq(t, data[1], from.ToString())
+	}
+
+	from, _ := dynjson.ParseObject(`{"a": {"b": 1}}`)
+	to, _ := dynjson.ParseObject(`{"a": {"b": 1}, "c": 2}`)
+	patch, err := dynjson.CreateMergePatch(from, to)
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{"c": 2}`, patch.ToString())
+
+	for _, data := range [][2]string{
+		{`{"a": 1}`, `{"a": null}`},
+		{`{}`, `{"a": null}`},
+		{`{"a": 1}`, `{"a": {"b": null}}`},
+	} {
+		from, _ := dynjson.ParseObject(data[0])
+		to, _ := dynjson.ParseObject(data[1])
+		_, err := dynjson.CreateMergePatch(from, to)
+		assert.NotNil(t, err, data[1])
+	}
+}
+
+func TestJsonObject_DeepMerge(t *testing.T) {
+	defaults, err := dynjson.ParseObject(`{"server": {"host": "localhost", "port": 80}, "debug": false, "tags": ["a"]}`)
+	assert.Nil(t, err)
+	env, err := dynjson.ParseObject(`{"server": {"port": 8080}, "tags": ["b"], "extra": null}`)
+	assert.Nil(t, err)
+
+	assert.Nil(t, defaults.DeepMerge(env))
+	assert.JSONEq(t, `{"server": {"host": "localhost", "port": 8080}, "debug": false, "tags": ["b"], "extra": null}`, defaults.ToString())
+}
+
+func TestJsonObject_DeepMerge_Arrays(t *testing.T) {
+	testData := []struct {
+		opts   []dynjson.MergeOption
+		result string
+	}{
+		{nil, `{"a": [{"id": 2, "x": 3}, 4]}`},
+		{[]dynjson.MergeOption{dynjson.WithArrayStrategy(dynjson.ArrayReplace)}, `{"a": [{"id": 2, "x": 3}, 4]}`},
+		{[]dynjson.MergeOption{dynjson.WithArrayStrategy(dynjson.ArrayAppend)}, `{"a": [{"id": 1, "x": 1}, {"id": 2, "x": 2}, {"id": 2, "x": 3}, 4]}`},
+		{[]dynjson.MergeOption{dynjson.WithArrayStrategy(dynjson.ArrayMergeByIndex)}, `{"a": [{"id": 2, "x": 3}, 4]}`},
+		{[]dynjson.MergeOption{dynjson.WithArrayMergeKey("id")}, `{"a": [{"id": 1, "x": 1}, {"id": 2, "x": 3}, 4]}`},
+	}
+
+	for _, data := range testData {
+		dst, err := dynjson.ParseObject(`{"a": [{"id": 1, "x": 1}, {"id": 2, "x": 2}]}`)
+		assert.Nil(t, err)
+		src, err := dynjson.ParseObject(`{"a": [{"id": 2, "x": 3}, 4]}`)
+		assert.Nil(t, err)
+
+		assert.Nil(t, dst.DeepMerge(src, data.opts...))
+		assert.JSONEq(t, data.result, dst.ToString())
+		assert.JSONEq(t, `{"a": [{"id": 2, "x": 3}, 4]}`, src.ToString())
+	}
+
+	dst := dynjson.NewJsonObject()
+	assert.NotNil(t, dst.DeepMerge(dynjson.NewJsonObject(), dynjson.WithArrayStrategy(dynjson.ArrayMergeByKey)))
+}
+
+func TestJsonObject_DeepMerge_Conflicts(t *testing.T) {
+	dst, err := dynjson.ParseObject(`{"a": 1, "b": {"c": "x"}, "d": [1], "e": "same"}`)
+	assert.Nil(t, err)
+	src, err := dynjson.ParseObject(`{"a": 2, "b": {"c": "y"}, "d": {"e": 1}, "e": "same"}`)
+	assert.Nil(t, err)
+
+	var paths []string
+	err = dst.DeepMerge(src, dynjson.WithConflictHandler(func(path string, d, s dynjson.Value) (dynjson.Value, error) {
+		paths = append(paths, path)
+		if path == "/a" {
+			return dynjson.NewValue(d.Int() + s.Int()), nil
+		}
+		return d, nil
+	}))
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"/a", "/b/c", "/d"}, paths)
+	assert.JSONEq(t, `{"a": 3, "b": {"c": "x"}, "d": [1], "e": "same"}`, dst.ToString())
+
+	failure := errors.New("conflict")
+	err = dst.DeepMerge(src, dynjson.WithConflictHandler(func(path string, d, s dynjson.Value) (dynjson.Value, error) {
+		return dynjson.Value{}, failure
+	}))
+	assert.Equal(t, failure, err)
+	assert.JSONEq(t, `{"a": 3, "b": {"c": "x"}, "d": [1], "e": "same"}`, dst.ToString())
+}
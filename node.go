@@ -0,0 +1,300 @@
+package dynjson
+
+import (
+	"math"
+	"sort"
+)
+
+// The functions in this file work on the raw nodes of a json tree.
+// Lists are either a JsonList or, when they come from encoding/json, a []interface{}.
+// Functions which change the length of a list return the new list, which has to be written back to its parent.
+
+func listLen(node interface{}) (int, bool) {
+	switch l := node.(type) {
+	case []interface{}:
+		return len(l), true
+	case JsonListRaw:
+		return len(l), true
+	case JsonList:
+		return len(l), true
+	}
+
+	return 0, false
+}
+
+func listGet(node interface{}, index int) interface{} {
+	switch l := node.(type) {
+	case []interface{}:
+		return l[index]
+	case JsonListRaw:
+		return l[index]
+	case JsonList:
+		return l[index].data
+	}
+
+	return nil
+}
+
+func listSet(node interface{}, index int, value interface{}) interface{} {
+	switch l := node.(type) {
+	case []interface{}:
+		l[index] = value
+	case JsonListRaw:
+		l[index] = value
+	case JsonList:
+		l[index] = JsonListItem{data: value}
+	}
+
+	return node
+}
+
+func listInsert(node interface{}, index int, value interface{}) interface{} {
+	switch l := node.(type) {
+	case []interface{}:
+		l = append(l, nil)
+		copy(l[index+1:], l[index:])
+		l[index] = value
+		return l
+	case JsonListRaw:
+		l = append(l, nil)
+		copy(l[index+1:], l[index:])
+		l[index] = value
+		return l
+	case JsonList:
+		l = append(l, JsonListItem{})
+		copy(l[index+1:], l[index:])
+		l[index] = JsonListItem{data: value}
+		return l
+	}
+
+	return node
+}
+
+// listPad appends count nulls to a list.
+func listPad(node interface{}, count int) interface{} {
+	switch l := node.(type) {
+	case []interface{}:
+		return append(l, make([]interface{}, count)...)
+	case JsonListRaw:
+		return append(l, make(JsonListRaw, count)...)
+	case JsonList:
+		return append(l, make(JsonList, count)...)
+	}
+
+	return node
+}
+
+// listRemove returns a new list without the element at the index. The list itself isn't changed, because it can be
+// shared with other objects.
+func listRemove(node interface{}, index int) interface{} {
+	switch l := node.(type) {
+	case []interface{}:
+		return append(append(make([]interface{}, 0, len(l)-1), l[:index]...), l[index+1:]...)
+	case JsonListRaw:
+		return append(append(make(JsonListRaw, 0, len(l)-1), l[:index]...), l[index+1:]...)
+	case JsonList:
+		return append(append(make(JsonList, 0, len(l)-1), l[:index]...), l[index+1:]...)
+	}
+
+	return node
+}
+
+// isOrdered returns true, when node is an *OrderedObject.
+func isOrdered(node interface{}) bool {
+	obj, ok := node.(*OrderedObject)
+	return ok && obj != nil
+}
+
+// memberKeys returns the keys of the object node: in their order for an *OrderedObject, sorted otherwise.
+func memberKeys(node interface{}) []string {
+	if isOrdered(node) {
+		return node.(*OrderedObject).Keys()
+	}
+	obj, _ := convToObject(node)
+	return objectKeys(obj)
+}
+
+// copyObject returns a copy of the object node, which shares the members, so they can be set and deleted without
+// changing node. The copy keeps the order of the keys. When node isn't an object, the copy is empty.
+func copyObject(node interface{}) *OrderedObject {
+	obj, _ := convToObject(node)
+	values := make(map[string]interface{}, len(obj))
+	for key, val := range obj {
+		values[key] = val
+	}
+	return &OrderedObject{keys: memberKeys(node), values: values}
+}
+
+// objectResult returns an object built by copyObject as *OrderedObject, when ordered is set, and as JsonObject
+// otherwise.
+func objectResult(obj *OrderedObject, ordered bool) interface{} {
+	if ordered {
+		return obj
+	}
+	return JsonObject(obj.values)
+}
+
+// objectKeys returns the keys of an object in a stable, sorted order.
+func objectKeys(obj JsonObject) []string {
+	keys := make([]string, 0, len(obj))
+	for key := range obj {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// nodesEqual compares two json values. Numbers are compared by value, regardless of their Go type.
+func nodesEqual(a, b interface{}) bool {
+	return nodesEqualTolerance(a, b, 0)
+}
+
+// nodesEqualTolerance works like nodesEqual, but numbers, which differ by at most tolerance, are equal.
+func nodesEqualTolerance(a, b interface{}, tolerance float64) bool {
+	if objA, ok := convToObject(a); ok {
+		objB, ok := convToObject(b)
+		if !ok || len(objA) != len(objB) {
+			return false
+		}
+		for key, valA := range objA {
+			valB, ok := objB[key]
+			if !ok || !nodesEqualTolerance(valA, valB, tolerance) {
+				return false
+			}
+		}
+		return true
+	}
+
+	if lenA, ok := listLen(a); ok {
+		lenB, ok := listLen(b)
+		if !ok || lenA != lenB {
+			return false
+		}
+		for i := 0; i < lenA; i++ {
+			if !nodesEqualTolerance(listGet(a, i), listGet(b, i), tolerance) {
+				return false
+			}
+		}
+		return true
+	}
+
+	switch kindOf(a) {
+	case KindNumber:
+		if kindOf(b) != KindNumber {
+			return false
+		}
+		return numbersEqual(a, b, tolerance)
+	case KindNull:
+		return b == nil
+	}
+
+	return a == b
+}
+
+// numbersEqual compares two numbers exactly, when both are integers, and as float64 otherwise.
+// Numbers, which differ by at most tolerance, are equal, too.
+func numbersEqual(a, b interface{}, tolerance float64) bool {
+	intA, okA := convToInt64(normalize(a))
+	intB, okB := convToInt64(normalize(b))
+	if okA && okB && (intA == intB || tolerance == 0) {
+		return intA == intB
+	}
+
+	floatA, _ := convToFloat64(normalize(a))
+	floatB, _ := convToFloat64(normalize(b))
+	return floatA == floatB || math.Abs(floatA-floatB) <= tolerance
+}
+
+// cloneNode returns a deep copy of a json value. Objects and lists keep their Go type.
+func cloneNode(data interface{}) interface{} {
+	switch d := data.(type) {
+	case map[string]interface{}:
+		if d == nil {
+			return d
+		}
+		result := make(map[string]interface{}, len(d))
+		for key, val := range d {
+			result[key] = cloneNode(val)
+		}
+		return result
+	case JsonObject:
+		if d == nil {
+			return d
+		}
+		result := make(JsonObject, len(d))
+		for key, val := range d {
+			result[key] = cloneNode(val)
+		}
+		return result
+	case *OrderedObject:
+		if d == nil {
+			return d
+		}
+		return d.Clone()
+	case []interface{}:
+		if d == nil {
+			return d
+		}
+		result := make([]interface{}, len(d))
+		for i, val := range d {
+			result[i] = cloneNode(val)
+		}
+		return result
+	case JsonListRaw:
+		if d == nil {
+			return d
+		}
+		result := make(JsonListRaw, len(d))
+		for i, val := range d {
+			result[i] = cloneNode(val)
+		}
+		return result
+	case JsonList:
+		if d == nil {
+			return d
+		}
+		result := make(JsonList, len(d))
+		for i, item := range d {
+			result[i] = JsonListItem{data: cloneNode(item.data)}
+		}
+		return result
+	}
+
+	return data
+}
+
+// plainNode converts a json tree into the types encoding/json uses, i.e. map[string]interface{} and []interface{}.
+// The result doesn't share any containers with data.
+func plainNode(data interface{}) interface{} {
+	if obj, ok := convToObject(data); ok {
+		result := make(map[string]interface{}, len(obj))
+		for key, val := range obj {
+			result[key] = plainNode(val)
+		}
+		return result
+	}
+
+	if n, ok := listLen(data); ok {
+		result := make([]interface{}, n)
+		for i := range result {
+			result[i] = plainNode(listGet(data, i))
+		}
+		return result
+	}
+
+	return data
+}
+
+// isNilList reports whether a list is nil, which is encoded as null.
+func isNilList(node interface{}) bool {
+	switch l := node.(type) {
+	case []interface{}:
+		return l == nil
+	case JsonListRaw:
+		return l == nil
+	case JsonList:
+		return l == nil
+	}
+
+	return false
+}
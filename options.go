@@ -0,0 +1,111 @@
+package dynjson
+
+// ParseOption changes the behaviour of the parse functions like ParseObject, ParseList, Parse and ParseReader.
+type ParseOption func(*parseConfig)
+
+type parseConfig struct {
+	useNumber        bool
+	maxBytes         int64
+	maxDepth         int
+	maxStringLength  int
+	maxKeys          int
+	disallowTrailing bool
+	preserveOrder    bool
+	duplicateKeys    DuplicateKeyPolicy
+}
+
+func newParseConfig(opts []ParseOption) parseConfig {
+	config := parseConfig{}
+	for _, opt := range opts {
+		opt(&config)
+	}
+	return config
+}
+
+// needsTokenizer returns true, when the limits can't be checked by encoding/json itself, when the order of the keys
+// has to be preserved or when duplicate keys are not handled like encoding/json does.
+func (c parseConfig) needsTokenizer() bool {
+	return c.maxDepth > 0 || c.maxStringLength > 0 || c.maxKeys > 0 || c.preserveOrder ||
+		c.duplicateKeys != DuplicateKeysKeepLast
+}
+
+// UseNumber keeps numbers as json.Number instead of converting them to float64.
+// This way integers above 2^53 don't lose precision and Int64Ok / Uint64Ok return their exact value.
+func UseNumber() ParseOption {
+	return func(config *parseConfig) {
+		config.useNumber = true
+	}
+}
+
+// MaxBytes limits the size of the input. Reading more than n bytes fails with a *LimitError.
+func MaxBytes(n int64) ParseOption {
+	return func(config *parseConfig) {
+		config.maxBytes = n
+	}
+}
+
+// MaxDepth limits the nesting of objects and lists. The top-level object or list has a depth of 1.
+func MaxDepth(n int) ParseOption {
+	return func(config *parseConfig) {
+		config.maxDepth = n
+	}
+}
+
+// MaxStringLength limits the length of strings and object keys in bytes, measured after unescaping.
+// Too long strings are rejected while they are read, so they are never buffered completely.
+func MaxStringLength(n int) ParseOption {
+	return func(config *parseConfig) {
+		config.maxStringLength = n
+	}
+}
+
+// MaxKeys limits the number of keys of each object.
+func MaxKeys(n int) ParseOption {
+	return func(config *parseConfig) {
+		config.maxKeys = n
+	}
+}
+
+// DisallowTrailingData makes ParseReader and friends fail with ErrTrailingData, when anything but whitespace follows
+// the json value. By default, they stop reading after the value. Parsing strings and byte slices always
+// disallows trailing data.
+func DisallowTrailingData() ParseOption {
+	return func(config *parseConfig) {
+		config.disallowTrailing = true
+	}
+}
+
+// PreserveOrder parses objects into *OrderedObject, which keeps the keys in the order of the input.
+// ParseObject and ParseObjectReader return a JsonObject, so only the order of nested objects is kept.
+// Use ParseOrderedObject or Parse to keep the order of the root object, too.
+func PreserveOrder() ParseOption {
+	return func(config *parseConfig) {
+		config.preserveOrder = true
+	}
+}
+
+// DuplicateKeyPolicy decides what happens, when an object contains the same key more than once.
+type DuplicateKeyPolicy int
+
+const (
+	// DuplicateKeysKeepLast keeps the value of the last occurrence of the key. This is the default, which
+	// matches encoding/json.
+	DuplicateKeysKeepLast DuplicateKeyPolicy = iota
+	// DuplicateKeysError fails with an *Error of the kind ErrorKindDuplicateKey, which contains the path and the
+	// position of the duplicate.
+	DuplicateKeysError
+	// DuplicateKeysKeepFirst keeps the value of the first occurrence of the key.
+	DuplicateKeysKeepFirst
+	// DuplicateKeysCollect collects the values of all occurrences of the key into a list, in the order of the input.
+	// Keys, which occur only once, keep their value as is.
+	DuplicateKeysCollect
+)
+
+// DuplicateKeys sets the policy for objects containing the same key more than once.
+// Different parsers handle duplicates differently, so rejecting them with DuplicateKeysError is the safe choice
+// for input, which is also read by other programs.
+func DuplicateKeys(policy DuplicateKeyPolicy) ParseOption {
+	return func(config *parseConfig) {
+		config.duplicateKeys = policy
+	}
+}
@@ -0,0 +1,713 @@
+package dynjson
+
+import (
+	"fmt"
+	"io"
+	"sort"
+)
+
+// OrderedObject is a json object, which keeps its keys in order: the order of the input when it was parsed, otherwise
+// the order in which the keys were added. It has the same getters and setters as JsonObject.
+//
+// Nested objects of an OrderedObject, which was parsed by ParseOrderedObject, are ordered, too.
+// The getters of JsonObject and Value return ordered objects as JsonObject, which shares the members with the ordered
+// object. Keys added through such a JsonObject are placed behind the ordered keys, sorted alphabetically.
+// SetPointer, MergePatch and DeepMerge keep the order and add new keys at the end.
+type OrderedObject struct {
+	keys   []string
+	values map[string]interface{}
+}
+
+// NewOrderedObject creates an empty ordered object.
+func NewOrderedObject() *OrderedObject {
+	return &OrderedObject{values: map[string]interface{}{}}
+}
+
+// ParseOrderedObject parses a string containing a json object and keeps the order of the keys of all objects.
+// Null results in a nil object.
+func ParseOrderedObject(jsonString string, opts ...ParseOption) (*OrderedObject, error) {
+	raw, err := parseBytes([]byte(jsonString), newParseConfig(append(opts, PreserveOrder())))
+	if err != nil {
+		return nil, err
+	}
+
+	if raw == nil {
+		return nil, nil
+	}
+	obj, ok := convToOrderedObject(raw)
+	if !ok {
+		return nil, typeError(KindObject, raw)
+	}
+	return obj, nil
+}
+
+// convToOrderedObject returns ordered objects as they are and wraps other objects. The keys of wrapped objects
+// are sorted.
+func convToOrderedObject(data interface{}) (*OrderedObject, bool) {
+	if obj, ok := data.(*OrderedObject); ok && obj != nil {
+		return obj, true
+	}
+	if obj, ok := convToObject(data); ok {
+		return &OrderedObject{values: obj}, true
+	}
+	return nil, false
+}
+
+// OrderedObjectOk returns the value as ordered object. Objects which are not ordered are wrapped, their keys are
+// sorted.
+func (v Value) OrderedObjectOk() (*OrderedObject, bool) {
+	return convToOrderedObject(v.data)
+}
+
+// MarshalJSON implements json.Marshaler. The keys are written in order.
+func (o *OrderedObject) MarshalJSON() ([]byte, error) {
+	if o == nil {
+		return []byte("null"), nil
+	}
+	return o.Marshal()
+}
+
+// UnmarshalJSON implements json.Unmarshaler. The order of the keys of all objects is kept.
+func (o *OrderedObject) UnmarshalJSON(data []byte) error {
+	raw, err := parseBytes(data, parseConfig{preserveOrder: true})
+	if err != nil {
+		return err
+	}
+
+	if raw == nil {
+		*o = OrderedObject{values: map[string]interface{}{}}
+		return nil
+	}
+	obj, ok := raw.(*OrderedObject)
+	if !ok {
+		return typeError(KindObject, raw)
+	}
+	*o = *obj
+	return nil
+}
+
+// ToString returns the JSON data as string.
+// Returns an empty string, when an error occurred. Use Marshal to get the error or to change the output.
+func (o *OrderedObject) ToString() string {
+	data, _ := o.Marshal()
+	return string(data)
+}
+
+// Marshal returns the json encoding of the object with the keys in order. See JsonObject.Marshal.
+func (o *OrderedObject) Marshal(opts ...MarshalOption) ([]byte, error) {
+	return marshalNode(o, newMarshalConfig(opts))
+}
+
+// WriteJSON writes the json encoding of the object to w. See JsonObject.Marshal.
+func (o *OrderedObject) WriteJSON(w io.Writer, opts ...MarshalOption) (int64, error) {
+	return writeNode(w, o, newMarshalConfig(opts))
+}
+
+// WriteTo implements io.WriterTo. It writes the compact json encoding of the object to w.
+func (o *OrderedObject) WriteTo(w io.Writer) (int64, error) {
+	return o.WriteJSON(w)
+}
+
+// Keys returns the keys in order.
+func (o *OrderedObject) Keys() []string {
+	o.sync()
+	return append([]string(nil), o.keys...)
+}
+
+// sync updates keys, when members were added or removed through a JsonObject sharing them. Added keys are placed
+// behind the others, sorted alphabetically. The methods of OrderedObject keep keys up to date themselves.
+func (o *OrderedObject) sync() {
+	if len(o.keys) == len(o.values) {
+		valid := true
+		for _, key := range o.keys {
+			if _, ok := o.values[key]; !ok {
+				valid = false
+				break
+			}
+		}
+		if valid {
+			return
+		}
+	}
+
+	keys := make([]string, 0, len(o.values))
+	seen := make(map[string]bool, len(o.values))
+	for _, key := range o.keys {
+		if _, ok := o.values[key]; ok && !seen[key] {
+			seen[key] = true
+			keys = append(keys, key)
+		}
+	}
+
+	var added []string
+	for key := range o.values {
+		if !seen[key] {
+			added = append(added, key)
+		}
+	}
+	sort.Strings(added)
+	o.keys = append(keys, added...)
+}
+
+// Len returns the number of members.
+func (o *OrderedObject) Len() int {
+	return len(o.values)
+}
+
+// Clone returns a deep copy of the object.
+func (o *OrderedObject) Clone() *OrderedObject {
+	values := make(map[string]interface{}, len(o.values))
+	for key, val := range o.values {
+		values[key] = cloneNode(val)
+	}
+	return &OrderedObject{keys: o.Keys(), values: values}
+}
+
+// Has checks if the object contains a specific field.
+func (o *OrderedObject) Has(field string) bool {
+	_, ok := o.values[field]
+	return ok
+}
+
+// IsNull checks if the object contains a specific field, which is explicitly set to null.
+func (o *OrderedObject) IsNull(field string) bool {
+	return o.Value(field).IsNull()
+}
+
+// Value returns the field as Value. When the field doesn't exist, the value is missing.
+func (o *OrderedObject) Value(field string) Value {
+	data, ok := o.values[field]
+	return Value{data: data, exists: ok}
+}
+
+// Set sets a field. New fields are added behind the existing ones, existing fields keep their position.
+func (o *OrderedObject) Set(field string, value interface{}) {
+	if o.values == nil {
+		o.values = map[string]interface{}{}
+	}
+	if _, ok := o.values[field]; !ok {
+		o.keys = append(o.keys, field)
+	}
+	o.values[field] = normalize(value)
+}
+
+// Delete removes a field and reports whether it existed.
+func (o *OrderedObject) Delete(field string) bool {
+	if _, ok := o.values[field]; !ok {
+		return false
+	}
+
+	delete(o.values, field)
+	if i := o.indexOf(field); i >= 0 {
+		o.keys = append(o.keys[:i], o.keys[i+1:]...)
+	}
+	return true
+}
+
+// MoveKey moves an existing field to the position index, e.g. 0 makes it the first field.
+func (o *OrderedObject) MoveKey(field string, index int) error {
+	if !o.Has(field) {
+		return withPath(missingError(), FormatPointer(field))
+	}
+	if index < 0 || index >= len(o.values) {
+		return indexError(index, len(o.values))
+	}
+
+	o.sync()
+	i := o.indexOf(field)
+	if i < index {
+		copy(o.keys[i:index], o.keys[i+1:index+1])
+	} else {
+		copy(o.keys[index+1:i+1], o.keys[index:i])
+	}
+	o.keys[index] = field
+	return nil
+}
+
+// InsertAfter sets a field and moves it directly behind the field after.
+func (o *OrderedObject) InsertAfter(after, field string, value interface{}) error {
+	if !o.Has(after) {
+		return withPath(missingError(), FormatPointer(after))
+	}
+	if after == field {
+		return fmt.Errorf("dynjson: can't insert %q after itself", field)
+	}
+
+	o.Set(field, value)
+	o.sync()
+	index := o.indexOf(after)
+	if o.indexOf(field) > index {
+		index++
+	}
+	return o.MoveKey(field, index)
+}
+
+// indexOf returns the position of the field in keys or -1.
+func (o *OrderedObject) indexOf(field string) int {
+	for i, key := range o.keys {
+		if key == field {
+			return i
+		}
+	}
+	return -1
+}
+
+// ObjectOk returns a nested object as ordered object. Objects which are not ordered are wrapped, their keys are sorted.
+func (o *OrderedObject) ObjectOk(field string) (*OrderedObject, bool) {
+	return o.Value(field).OrderedObjectOk()
+}
+
+func (o *OrderedObject) Object(field string) *OrderedObject {
+	val, _ := o.ObjectOk(field)
+	return val
+}
+
+func (o *OrderedObject) ObjectErr(field string) (*OrderedObject, error) {
+	val, ok := o.ObjectOk(field)
+	if !ok {
+		return nil, withPath(o.Value(field).kindError("object", KindObject), FormatPointer(field))
+	}
+	return val, nil
+}
+
+// Pointer returns the value the JSON pointer refers to. See JsonObject.Pointer.
+func (o *OrderedObject) Pointer(ptr string) (Value, error) {
+	return pointerGet(o, ptr)
+}
+
+// SetPointer sets the value the JSON pointer refers to. See JsonObject.SetPointer.
+func (o *OrderedObject) SetPointer(ptr string, value interface{}) error {
+	segments, err := ParsePointer(ptr)
+	if err != nil {
+		return err
+	}
+
+	_, err = setPath(o, ptr, segments, normalize(value))
+	return err
+}
+
+// DeletePointer removes the value the JSON pointer refers to. See JsonObject.DeletePointer.
+func (o *OrderedObject) DeletePointer(ptr string) error {
+	_, err := pointerUpdate(o, ptr, deleteChild)
+	return err
+}
+
+// Decode stores the object in the struct or map v points to. See JsonObject.Decode.
+func (o *OrderedObject) Decode(v interface{}) error {
+	return decodeValue(o, v)
+}
+
+// Chain returns the nested object, which is reached by following the fields, or nil. See JsonObject.Chain.
+func (o *OrderedObject) Chain(field ...string) *OrderedObject {
+	var result = o
+
+	for _, f := range field {
+		result = result.Object(f)
+		if result == nil {
+			return nil
+		}
+	}
+
+	return result
+}
+
+// SetPath sets the value at the given path. See JsonObject.SetPath. New keys are added at the end.
+func (o *OrderedObject) SetPath(path []string, value interface{}) error {
+	_, err := setPath(o, FormatPointer(path...), path, normalize(value))
+	return err
+}
+
+// GetPath returns the value at the given path. See JsonObject.GetPath.
+func (o *OrderedObject) GetPath(path []string) Value {
+	var node interface{} = o
+	for _, segment := range path {
+		child, reason := getChild(node, segment)
+		if reason != "" {
+			return Value{}
+		}
+		node = child
+	}
+
+	return Value{data: node, exists: true}
+}
+
+// ObjectPathOk works like ObjectOk, but takes a path instead of a field. The same applies to the other typed
+// getters ending with "Path", "PathOk" or "PathDefault".
+func (o *OrderedObject) ObjectPathOk(path []string) (*OrderedObject, bool) {
+	return o.GetPath(path).OrderedObjectOk()
+}
+
+func (o *OrderedObject) ObjectPath(path []string) *OrderedObject {
+	val, _ := o.ObjectPathOk(path)
+	return val
+}
+
+func (o *OrderedObject) ListPathOk(path []string) (JsonList, bool) {
+	return o.GetPath(path).ListOk()
+}
+
+func (o *OrderedObject) ListPath(path []string) JsonList {
+	val, _ := o.ListPathOk(path)
+	return val
+}
+
+func (o *OrderedObject) StringPathOk(path []string) (string, bool) {
+	return o.GetPath(path).StringOk()
+}
+
+func (o *OrderedObject) StringPathDefault(path []string, def string) string {
+	val, ok := o.StringPathOk(path)
+	if ok {
+		return val
+	}
+	return def
+}
+
+func (o *OrderedObject) StringPath(path []string) string {
+	val, _ := o.StringPathOk(path)
+	return val
+}
+
+func (o *OrderedObject) Float64PathOk(path []string) (float64, bool) {
+	return o.GetPath(path).Float64Ok()
+}
+
+func (o *OrderedObject) Float64PathDefault(path []string, def float64) float64 {
+	val, ok := o.Float64PathOk(path)
+	if ok {
+		return val
+	}
+	return def
+}
+
+func (o *OrderedObject) Float64Path(path []string) float64 {
+	val, _ := o.Float64PathOk(path)
+	return val
+}
+
+func (o *OrderedObject) Float32PathOk(path []string) (float32, bool) {
+	return o.GetPath(path).Float32Ok()
+}
+
+func (o *OrderedObject) Float32PathDefault(path []string, def float32) float32 {
+	val, ok := o.Float32PathOk(path)
+	if ok {
+		return val
+	}
+	return def
+}
+
+func (o *OrderedObject) Float32Path(path []string) float32 {
+	val, _ := o.Float32PathOk(path)
+	return val
+}
+
+func (o *OrderedObject) IntPathOk(path []string) (int, bool) {
+	return o.GetPath(path).IntOk()
+}
+
+func (o *OrderedObject) IntPathDefault(path []string, def int) int {
+	val, ok := o.IntPathOk(path)
+	if ok {
+		return val
+	}
+	return def
+}
+
+func (o *OrderedObject) IntPath(path []string) int {
+	val, _ := o.IntPathOk(path)
+	return val
+}
+
+func (o *OrderedObject) Int64PathOk(path []string) (int64, bool) {
+	return o.GetPath(path).Int64Ok()
+}
+
+func (o *OrderedObject) Int64PathDefault(path []string, def int64) int64 {
+	val, ok := o.Int64PathOk(path)
+	if ok {
+		return val
+	}
+	return def
+}
+
+func (o *OrderedObject) Int64Path(path []string) int64 {
+	val, _ := o.Int64PathOk(path)
+	return val
+}
+
+func (o *OrderedObject) Int32PathOk(path []string) (int32, bool) {
+	return o.GetPath(path).Int32Ok()
+}
+
+func (o *OrderedObject) Int32PathDefault(path []string, def int32) int32 {
+	val, ok := o.Int32PathOk(path)
+	if ok {
+		return val
+	}
+	return def
+}
+
+func (o *OrderedObject) Int32Path(path []string) int32 {
+	val, _ := o.Int32PathOk(path)
+	return val
+}
+
+func (o *OrderedObject) Uint64PathOk(path []string) (uint64, bool) {
+	return o.GetPath(path).Uint64Ok()
+}
+
+func (o *OrderedObject) Uint64PathDefault(path []string, def uint64) uint64 {
+	val, ok := o.Uint64PathOk(path)
+	if ok {
+		return val
+	}
+	return def
+}
+
+func (o *OrderedObject) Uint64Path(path []string) uint64 {
+	val, _ := o.Uint64PathOk(path)
+	return val
+}
+
+func (o *OrderedObject) BoolPathOk(path []string) (bool, bool) {
+	return o.GetPath(path).BoolOk()
+}
+
+func (o *OrderedObject) BoolPathDefault(path []string, def bool) bool {
+	val, ok := o.BoolPathOk(path)
+	if ok {
+		return val
+	}
+	return def
+}
+
+func (o *OrderedObject) BoolPath(path []string) bool {
+	val, _ := o.BoolPathOk(path)
+	return val
+}
+
+// Query compiles the JSONPath expression and returns all matching values of the object in the order of the keys.
+func (o *OrderedObject) Query(expr string) ([]PathMatch, error) {
+	path, err := CompileJsonPath(expr)
+	if err != nil {
+		return nil, err
+	}
+	return path.Query(o), nil
+}
+
+// ApplyPatch applies a JSON Patch (RFC 6902) to the object. See JsonObject.ApplyPatch.
+// The keys keep their order, added keys are placed at the end.
+func (o *OrderedObject) ApplyPatch(patch JsonList) error {
+	result, err := ApplyPatch(o, patch)
+	if err != nil {
+		return err
+	}
+
+	obj, ok := result.OrderedObjectOk()
+	if !ok {
+		return fmt.Errorf("dynjson: patch replaced the object by %s", result.Kind())
+	}
+
+	if o.values == nil {
+		o.values = map[string]interface{}{}
+	}
+	for key := range o.values {
+		delete(o.values, key)
+	}
+	for key, val := range obj.values {
+		o.values[key] = val
+	}
+	o.keys = obj.Keys()
+
+	return nil
+}
+
+// Lenient returns the object in lenient mode, see LenientObject. The lenient object shares the members with o.
+func (o *OrderedObject) Lenient(opts ...CoercionOption) LenientObject {
+	return JsonObject(o.values).Lenient(opts...)
+}
+
+func (o *OrderedObject) ListOk(field string) (JsonList, bool) {
+	return o.Value(field).ListOk()
+}
+
+func (o *OrderedObject) List(field string) JsonList {
+	return o.Value(field).List()
+}
+
+func (o *OrderedObject) ListErr(field string) (JsonList, error) {
+	val, err := o.Value(field).ListErr()
+	return val, withPath(err, FormatPointer(field))
+}
+
+func (o *OrderedObject) StringOk(field string) (string, bool) {
+	return o.Value(field).StringOk()
+}
+
+func (o *OrderedObject) StringDefault(field string, def string) string {
+	return o.Value(field).StringDefault(def)
+}
+
+func (o *OrderedObject) String(field string) string {
+	return o.Value(field).String()
+}
+
+func (o *OrderedObject) StringErr(field string) (string, error) {
+	val, err := o.Value(field).StringErr()
+	return val, withPath(err, FormatPointer(field))
+}
+
+func (o *OrderedObject) Float64Ok(field string) (float64, bool) {
+	return o.Value(field).Float64Ok()
+}
+
+func (o *OrderedObject) Float64Default(field string, def float64) float64 {
+	return o.Value(field).Float64Default(def)
+}
+
+func (o *OrderedObject) Float64(field string) float64 {
+	return o.Value(field).Float64()
+}
+
+func (o *OrderedObject) Float64Err(field string) (float64, error) {
+	val, err := o.Value(field).Float64Err()
+	return val, withPath(err, FormatPointer(field))
+}
+
+func (o *OrderedObject) Float32Ok(field string) (float32, bool) {
+	return o.Value(field).Float32Ok()
+}
+
+func (o *OrderedObject) Float32Default(field string, def float32) float32 {
+	return o.Value(field).Float32Default(def)
+}
+
+func (o *OrderedObject) Float32(field string) float32 {
+	return o.Value(field).Float32()
+}
+
+func (o *OrderedObject) Float32Err(field string) (float32, error) {
+	val, err := o.Value(field).Float32Err()
+	return val, withPath(err, FormatPointer(field))
+}
+
+func (o *OrderedObject) IntOk(field string) (int, bool) {
+	return o.Value(field).IntOk()
+}
+
+func (o *OrderedObject) IntDefault(field string, def int) int {
+	return o.Value(field).IntDefault(def)
+}
+
+func (o *OrderedObject) Int(field string) int {
+	return o.Value(field).Int()
+}
+
+func (o *OrderedObject) IntErr(field string) (int, error) {
+	val, err := o.Value(field).IntErr()
+	return val, withPath(err, FormatPointer(field))
+}
+
+func (o *OrderedObject) Int64Ok(field string) (int64, bool) {
+	return o.Value(field).Int64Ok()
+}
+
+func (o *OrderedObject) Int64Default(field string, def int64) int64 {
+	return o.Value(field).Int64Default(def)
+}
+
+func (o *OrderedObject) Int64(field string) int64 {
+	return o.Value(field).Int64()
+}
+
+func (o *OrderedObject) Int64Err(field string) (int64, error) {
+	val, err := o.Value(field).Int64Err()
+	return val, withPath(err, FormatPointer(field))
+}
+
+func (o *OrderedObject) Int32Ok(field string) (int32, bool) {
+	return o.Value(field).Int32Ok()
+}
+
+func (o *OrderedObject) Int32Default(field string, def int32) int32 {
+	return o.Value(field).Int32Default(def)
+}
+
+func (o *OrderedObject) Int32(field string) int32 {
+	return o.Value(field).Int32()
+}
+
+func (o *OrderedObject) Int32Err(field string) (int32, error) {
+	val, err := o.Value(field).Int32Err()
+	return val, withPath(err, FormatPointer(field))
+}
+
+func (o *OrderedObject) Uint64Ok(field string) (uint64, bool) {
+	return o.Value(field).Uint64Ok()
+}
+
+func (o *OrderedObject) Uint64Default(field string, def uint64) uint64 {
+	return o.Value(field).Uint64Default(def)
+}
+
+func (o *OrderedObject) Uint64(field string) uint64 {
+	return o.Value(field).Uint64()
+}
+
+func (o *OrderedObject) Uint64Err(field string) (uint64, error) {
+	val, err := o.Value(field).Uint64Err()
+	return val, withPath(err, FormatPointer(field))
+}
+
+func (o *OrderedObject) BoolOk(field string) (bool, bool) {
+	return o.Value(field).BoolOk()
+}
+
+func (o *OrderedObject) BoolDefault(field string, def bool) bool {
+	return o.Value(field).BoolDefault(def)
+}
+
+func (o *OrderedObject) Bool(field string) bool {
+	return o.Value(field).Bool()
+}
+
+func (o *OrderedObject) BoolErr(field string) (bool, error) {
+	val, err := o.Value(field).BoolErr()
+	return val, withPath(err, FormatPointer(field))
+}
+
+func (o *OrderedObject) SetObject(field string, value JsonObject) {
+	o.Set(field, value)
+}
+
+// SetOrderedObject sets a nested ordered object.
+func (o *OrderedObject) SetOrderedObject(field string, value *OrderedObject) {
+	o.Set(field, value)
+}
+
+func (o *OrderedObject) SetList(field string, value JsonList) {
+	o.Set(field, value)
+}
+
+// SetNumber writes an integer or float into the object. See JsonObject.SetNumber.
+func (o *OrderedObject) SetNumber(field string, value float64) {
+	o.Set(field, value)
+}
+
+// SetInt64 writes an integer into the object without losing precision. See JsonObject.SetInt64.
+func (o *OrderedObject) SetInt64(field string, value int64) {
+	o.Set(field, value)
+}
+
+func (o *OrderedObject) SetString(field, value string) {
+	o.Set(field, value)
+}
+
+func (o *OrderedObject) SetBool(field string, value bool) {
+	o.Set(field, value)
+}
+
+func (o *OrderedObject) SetNull(field string) {
+	o.Set(field, nil)
+}
@@ -0,0 +1,221 @@
+package dynjson_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/go-schild/dynjson"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseOrderedObject(t *testing.T) {
+	o, err := dynjson.ParseOrderedObject(`{"z": 1, "a": {"y": true, "b": null}, "m": [{"d": 1, "c": 2}]}`)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"z", "a", "m"}, o.Keys())
+	assert.Equal(t, []string{"y", "b"}, o.Object("a").Keys())
+	assert.Equal(t, 1, o.Int("z"))
+	assert.True(t, o.Object("a").IsNull("b"))
+	assert.Equal(t, `{"z":1,"a":{"y":true,"b":null},"m":[{"d":1,"c":2}]}`, o.ToString())
+
+	_, err = dynjson.ParseOrderedObject(`[1]`)
+	assert.True(t, errors.Is(err, dynjson.ErrTypeMismatch))
+
+	o, err = dynjson.ParseOrderedObject(`null`)
+	assert.Nil(t, err)
+	assert.Nil(t, o)
+}
+
+func TestOrderedObject_Set(t *testing.T) {
+	o := dynjson.NewOrderedObject()
+	o.SetString("b", "x")
+	o.SetNumber("a", 1)
+	o.SetBool("c", true)
+	o.SetString("b", "y")
+	assert.Equal(t, `{"b":"y","a":1,"c":true}`, o.ToString())
+
+	assert.True(t, o.Delete("a"))
+	assert.False(t, o.Delete("a"))
+	o.SetNull("a")
+	assert.Equal(t, []string{"b", "c", "a"}, o.Keys())
+	assert.Equal(t, 3, o.Len())
+}
+
+func TestOrderedObject_MoveKey(t *testing.T) {
+	o, _ := dynjson.ParseOrderedObject(`{"a": 1, "b": 2, "c": 3}`)
+
+	assert.Nil(t, o.MoveKey("c", 0))
+	assert.Equal(t, []string{"c", "a", "b"}, o.Keys())
+	assert.Nil(t, o.MoveKey("c", 2))
+	assert.Equal(t, []string{"a", "b", "c"}, o.Keys())
+
+	assert.True(t, errors.Is(o.MoveKey("x", 0), dynjson.ErrMissing))
+	assert.True(t, errors.Is(o.MoveKey("a", 3), dynjson.ErrOutOfRange))
+}
+
+func TestOrderedObject_InsertAfter(t *testing.T) {
+	o, _ := dynjson.ParseOrderedObject(`{"a": 1, "b": 2, "c": 3}`)
+
+	assert.Nil(t, o.InsertAfter("a", "x", "new"))
+	assert.Equal(t, `{"a":1,"x":"new","b":2,"c":3}`, o.ToString())
+	assert.Nil(t, o.InsertAfter("x", "c", 4))
+	assert.Equal(t, `{"a":1,"x":"new","c":4,"b":2}`, o.ToString())
+	assert.Nil(t, o.InsertAfter("b", "a", 5))
+	assert.Equal(t, `{"x":"new","c":4,"b":2,"a":5}`, o.ToString())
+
+	assert.True(t, errors.Is(o.InsertAfter("missing", "y", 1), dynjson.ErrMissing))
+	assert.NotNil(t, o.InsertAfter("a", "a", 1))
+}
+
+func TestOrderedObject_SharedMembers(t *testing.T) {
+	o, _ := dynjson.ParseOrderedObject(`{"b": 1, "a": 2}`)
+
+	// Keys added through the map view are placed behind the ordered keys.
+	v := dynjson.NewValue(o)
+	v.Object()["d"] = 3.0
+	v.Object()["c"] = 4.0
+	assert.Equal(t, []string{"b", "a", "c", "d"}, o.Keys())
+	assert.Equal(t, `{"b":1,"a":2,"c":4,"d":3}`, o.ToString())
+
+	delete(v.Object(), "a")
+	assert.Equal(t, []string{"b", "c", "d"}, o.Keys())
+	assert.Nil(t, o.MoveKey("b", 2))
+	assert.Equal(t, []string{"c", "d", "b"}, o.Keys())
+	assert.True(t, o.Delete("d"))
+	assert.Equal(t, `{"c":4,"b":1}`, o.ToString())
+}
+
+func TestOrderedObject_Marshal(t *testing.T) {
+	o, _ := dynjson.ParseOrderedObject(`{"b": 1, "a": {"d": 2, "c": 3}}`)
+
+	data, err := o.Marshal(dynjson.SortKeys())
+	assert.Nil(t, err)
+	assert.Equal(t, `{"a":{"c":3,"d":2},"b":1}`, string(data))
+
+	data, err = o.Marshal(dynjson.Indent("  "))
+	assert.Nil(t, err)
+	assert.Equal(t, "{\n  \"b\": 1,\n  \"a\": {\n    \"d\": 2,\n    \"c\": 3\n  }\n}", string(data))
+
+	var target struct {
+		O *dynjson.OrderedObject `json:"o"`
+	}
+	assert.Nil(t, json.Unmarshal([]byte(`{"o": {"y": 1, "x": 2}}`), &target))
+	assert.Equal(t, []string{"y", "x"}, target.O.Keys())
+}
+
+func TestOrderedObject_Pointer(t *testing.T) {
+	o, _ := dynjson.ParseOrderedObject(`{"b": {"z": 1, "y": 2}, "a": [1]}`)
+
+	assert.Nil(t, o.SetPointer("/b/x", "new"))
+	assert.Nil(t, o.SetPointer("/b/z", 3))
+	assert.Nil(t, o.DeletePointer("/b/y"))
+	assert.Equal(t, `{"b":{"z":3,"x":"new"},"a":[1]}`, o.ToString())
+
+	assert.Nil(t, o.SetPointer("/z", 1))
+	assert.Nil(t, o.SetPointer("/m", 2))
+	assert.Nil(t, o.SetPointer("/n/y/1", true))
+	assert.Nil(t, o.SetPointer("/n/c", 3))
+	assert.Nil(t, o.SetPointer("/a/2/q", 4))
+	assert.Nil(t, o.SetPointer("/a/2/p", 5))
+	assert.Equal(t, []string{"b", "a", "z", "m", "n"}, o.Keys())
+	assert.Equal(t, `{"b":{"z":3,"x":"new"},"a":[1,null,{"q":4,"p":5}],"z":1,"m":2,"n":{"y":[null,true],"c":3}}`,
+		o.ToString())
+
+	v, err := o.Pointer("/b/x")
+	assert.Nil(t, err)
+	assert.Equal(t, "new", v.String())
+}
+
+func TestOrderedObject_Clone(t *testing.T) {
+	o, _ := dynjson.ParseOrderedObject(`{"b": {"z": 1, "y": 2}, "a": 1}`)
+
+	c := o.Clone()
+	c.Object("b").SetNumber("x", 3)
+	assert.Equal(t, `{"b":{"z":1,"y":2},"a":1}`, o.ToString())
+	assert.Equal(t, `{"b":{"z":1,"y":2,"x":3},"a":1}`, c.ToString())
+}
+
+func TestParse_PreserveOrder(t *testing.T) {
+	v, err := dynjson.Parse([]byte(`{"b": 1, "a": 2}`), dynjson.PreserveOrder())
+	assert.Nil(t, err)
+
+	o, ok := v.OrderedObjectOk()
+	assert.True(t, ok)
+	assert.Equal(t, []string{"b", "a"}, o.Keys())
+	assert.Equal(t, 2, v.Object().Int("a"))
+
+	j, err := dynjson.ParseObject(`{"x": {"b": 1, "a": 2}}`, dynjson.PreserveOrder())
+	assert.Nil(t, err)
+	assert.Equal(t, `{"b":1,"a":2}`, j.Value("x").ToString())
+}
+
+func TestOrderedObject_Merge(t *testing.T) {
+	o, _ := dynjson.ParseOrderedObject(`{"m": 1, "b": {"y": 2, "x": 3}, "k": 4}`)
+	patch, _ := dynjson.ParseOrderedObject(`{"z": 1, "b": {"w": 5, "y": null}, "k": null, "c": {"q": 1, "p": 2}}`)
+
+	result := dynjson.MergePatch(o, patch)
+	assert.Equal(t, `{"m":1,"b":{"x":3,"w":5},"z":1,"c":{"q":1,"p":2}}`, result.ToString())
+	assert.Equal(t, `{"m":1,"b":{"y":2,"x":3},"k":4}`, o.ToString())
+
+	j, _ := dynjson.ParseObject(`{"o": {"m": 1, "b": 2}}`, dynjson.PreserveOrder())
+	src, _ := dynjson.ParseObject(`{"o": {"z": 3, "a": 4}}`)
+	assert.Nil(t, j.DeepMerge(src))
+	o, ok := j.Value("o").OrderedObjectOk()
+	assert.True(t, ok)
+	assert.Equal(t, []string{"m", "b", "a", "z"}, o.Keys())
+}
+
+func TestOrderedObject_Path(t *testing.T) {
+	o, _ := dynjson.ParseOrderedObject(`{"b": {"z": {"y": 1}, "x": [true]}, "a": "s"}`)
+
+	assert.Equal(t, []string{"y"}, o.Chain("b", "z").Keys())
+	assert.Nil(t, o.Chain("b", "x"))
+	assert.Equal(t, 1, o.IntPath([]string{"b", "z", "y"}))
+	assert.True(t, o.BoolPath([]string{"b", "x", "0"}))
+	assert.Equal(t, "d", o.StringPathDefault([]string{"b", "c"}, "d"))
+	assert.Equal(t, []string{"z", "x"}, o.ObjectPath([]string{"b"}).Keys())
+
+	assert.Nil(t, o.SetPath([]string{"b", "w"}, 2))
+	assert.Equal(t, `{"b":{"z":{"y":1},"x":[true],"w":2},"a":"s"}`, o.ToString())
+}
+
+func TestOrderedObject_Query(t *testing.T) {
+	o, _ := dynjson.ParseOrderedObject(`{"b": 1, "a": 2, "c": 3}`)
+
+	matches, err := o.Query("$.*")
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(matches))
+	assert.Equal(t, "/b", matches[0].Pointer)
+	assert.Equal(t, "/a", matches[1].Pointer)
+	assert.Equal(t, "/c", matches[2].Pointer)
+
+	_, err = o.Query("$[")
+	assert.NotNil(t, err)
+}
+
+func TestOrderedObject_ApplyPatch(t *testing.T) {
+	o, _ := dynjson.ParseOrderedObject(`{"b": 1, "a": {"z": 1, "y": 2}}`)
+	shared := dynjson.NewValue(o).Object()
+
+	patch, err := dynjson.ParseList(`[
+		{"op": "add", "path": "/c", "value": 3},
+		{"op": "remove", "path": "/b"},
+		{"op": "add", "path": "/a/x", "value": 4}
+	]`)
+	assert.Nil(t, err)
+	assert.Nil(t, o.ApplyPatch(patch))
+	assert.Equal(t, `{"a":{"z":1,"y":2,"x":4},"c":3}`, o.ToString())
+	assert.Equal(t, 3, shared.Int("c"))
+
+	patch, err = dynjson.ParseList(`[{"op": "test", "path": "/c", "value": 4}]`)
+	assert.Nil(t, err)
+	assert.NotNil(t, o.ApplyPatch(patch))
+	assert.Equal(t, `{"a":{"z":1,"y":2,"x":4},"c":3}`, o.ToString())
+}
+
+func TestOrderedObject_Lenient(t *testing.T) {
+	o, _ := dynjson.ParseOrderedObject(`{"n": "42", "b": "true"}`)
+
+	assert.Equal(t, 42, o.Lenient().Int("n"))
+	assert.True(t, o.Lenient().Bool("b"))
+}
@@ -0,0 +1,327 @@
+package dynjson
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// PatchError is returned when a JSON Patch (RFC 6902) can't be applied.
+type PatchError struct {
+	// Index is the position of the failed operation inside the patch.
+	Index int
+	// Op is the name of the failed operation, e.g. "add".
+	Op string
+	// Path is the path of the failed operation.
+	Path string
+	// Err describes what went wrong. It is a *PointerError, when the path couldn't be resolved.
+	Err error
+}
+
+func (e *PatchError) Error() string {
+	return fmt.Sprintf("dynjson: patch operation %d (%s %q): %v", e.Index, e.Op, e.Path, e.Err)
+}
+
+func (e *PatchError) Unwrap() error {
+	return e.Err
+}
+
+// ApplyPatch applies a JSON Patch (RFC 6902) to doc and returns the patched document.
+// Doc can be a JsonObject, a JsonList or a Value. It is not modified, so when an operation fails, nothing changes.
+//
+// The patch is a list of operations like {"op": "add", "path": "/a/1", "value": 5}.
+// Supported operations are add, remove, replace, move, copy and test.
+func ApplyPatch(doc interface{}, patch JsonList) (Value, error) {
+	root := cloneNode(normalize(doc))
+
+	for i, item := range patch {
+		op, ok := item.ObjectOk()
+		if !ok {
+			return Value{}, &PatchError{Index: i, Err: errors.New("operation is not an object")}
+		}
+
+		var err error
+		root, err = applyOperation(root, op)
+		if err != nil {
+			return Value{}, &PatchError{Index: i, Op: op.String("op"), Path: op.String("path"), Err: err}
+		}
+	}
+
+	return Value{data: root, exists: true}, nil
+}
+
+// ApplyPatch applies a JSON Patch (RFC 6902) to the json object.
+// Either all operations are applied or, when one of them fails, the object stays untouched.
+func (j JsonObject) ApplyPatch(patch JsonList) error {
+	result, err := ApplyPatch(j, patch)
+	if err != nil {
+		return err
+	}
+
+	obj, ok := result.ObjectOk()
+	if !ok {
+		return fmt.Errorf("dynjson: patch replaced the object by %s", result.Kind())
+	}
+
+	for key := range j {
+		delete(j, key)
+	}
+	for key, val := range obj {
+		j[key] = val
+	}
+
+	return nil
+}
+
+// ApplyPatch applies a JSON Patch (RFC 6902) to the json list.
+// Either all operations are applied or, when one of them fails, the list stays untouched.
+func (j *JsonList) ApplyPatch(patch JsonList) error {
+	result, err := ApplyPatch(*j, patch)
+	if err != nil {
+		return err
+	}
+
+	list, ok := result.ListOk()
+	if !ok {
+		return fmt.Errorf("dynjson: patch replaced the list by %s", result.Kind())
+	}
+
+	*j = list
+	return nil
+}
+
+func applyOperation(root interface{}, op JsonObject) (interface{}, error) {
+	name, ok := op.StringOk("op")
+	if !ok {
+		return nil, errors.New(`missing or invalid member "op"`)
+	}
+	path, ok := op.StringOk("path")
+	if !ok {
+		return nil, errors.New(`missing or invalid member "path"`)
+	}
+
+	value, hasValue := op["value"]
+	from, hasFrom := op.StringOk("from")
+
+	switch name {
+	case "add":
+		if !hasValue {
+			return nil, errors.New(`missing member "value"`)
+		}
+		return patchAdd(root, path, cloneNode(value))
+	case "remove":
+		if path == "" {
+			return nil, errors.New("can't remove the document root")
+		}
+		return pointerUpdate(root, path, deleteChild)
+	case "replace":
+		if !hasValue {
+			return nil, errors.New(`missing member "value"`)
+		}
+		if path == "" {
+			return cloneNode(value), nil
+		}
+		return pointerUpdate(root, path, func(parent interface{}, segment string) (interface{}, string) {
+			return replaceChild(parent, segment, cloneNode(value))
+		})
+	case "move":
+		if !hasFrom {
+			return nil, errors.New(`missing or invalid member "from"`)
+		}
+		if strings.HasPrefix(path, from+"/") {
+			return nil, errors.New("can't move a value into one of its children")
+		}
+		val, err := pointerGet(root, from)
+		if err != nil {
+			return nil, err
+		}
+		if from == path {
+			return root, nil
+		}
+		root, err = pointerUpdate(root, from, deleteChild)
+		if err != nil {
+			return nil, err
+		}
+		return patchAdd(root, path, val.data)
+	case "copy":
+		if !hasFrom {
+			return nil, errors.New(`missing or invalid member "from"`)
+		}
+		val, err := pointerGet(root, from)
+		if err != nil {
+			return nil, err
+		}
+		return patchAdd(root, path, cloneNode(val.data))
+	case "test":
+		if !hasValue {
+			return nil, errors.New(`missing member "value"`)
+		}
+		val, err := pointerGet(root, path)
+		if err != nil {
+			return nil, err
+		}
+		if !nodesEqual(val.data, value) {
+			return nil, errors.New("test failed")
+		}
+		return root, nil
+	}
+
+	return nil, fmt.Errorf("unknown operation %q", name)
+}
+
+func patchAdd(root interface{}, path string, value interface{}) (interface{}, error) {
+	if path == "" {
+		return value, nil
+	}
+
+	return pointerUpdate(root, path, func(parent interface{}, segment string) (interface{}, string) {
+		return insertChild(parent, segment, value)
+	})
+}
+
+// Diff generates a JSON Patch (RFC 6902), which turns a into b.
+// A and b can be a JsonObject, a JsonList or a Value. Lists are compared using the edit distance of their elements,
+// so inserting, removing or replacing a single element results in a single operation. To bound time and memory,
+// lists whose changed parts have more than about a million element pairs are compared index by index.
+func Diff(a, b interface{}) JsonList {
+	patch := NewJsonList(nil)
+	diffNodes(&patch, "", normalize(a), normalize(b))
+	return patch
+}
+
+func appendOperation(patch *JsonList, op, path string, value interface{}) {
+	operation := JsonObject{"op": op, "path": path}
+	if op != "remove" {
+		operation["value"] = cloneNode(value)
+	}
+	*patch = append(*patch, JsonListItem{data: operation})
+}
+
+func diffNodes(patch *JsonList, path string, a, b interface{}) {
+	if objA, ok := convToObject(a); ok {
+		if objB, ok := convToObject(b); ok {
+			diffObjects(patch, path, objA, objB)
+			return
+		}
+	}
+
+	if _, ok := listLen(a); ok {
+		if _, ok := listLen(b); ok {
+			diffLists(patch, path, a, b)
+			return
+		}
+	}
+
+	if !nodesEqual(a, b) {
+		appendOperation(patch, "replace", path, b)
+	}
+}
+
+func diffObjects(patch *JsonList, path string, a, b JsonObject) {
+	for _, key := range objectKeys(a) {
+		if _, ok := b[key]; !ok {
+			appendOperation(patch, "remove", path+"/"+EscapePointerSegment(key), nil)
+		}
+	}
+
+	for _, key := range objectKeys(b) {
+		childPath := path + "/" + EscapePointerSegment(key)
+		if valA, ok := a[key]; ok {
+			diffNodes(patch, childPath, valA, b[key])
+		} else {
+			appendOperation(patch, "add", childPath, b[key])
+		}
+	}
+}
+
+// maxDiffCells limits the size of the cost matrix of diffLists, which needs memory and time proportional to the
+// product of the lengths of the lists. Longer lists are compared index by index.
+const maxDiffCells = 1 << 20
+
+func diffLists(patch *JsonList, path string, a, b interface{}) {
+	lenA, _ := listLen(a)
+	lenB, _ := listLen(b)
+
+	// Equal elements at the beginning and at the end don't need to be part of the comparison.
+	prefix := 0
+	for prefix < lenA && prefix < lenB && nodesEqual(listGet(a, prefix), listGet(b, prefix)) {
+		prefix++
+	}
+	suffix := 0
+	for suffix < lenA-prefix && suffix < lenB-prefix && nodesEqual(listGet(a, lenA-1-suffix), listGet(b, lenB-1-suffix)) {
+		suffix++
+	}
+
+	n := lenA - prefix - suffix
+	m := lenB - prefix - suffix
+
+	if n > 0 && m > maxDiffCells/n {
+		diffListsByIndex(patch, path, a, b, prefix, n, m)
+		return
+	}
+
+	// cost[i][k] is the number of operations needed to turn a[prefix+i:] into b[prefix+k:],
+	// where every element can be kept, removed, added or replaced.
+	cost := make([][]int, n+1)
+	for i := range cost {
+		cost[i] = make([]int, m+1)
+		cost[i][m] = n - i
+	}
+	for k := 0; k <= m; k++ {
+		cost[n][k] = m - k
+	}
+	for i := n - 1; i >= 0; i-- {
+		for k := m - 1; k >= 0; k-- {
+			if nodesEqual(listGet(a, prefix+i), listGet(b, prefix+k)) {
+				cost[i][k] = cost[i+1][k+1]
+			} else {
+				cost[i][k] = 1 + minInt(cost[i+1][k+1], minInt(cost[i+1][k], cost[i][k+1]))
+			}
+		}
+	}
+
+	index := prefix
+	i, k := 0, 0
+	for i < n || k < m {
+		switch {
+		case i < n && k < m && nodesEqual(listGet(a, prefix+i), listGet(b, prefix+k)):
+			index++
+			i++
+			k++
+		case i < n && k < m && cost[i][k] == 1+cost[i+1][k+1]:
+			diffNodes(patch, path+"/"+strconv.Itoa(index), listGet(a, prefix+i), listGet(b, prefix+k))
+			index++
+			i++
+			k++
+		case i < n && cost[i][k] == 1+cost[i+1][k]:
+			appendOperation(patch, "remove", path+"/"+strconv.Itoa(index), nil)
+			i++
+		default:
+			appendOperation(patch, "add", path+"/"+strconv.Itoa(index), listGet(b, prefix+k))
+			index++
+			k++
+		}
+	}
+}
+
+// diffListsByIndex compares the n elements of a and the m elements of b behind prefix index by index. In contrast to
+// the edit distance, it is linear, but inserting a single element results in an operation for every element behind it.
+func diffListsByIndex(patch *JsonList, path string, a, b interface{}, prefix, n, m int) {
+	for i := 0; i < n && i < m; i++ {
+		diffNodes(patch, path+"/"+strconv.Itoa(prefix+i), listGet(a, prefix+i), listGet(b, prefix+i))
+	}
+	for i := m; i < n; i++ {
+		appendOperation(patch, "remove", path+"/"+strconv.Itoa(prefix+m), nil)
+	}
+	for i := n; i < m; i++ {
+		appendOperation(patch, "add", path+"/"+strconv.Itoa(prefix+i), listGet(b, prefix+i))
+	}
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
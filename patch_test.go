@@ -0,0 +1,225 @@
+package dynjson_test
+
+import (
+	"errors"
+	"runtime"
+	"testing"
+
+	"github.com/go-schild/dynjson"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApplyPatch(t *testing.T) {
+	// Examples from RFC 6902, appendix A
+	testData := []struct {
+		doc, patch, result string
+	}{
+		{`{"foo": "bar"}`, `[{"op": "add", "path": "/baz", "value": "qux"}]`, `{"baz": "qux", "foo": "bar"}`},
+		{`{"foo": ["bar", "baz"]}`, `[{"op": "add", "path": "/foo/1", "value": "qux"}]`, `{"foo": ["bar", "qux", "baz"]}`},
+		{`{"baz": "qux", "foo": "bar"}`, `[{"op": "remove", "path": "/baz"}]`, `{"foo": "bar"}`},
+		{`{"foo": ["bar", "qux", "baz"]}`, `[{"op": "remove", "path": "/foo/1"}]`, `{"foo": ["bar", "baz"]}`},
+		{`{"baz": "qux", "foo": "bar"}`, `[{"op": "replace", "path": "/baz", "value": "boo"}]`, `{"baz": "boo", "foo": "bar"}`},
+		{
+			`{"foo": {"bar": "baz", "waldo": "fred"}, "qux": {"corge": "grault"}}`,
+			`[{"op": "move", "from": "/foo/waldo", "path": "/qux/thud"}]`,
+			`{"foo": {"bar": "baz"}, "qux": {"corge": "grault", "thud": "fred"}}`,
+		},
+		{`{"foo": ["all", "grass", "cows", "eat"]}`, `[{"op": "move", "from": "/foo/1", "path": "/foo/3"}]`, `{"foo": ["all", "cows", "eat", "grass"]}`},
+		{
+			`{"baz": "qux", "foo": ["a", 2, "c"]}`,
+			`[{"op": "test", "path": "/baz", "value": "qux"}, {"op": "test", "path": "/foo/1", "value": 2}]`,
+			`{"baz": "qux", "foo": ["a", 2, "c"]}`,
+		},
+		{`{"foo": "bar"}`, `[{"op": "add", "path": "/child", "value": {"grandchild": {}}}]`, `{"foo": "bar", "child": {"grandchild": {}}}`},
+		{`{"foo": "bar"}`, `[{"op": "add", "path": "/baz", "value": "qux", "xyz": 123}]`, `{"foo": "bar", "baz": "qux"}`},
+		{`{"foo": ["bar"]}`, `[{"op": "add", "path": "/foo/-", "value": ["abc", "def"]}]`, `{"foo": ["bar", ["abc", "def"]]}`},
+		{`{"/": 9, "~1": 10}`, `[{"op": "test", "path": "/~01", "value": 10}]`, `{"/": 9, "~1": 10}`},
+		{`{"foo": "bar"}`, `[{"op": "copy", "from": "/foo", "path": "/baz"}]`, `{"foo": "bar", "baz": "bar"}`},
+		{`{"foo": "bar"}`, `[{"op": "replace", "path": "", "value": [1]}]`, `[1]`},
+		{`{"foo": {"a": 1}}`, `[{"op": "test", "path": "/foo", "value": {"a": 1.0}}]`, `{"foo": {"a": 1}}`},
+	}
+
+	for _, data := range testData {
+		doc, err := dynjson.ParseObject(data.doc)
+		assert.Nil(t, err)
+		patch, err := dynjson.ParseList(data.patch)
+		assert.Nil(t, err)
+
+		result, err := dynjson.ApplyPatch(doc, patch)
+		assert.Nil(t, err, data.patch)
+		assert.JSONEq(t, data.result, result.ToString(), data.patch)
+	}
+}
+
+func TestApplyPatch_Errors(t *testing.T) {
+	testData := []struct {
+		doc, patch string
+	}{
+		{`{"baz": "qux"}`, `[{"op": "test", "path": "/baz", "value": "bar"}]`},
+		{`{"foo": "bar"}`, `[{"op": "add", "path": "/baz/bat", "value": "qux"}]`},
+		{`{"foo": "bar"}`, `[{"op": "remove", "path": "/baz"}]`},
+		{`{"foo": "bar"}`, `[{"op": "replace", "path": "/baz", "value": 1}]`},
+		{`{"foo": [1]}`, `[{"op": "add", "path": "/foo/2", "value": 1}]`},
+		{`{"foo": [1]}`, `[{"op": "replace", "path": "/foo/1", "value": 1}]`},
+		{`{"foo": {"a": 1}}`, `[{"op": "move", "from": "/foo", "path": "/foo/b"}]`},
+		{`{"foo": "bar"}`, `[{"op": "copy", "from": "/none", "path": "/baz"}]`},
+		{`{"foo": "bar"}`, `[{"op": "add", "path": "/baz"}]`},
+		{`{"foo": "bar"}`, `[{"op": "unknown", "path": "/baz"}]`},
+		{`{"foo": "bar"}`, `[{"path": "/baz"}]`},
+		{`{"foo": "bar"}`, `[{"op": "move", "path": "/baz"}]`},
+		{`{"foo": "bar"}`, `[5]`},
+	}
+
+	for _, data := range testData {
+		doc, err := dynjson.ParseObject(data.doc)
+		assert.Nil(t, err)
+		patch, err := dynjson.ParseList(data.patch)
+		assert.Nil(t, err)
+
+		_, err = dynjson.ApplyPatch(doc, patch)
+		assert.NotNil(t, err, data.patch)
+
+		var pErr *dynjson.PatchError
+		assert.True(t, errors.As(err, &pErr), data.patch)
+		assert.Equal(t, 0, pErr.Index)
+	}
+}
+
+func TestJsonObject_ApplyPatch(t *testing.T) {
+	j, err := dynjson.ParseObject(`{"a": 1, "b": [1, 2]}`)
+	assert.Nil(t, err)
+
+	patch, err := dynjson.ParseList(`[
+		{"op": "add", "path": "/c", "value": 3},
+		{"op": "remove", "path": "/b/0"},
+		{"op": "test", "path": "/a", "value": 2}
+	]`)
+	assert.Nil(t, err)
+
+	err = j.ApplyPatch(patch)
+	assert.NotNil(t, err)
+	assert.JSONEq(t, `{"a": 1, "b": [1, 2]}`, j.ToString())
+
+	var pErr *dynjson.PatchError
+	assert.True(t, errors.As(err, &pErr))
+	assert.Equal(t, 2, pErr.Index)
+	assert.Equal(t, "test", pErr.Op)
+
+	patch = patch[:2]
+	assert.Nil(t, j.ApplyPatch(patch))
+	assert.JSONEq(t, `{"a": 1, "b": [2], "c": 3}`, j.ToString())
+
+	patch, err = dynjson.ParseList(`[{"op": "replace", "path": "", "value": 1}]`)
+	assert.Nil(t, err)
+	assert.NotNil(t, j.ApplyPatch(patch))
+
+	patch, err = dynjson.ParseList(`[{"op": "add", "path": "/x/y", "value": 1}]`)
+	assert.Nil(t, err)
+
+	err = j.ApplyPatch(patch)
+	var ptrErr *dynjson.PointerError
+	assert.True(t, errors.As(err, &ptrErr))
+	assert.Equal(t, "x", ptrErr.Segment)
+}
+
+func TestJsonList_ApplyPatch(t *testing.T) {
+	j, err := dynjson.ParseList(`[1, 2, 3]`)
+	assert.Nil(t, err)
+
+	patch, err := dynjson.ParseList(`[{"op": "remove", "path": "/0"}, {"op": "add", "path": "/-", "value": {"a": 4}}]`)
+	assert.Nil(t, err)
+
+	assert.Nil(t, j.ApplyPatch(patch))
+	assert.JSONEq(t, `[2, 3, {"a": 4}]`, j.ToString())
+}
+
+func TestApplyPatch_Shared(t *testing.T) {
+	list := dynjson.NewJsonList(dynjson.JsonListRaw{1, 2, 3})
+	doc := dynjson.JsonObject{"l": list}
+
+	patch, err := dynjson.ParseList(`[{"op": "remove", "path": "/l/0"}]`)
+	assert.Nil(t, err)
+	assert.Nil(t, doc.ApplyPatch(patch))
+
+	assert.Equal(t, `{"l":[2,3]}`, doc.ToString())
+	assert.Equal(t, `[1,2,3]`, list.ToString())
+}
+
+func TestDiff(t *testing.T) {
+	testData := []struct {
+		a, b string
+		ops  int
+	}{
+		{`{}`, `{}`, 0},
+		{`{"a": 1}`, `{"a": 1}`, 0},
+		{`{"a": 1}`, `{"a": 2}`, 1},
+		{`{"a": 1}`, `{"b": 1}`, 2},
+		{`{"a": {"b": 1, "c": 2}}`, `{"a": {"b": 1, "c": 3}}`, 1},
+		{`{"a": [1, 2, 3]}`, `{"a": [0, 1, 2, 3]}`, 1},
+		{`{"a": [1, 2, 3]}`, `{"a": [1, 3]}`, 1},
+		{`{"a": [1, 2, 3]}`, `{"a": [1, 2, 3, 4]}`, 1},
+		{`{"a": [1, 2, 3]}`, `{"a": [3, 2, 1]}`, 2},
+		{`{"a": [1, 2, 3, 4, 5]}`, `{"a": [1, 9, 3, 8, 5, 6]}`, 3},
+		{`{"a": [{"b": 1, "c": 1}]}`, `{"a": [{"b": 1, "c": 2}]}`, 1},
+		{`{"a": [1, 2]}`, `{"a": "x"}`, 1},
+		{`{"a": []}`, `{"a": [1, 2, 3]}`, 3},
+		{`{"a": [1, 2, 3]}`, `{"a": []}`, 3},
+		{`{"a/b": {"~": 1}}`, `{"a/b": {"~": null}}`, 1},
+		{`{"a": 1}`, `[1]`, 1},
+		{`[1, [2, 3]]`, `[1, [2, 4], 5]`, 2},
+	}
+
+	for _, data := range testData {
+		a, err := dynjson.Parse([]byte(data.a))
+		assert.Nil(t, err)
+		b, err := dynjson.Parse([]byte(data.b))
+		assert.Nil(t, err)
+
+		patch := dynjson.Diff(a, b)
+		assert.Equal(t, data.ops, len(patch), data.a+" -> "+data.b+": "+patch.ToString())
+
+		result, err := dynjson.ApplyPatch(a, patch)
+		assert.Nil(t, err, patch.ToString())
+		assert.JSONEq(t, data.b, result.ToString(), patch.ToString())
+		assert.JSONEq(t, data.a, a.ToString())
+	}
+}
+
+func TestDiff_LargeLists(t *testing.T) {
+	a := make([]interface{}, 10000)
+	b := make([]interface{}, 10002)
+	for i := range a {
+		a[i] = i
+		b[i] = -i
+	}
+	b[10000], b[10001] = "x", "y"
+
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	patch := dynjson.Diff(dynjson.NewJsonList(a), dynjson.NewJsonList(b))
+	runtime.ReadMemStats(&after)
+	assert.Less(t, after.TotalAlloc-before.TotalAlloc, uint64(64<<20))
+
+	result, err := dynjson.ApplyPatch(dynjson.NewJsonList(a), patch)
+	assert.Nil(t, err)
+	assert.True(t, dynjson.Equal(dynjson.NewJsonList(b), result))
+
+	result, err = dynjson.ApplyPatch(dynjson.NewJsonList(b), dynjson.Diff(dynjson.NewJsonList(b), dynjson.NewJsonList(a)))
+	assert.Nil(t, err)
+	assert.True(t, dynjson.Equal(dynjson.NewJsonList(a), result))
+}
+
+func TestDiff_Serialised(t *testing.T) {
+	a, err := dynjson.ParseObject(`{"a": 1, "b": [1, 2]}`)
+	assert.Nil(t, err)
+	b, err := dynjson.ParseObject(`{"a": 1, "b": [1, 2, 3], "c": "x"}`)
+	assert.Nil(t, err)
+
+	patch := dynjson.Diff(a, b)
+	assert.JSONEq(t, `[{"op": "add", "path": "/b/2", "value": 3}, {"op": "add", "path": "/c", "value": "x"}]`, patch.ToString())
+
+	parsed, err := dynjson.ParseList(patch.ToString())
+	assert.Nil(t, err)
+	assert.Nil(t, a.ApplyPatch(parsed))
+	assert.JSONEq(t, b.ToString(), a.ToString())
+}
@@ -0,0 +1,311 @@
+package dynjson
+
+import (
+	"fmt"
+)
+
+// SetPath sets a value deep inside the json object, e.g. SetPath([]string{"a", "b", "0", "c"}, 5).
+// Missing objects and lists along the path are created: when the following segment is a list index
+// (a number or "-"), a list is created, otherwise an object. Lists which are too short are padded with null,
+// but by at most 1024 elements, so a huge index can't exhaust the memory.
+// When a value, which is neither an object nor a list, is in the way, an error is returned.
+func (j JsonObject) SetPath(path []string, value interface{}) error {
+	_, err := setPath(j, FormatPointer(path...), path, normalize(value))
+	return err
+}
+
+// GetPath returns the value at the given path, e.g. GetPath([]string{"items", "3", "name"}).
+// List elements are addressed by their index. When the path does not exist, the value is missing.
+func (j JsonObject) GetPath(path []string) Value {
+	var node interface{} = j
+	for _, segment := range path {
+		child, reason := getChild(node, segment)
+		if reason != "" {
+			return Value{}
+		}
+		node = child
+	}
+
+	return Value{data: node, exists: true}
+}
+
+// ObjectPathOk works like ObjectOk, but takes a path instead of a field. The same applies to the other typed
+// getters ending with "Path", "PathOk" or "PathDefault".
+func (j JsonObject) ObjectPathOk(path []string) (JsonObject, bool) {
+	return j.GetPath(path).ObjectOk()
+}
+
+func (j JsonObject) ObjectPath(path []string) JsonObject {
+	val, _ := j.ObjectPathOk(path)
+	return val
+}
+
+func (j JsonObject) ListPathOk(path []string) (JsonList, bool) {
+	return j.GetPath(path).ListOk()
+}
+
+func (j JsonObject) ListPath(path []string) JsonList {
+	val, _ := j.ListPathOk(path)
+	return val
+}
+
+func (j JsonObject) StringPathOk(path []string) (string, bool) {
+	return j.GetPath(path).StringOk()
+}
+
+func (j JsonObject) StringPathDefault(path []string, def string) string {
+	val, ok := j.StringPathOk(path)
+	if ok {
+		return val
+	}
+	return def
+}
+
+func (j JsonObject) StringPath(path []string) string {
+	val, _ := j.StringPathOk(path)
+	return val
+}
+
+func (j JsonObject) Float64PathOk(path []string) (float64, bool) {
+	return j.GetPath(path).Float64Ok()
+}
+
+func (j JsonObject) Float64PathDefault(path []string, def float64) float64 {
+	val, ok := j.Float64PathOk(path)
+	if ok {
+		return val
+	}
+	return def
+}
+
+func (j JsonObject) Float64Path(path []string) float64 {
+	val, _ := j.Float64PathOk(path)
+	return val
+}
+
+func (j JsonObject) Float32PathOk(path []string) (float32, bool) {
+	return j.GetPath(path).Float32Ok()
+}
+
+func (j JsonObject) Float32PathDefault(path []string, def float32) float32 {
+	val, ok := j.Float32PathOk(path)
+	if ok {
+		return val
+	}
+	return def
+}
+
+func (j JsonObject) Float32Path(path []string) float32 {
+	val, _ := j.Float32PathOk(path)
+	return val
+}
+
+func (j JsonObject) IntPathOk(path []string) (int, bool) {
+	return j.GetPath(path).IntOk()
+}
+
+func (j JsonObject) IntPathDefault(path []string, def int) int {
+	val, ok := j.IntPathOk(path)
+	if ok {
+		return val
+	}
+	return def
+}
+
+func (j JsonObject) IntPath(path []string) int {
+	val, _ := j.IntPathOk(path)
+	return val
+}
+
+func (j JsonObject) Int64PathOk(path []string) (int64, bool) {
+	return j.GetPath(path).Int64Ok()
+}
+
+func (j JsonObject) Int64PathDefault(path []string, def int64) int64 {
+	val, ok := j.Int64PathOk(path)
+	if ok {
+		return val
+	}
+	return def
+}
+
+func (j JsonObject) Int64Path(path []string) int64 {
+	val, _ := j.Int64PathOk(path)
+	return val
+}
+
+func (j JsonObject) Int32PathOk(path []string) (int32, bool) {
+	return j.GetPath(path).Int32Ok()
+}
+
+func (j JsonObject) Int32PathDefault(path []string, def int32) int32 {
+	val, ok := j.Int32PathOk(path)
+	if ok {
+		return val
+	}
+	return def
+}
+
+func (j JsonObject) Int32Path(path []string) int32 {
+	val, _ := j.Int32PathOk(path)
+	return val
+}
+
+func (j JsonObject) Uint64PathOk(path []string) (uint64, bool) {
+	return j.GetPath(path).Uint64Ok()
+}
+
+func (j JsonObject) Uint64PathDefault(path []string, def uint64) uint64 {
+	val, ok := j.Uint64PathOk(path)
+	if ok {
+		return val
+	}
+	return def
+}
+
+func (j JsonObject) Uint64Path(path []string) uint64 {
+	val, _ := j.Uint64PathOk(path)
+	return val
+}
+
+func (j JsonObject) BoolPathOk(path []string) (bool, bool) {
+	return j.GetPath(path).BoolOk()
+}
+
+func (j JsonObject) BoolPathDefault(path []string, def bool) bool {
+	val, ok := j.BoolPathOk(path)
+	if ok {
+		return val
+	}
+	return def
+}
+
+func (j JsonObject) BoolPath(path []string) bool {
+	val, _ := j.BoolPathOk(path)
+	return val
+}
+
+// maxPadding is the maximum number of nulls, which are added to a list, when an index is behind its end.
+const maxPadding = 1024
+
+func setPath(root interface{}, ptr string, segments []string, value interface{}) (interface{}, error) {
+	if len(segments) == 0 {
+		return nil, &PointerError{Pointer: ptr, Position: -1, Reason: "can't change the document root"}
+	}
+
+	return setPathAt(root, ptr, segments, 0, value, false)
+}
+
+// setPathAt sets the value below node. Ordered tells, whether the closest object above node keeps the order of its
+// keys, so new objects keep it, too.
+func setPathAt(node interface{}, ptr string, segments []string, position int, value interface{},
+	ordered bool) (interface{}, error) {
+	segment := segments[position]
+
+	if _, ok := node.(*OrderedObject); ok {
+		ordered = true
+	} else if _, ok := convToObject(node); ok {
+		ordered = false
+	}
+
+	if position < len(segments)-1 {
+		child, reason := getOrCreateChild(node, segment, segments[position+1], ordered)
+		if reason != "" {
+			return nil, &PointerError{Pointer: ptr, Segment: segment, Position: position, Reason: reason}
+		}
+
+		value, err := setPathAt(child, ptr, segments, position+1, value, ordered)
+		if err != nil {
+			return nil, err
+		}
+		return setPaddedChild(node, segment, value), nil
+	}
+
+	if _, ok := convToObject(node); !ok {
+		if _, ok := listLen(node); !ok {
+			return nil, &PointerError{Pointer: ptr, Segment: segment, Position: position, Reason: fmt.Sprintf("can't set a child of %s", kindOf(node))}
+		}
+		if _, ok := parseIndexSegment(segment); !ok && segment != "-" {
+			return nil, &PointerError{Pointer: ptr, Segment: segment, Position: position, Reason: "invalid list index"}
+		}
+		if reason := checkPadding(node, segment); reason != "" {
+			return nil, &PointerError{Pointer: ptr, Segment: segment, Position: position, Reason: reason}
+		}
+	}
+
+	return setPaddedChild(node, segment, value), nil
+}
+
+// getOrCreateChild returns the child of node called segment. When it doesn't exist, a new container is returned,
+// which is a list, when next is a list index, and an object otherwise. New objects are ordered, when ordered is set.
+func getOrCreateChild(node interface{}, segment, next string, ordered bool) (interface{}, string) {
+	var child interface{}
+	var found bool
+
+	if obj, ok := convToObject(node); ok {
+		child, found = obj[segment]
+	} else if n, ok := listLen(node); ok {
+		index, ok := parseIndexSegment(segment)
+		if !ok && segment != "-" {
+			return nil, "invalid list index"
+		}
+		if ok && index < n {
+			child, found = listGet(node, index), true
+		}
+		if reason := checkPadding(node, segment); reason != "" {
+			return nil, reason
+		}
+	} else {
+		return nil, fmt.Sprintf("can't descend into %s", kindOf(node))
+	}
+
+	if !found {
+		if _, ok := parseIndexSegment(next); ok || next == "-" {
+			return NewJsonList(nil), ""
+		}
+		if ordered {
+			return NewOrderedObject(), ""
+		}
+		return JsonObject{}, ""
+	}
+
+	return child, ""
+}
+
+// checkPadding returns an error reason, when setting the index segment of the list node needs more than maxPadding
+// nulls.
+func checkPadding(node interface{}, segment string) string {
+	n, _ := listLen(node)
+	if index, ok := parseIndexSegment(segment); ok && index-n > maxPadding {
+		return fmt.Sprintf("index %d is too far behind the end of the list of length %d", index, n)
+	}
+	return ""
+}
+
+// setPaddedChild works like setChild, but pads lists with null, when the index is behind the end of the list.
+// Node has to be an object or a list and segment a valid list index for lists.
+func setPaddedChild(node interface{}, segment string, value interface{}) interface{} {
+	if obj, ok := node.(*OrderedObject); ok && obj != nil {
+		obj.Set(segment, value)
+		return node
+	}
+	if obj, ok := convToObject(node); ok {
+		obj[segment] = value
+		return node
+	}
+
+	n, _ := listLen(node)
+	index := n
+	if segment != "-" {
+		index, _ = parseIndexSegment(segment)
+	}
+
+	if index > n {
+		node = listPad(node, index-n)
+		n = index
+	}
+	if index == n {
+		return listInsert(node, index, value)
+	}
+	return listSet(node, index, value)
+}
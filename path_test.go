@@ -0,0 +1,84 @@
+package dynjson_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/go-schild/dynjson"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJsonObject_SetPath(t *testing.T) {
+	j := dynjson.NewJsonObject()
+
+	assert.Nil(t, j.SetPath([]string{"a", "b", "c"}, 5))
+	assert.Nil(t, j.SetPath([]string{"a", "list", "1", "name"}, "x"))
+	assert.Nil(t, j.SetPath([]string{"a", "list", "-"}, 3.5))
+	assert.Nil(t, j.SetPath([]string{"a", "b", "d"}, dynjson.NewJsonObject()))
+	assert.Nil(t, j.SetPath([]string{"a/b"}, true))
+
+	assert.JSONEq(t, `{"a": {"b": {"c": 5, "d": {}}, "list": [null, {"name": "x"}, 3.5]}, "a/b": true}`, j.ToString())
+
+	assert.NotNil(t, j.SetPath([]string{}, 1))
+	assert.NotNil(t, j.SetPath([]string{"a", "b", "c", "d"}, 1))
+	assert.NotNil(t, j.SetPath([]string{"a", "list", "x"}, 1))
+	assert.NotNil(t, j.SetPath([]string{"a", "list", "0", "x"}, 1))
+}
+
+func TestJsonObject_SetPath_Parsed(t *testing.T) {
+	j, err := dynjson.ParseObject(`{"a": [{"b": 1}]}`)
+	assert.Nil(t, err)
+
+	assert.Nil(t, j.SetPath([]string{"a", "0", "c"}, 2))
+	assert.Nil(t, j.SetPath([]string{"a", "2", "0"}, 3))
+
+	assert.JSONEq(t, `{"a": [{"b": 1, "c": 2}, null, [3]]}`, j.ToString())
+}
+
+func TestJsonObject_SetPointer_Padding(t *testing.T) {
+	j, err := dynjson.ParseObject(`{"a": [1]}`)
+	assert.Nil(t, err)
+
+	assert.Nil(t, j.SetPointer("/a/4", 2))
+	assert.Nil(t, j.SetPointer("/b/1024/c", 3))
+	assert.JSONEq(t, `[1, null, null, null, 2]`, j.List("a").ToString())
+	assert.Equal(t, 1025, j.List("b").Len())
+
+	err = j.SetPointer("/a/9999999999", 1)
+	var pErr *dynjson.PointerError
+	assert.True(t, errors.As(err, &pErr))
+	assert.Equal(t, "9999999999", pErr.Segment)
+	assert.NotNil(t, j.SetPointer("/c/1025/d", 1))
+	assert.Equal(t, 5, j.List("a").Len())
+	assert.False(t, j.Has("c"))
+}
+
+func TestJsonObject_GetPath(t *testing.T) {
+	j, err := dynjson.ParseObject(`{"a": {"b": [{"c": "x", "n": 5, "f": 1.5, "t": true}]}}`)
+	assert.Nil(t, err)
+
+	assert.Equal(t, dynjson.KindString, j.GetPath([]string{"a", "b", "0", "c"}).Kind())
+	assert.Equal(t, dynjson.KindMissing, j.GetPath([]string{"a", "b", "1"}).Kind())
+	assert.Equal(t, dynjson.KindObject, j.GetPath([]string{}).Kind())
+
+	s, ok := j.StringPathOk([]string{"a", "b", "0", "c"})
+	assert.True(t, ok)
+	assert.Equal(t, "x", s)
+
+	_, ok = j.StringPathOk([]string{"a", "b", "0", "n"})
+	assert.False(t, ok)
+
+	assert.Equal(t, "default", j.StringPathDefault([]string{"a", "none"}, "default"))
+	assert.Equal(t, 5, j.IntPath([]string{"a", "b", "0", "n"}))
+	assert.Equal(t, int64(5), j.Int64Path([]string{"a", "b", "0", "n"}))
+	assert.Equal(t, int32(-1), j.Int32PathDefault([]string{"a", "b", "0", "f"}, -1))
+	assert.Equal(t, uint64(5), j.Uint64Path([]string{"a", "b", "0", "n"}))
+	assert.Equal(t, float64(1.5), j.Float64Path([]string{"a", "b", "0", "f"}))
+	assert.Equal(t, float32(1.5), j.Float32Path([]string{"a", "b", "0", "f"}))
+	assert.True(t, j.BoolPath([]string{"a", "b", "0", "t"}))
+	assert.Equal(t, "x", j.ObjectPath([]string{"a", "b", "0"}).String("c"))
+	assert.Equal(t, 1, len(j.ListPath([]string{"a", "b"})))
+
+	_, ok = j.ListPathOk([]string{"a"})
+	assert.False(t, ok)
+}
@@ -0,0 +1,326 @@
+package dynjson
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// PointerError is returned when a JSON pointer can't be parsed or resolved.
+// It tells which segment of the pointer failed and why.
+type PointerError struct {
+	// Pointer is the complete pointer.
+	Pointer string
+	// Segment is the unescaped segment which failed. It is empty, when the pointer itself is malformed.
+	Segment string
+	// Position is the position of the failed segment, starting with 0.
+	Position int
+	// Reason describes what went wrong.
+	Reason string
+}
+
+func (e *PointerError) Error() string {
+	if e.Position < 0 {
+		return fmt.Sprintf("dynjson: invalid pointer %q: %s", e.Pointer, e.Reason)
+	}
+	return fmt.Sprintf("dynjson: pointer %q: segment %d (%q): %s", e.Pointer, e.Position, e.Segment, e.Reason)
+}
+
+// ParsePointer splits a JSON pointer (RFC 6901) like "/items/3/name" into its unescaped segments.
+// The empty pointer "" refers to the whole document and has no segments.
+func ParsePointer(ptr string) ([]string, error) {
+	if ptr == "" {
+		return []string{}, nil
+	}
+	if ptr[0] != '/' {
+		return nil, &PointerError{Pointer: ptr, Position: -1, Reason: "must be empty or start with '/'"}
+	}
+
+	segments := strings.Split(ptr[1:], "/")
+	for i, segment := range segments {
+		if !strings.Contains(segment, "~") {
+			continue
+		}
+
+		for k := 0; k < len(segment); k++ {
+			if segment[k] == '~' && (k+1 == len(segment) || (segment[k+1] != '0' && segment[k+1] != '1')) {
+				return nil, &PointerError{Pointer: ptr, Segment: segment, Position: i, Reason: "invalid escape sequence"}
+			}
+		}
+		segments[i] = strings.Replace(strings.Replace(segment, "~1", "/", -1), "~0", "~", -1)
+	}
+
+	return segments, nil
+}
+
+// FormatPointer builds a JSON pointer from unescaped segments, e.g. FormatPointer("a/b", "0") returns "/a~1b/0".
+func FormatPointer(segments ...string) string {
+	var sb strings.Builder
+	for _, segment := range segments {
+		sb.WriteByte('/')
+		sb.WriteString(EscapePointerSegment(segment))
+	}
+	return sb.String()
+}
+
+// EscapePointerSegment escapes "~" and "/" inside a single segment of a JSON pointer.
+func EscapePointerSegment(segment string) string {
+	return strings.Replace(strings.Replace(segment, "~", "~0", -1), "/", "~1", -1)
+}
+
+// Pointer returns the value the JSON pointer refers to, e.g. "/items/3/name".
+func (j JsonObject) Pointer(ptr string) (Value, error) {
+	return pointerGet(j, ptr)
+}
+
+// SetPointer sets the value the JSON pointer refers to.
+// Existing values are replaced, "-" as last segment appends to a list.
+// Missing objects and lists along the way are created, see SetPath.
+func (j JsonObject) SetPointer(ptr string, value interface{}) error {
+	segments, err := ParsePointer(ptr)
+	if err != nil {
+		return err
+	}
+
+	_, err = setPath(j, ptr, segments, normalize(value))
+	return err
+}
+
+// DeletePointer removes the value the JSON pointer refers to. Elements behind a removed list element move up.
+// The parent of a removed list element gets a new list, so other objects holding the old list aren't changed.
+func (j JsonObject) DeletePointer(ptr string) error {
+	_, err := pointerUpdate(j, ptr, deleteChild)
+	return err
+}
+
+// Pointer returns the value the JSON pointer refers to, e.g. "/3/name".
+func (j JsonList) Pointer(ptr string) (Value, error) {
+	return pointerGet(j, ptr)
+}
+
+// SetPointer sets the value the JSON pointer refers to.
+// Existing values are replaced, "-" as last segment appends to a list.
+// Missing objects and lists along the way are created, see JsonObject.SetPath.
+func (j *JsonList) SetPointer(ptr string, value interface{}) error {
+	segments, err := ParsePointer(ptr)
+	if err != nil {
+		return err
+	}
+
+	result, err := setPath(*j, ptr, segments, normalize(value))
+	if err == nil {
+		*j = result.(JsonList)
+	}
+	return err
+}
+
+// DeletePointer removes the value the JSON pointer refers to. Elements behind a removed list element move up.
+// The parent of a removed list element gets a new list, so other objects holding the old list aren't changed.
+func (j *JsonList) DeletePointer(ptr string) error {
+	result, err := pointerUpdate(*j, ptr, deleteChild)
+	if err == nil {
+		*j = result.(JsonList)
+	}
+	return err
+}
+
+// Pointer returns the value the JSON pointer refers to.
+func (v Value) Pointer(ptr string) (Value, error) {
+	if !v.exists {
+		return Value{}, &PointerError{Pointer: ptr, Position: -1, Reason: "value is missing"}
+	}
+	return pointerGet(v.data, ptr)
+}
+
+func pointerGet(root interface{}, ptr string) (Value, error) {
+	segments, err := ParsePointer(ptr)
+	if err != nil {
+		return Value{}, err
+	}
+
+	node := root
+	for i, segment := range segments {
+		child, reason := getChild(node, segment)
+		if reason != "" {
+			return Value{}, &PointerError{Pointer: ptr, Segment: segment, Position: i, Reason: reason}
+		}
+		node = child
+	}
+
+	return Value{data: node, exists: true}, nil
+}
+
+// childUpdate changes the child of parent which is called segment and returns the new parent.
+// When it fails, it returns the reason.
+type childUpdate func(parent interface{}, segment string) (interface{}, string)
+
+// pointerUpdate walks down to the parent of the value the pointer refers to and applies update on it.
+// Lists which change their length are written back into their parents. The new root is returned.
+func pointerUpdate(root interface{}, ptr string, update childUpdate) (interface{}, error) {
+	segments, err := ParsePointer(ptr)
+	if err != nil {
+		return nil, err
+	}
+	if len(segments) == 0 {
+		return nil, &PointerError{Pointer: ptr, Position: -1, Reason: "can't change the document root"}
+	}
+
+	return pointerUpdateAt(root, ptr, segments, 0, update)
+}
+
+func pointerUpdateAt(node interface{}, ptr string, segments []string, position int, update childUpdate) (interface{}, error) {
+	segment := segments[position]
+
+	if position == len(segments)-1 {
+		result, reason := update(node, segment)
+		if reason != "" {
+			return nil, &PointerError{Pointer: ptr, Segment: segment, Position: position, Reason: reason}
+		}
+		return result, nil
+	}
+
+	child, reason := getChild(node, segment)
+	if reason != "" {
+		return nil, &PointerError{Pointer: ptr, Segment: segment, Position: position, Reason: reason}
+	}
+
+	child, err := pointerUpdateAt(child, ptr, segments, position+1, update)
+	if err != nil {
+		return nil, err
+	}
+
+	result, _ := setChild(node, segment, child)
+	return result, nil
+}
+
+// getChild returns the member or element of a container called segment.
+func getChild(node interface{}, segment string) (interface{}, string) {
+	if obj, ok := convToObject(node); ok {
+		child, ok := obj[segment]
+		if !ok {
+			return nil, "member not found"
+		}
+		return child, ""
+	}
+
+	if n, ok := listLen(node); ok {
+		index, reason := parseListIndex(segment, n, false)
+		if reason != "" {
+			return nil, reason
+		}
+		return listGet(node, index), ""
+	}
+
+	return nil, fmt.Sprintf("can't descend into %s", kindOf(node))
+}
+
+// setChild replaces the member or element of a container called segment.
+// A list index equal to the list length or "-" appends to the list.
+func setChild(node interface{}, segment string, value interface{}) (interface{}, string) {
+	if obj, ok := node.(*OrderedObject); ok && obj != nil {
+		obj.Set(segment, value)
+		return node, ""
+	}
+	if obj, ok := convToObject(node); ok {
+		obj[segment] = value
+		return node, ""
+	}
+
+	if n, ok := listLen(node); ok {
+		index, reason := parseListIndex(segment, n, true)
+		if reason != "" {
+			return nil, reason
+		}
+		if index == n {
+			return listInsert(node, index, value), ""
+		}
+		return listSet(node, index, value), ""
+	}
+
+	return nil, fmt.Sprintf("can't set a child of %s", kindOf(node))
+}
+
+// insertChild works like setChild, but inserts into lists instead of replacing the element.
+func insertChild(node interface{}, segment string, value interface{}) (interface{}, string) {
+	if n, ok := listLen(node); ok {
+		index, reason := parseListIndex(segment, n, true)
+		if reason != "" {
+			return nil, reason
+		}
+		return listInsert(node, index, value), ""
+	}
+
+	return setChild(node, segment, value)
+}
+
+// replaceChild works like setChild, but the member or element has to exist already.
+func replaceChild(node interface{}, segment string, value interface{}) (interface{}, string) {
+	if _, reason := getChild(node, segment); reason != "" {
+		return nil, reason
+	}
+
+	return setChild(node, segment, value)
+}
+
+// deleteChild removes the member or element of a container called segment.
+func deleteChild(node interface{}, segment string) (interface{}, string) {
+	if obj, ok := node.(*OrderedObject); ok && obj != nil {
+		if !obj.Delete(segment) {
+			return nil, "member not found"
+		}
+		return node, ""
+	}
+	if obj, ok := convToObject(node); ok {
+		if _, ok := obj[segment]; !ok {
+			return nil, "member not found"
+		}
+		delete(obj, segment)
+		return node, ""
+	}
+
+	if n, ok := listLen(node); ok {
+		index, reason := parseListIndex(segment, n, false)
+		if reason != "" {
+			return nil, reason
+		}
+		return listRemove(node, index), ""
+	}
+
+	return nil, fmt.Sprintf("can't delete a child of %s", kindOf(node))
+}
+
+// parseListIndex parses a list index as defined by RFC 6901: no leading zeros, no signs.
+// When allowAppend is set, "-" and the length of the list are accepted and refer to the position after the last element.
+func parseListIndex(segment string, length int, allowAppend bool) (int, string) {
+	if segment == "-" {
+		if allowAppend {
+			return length, ""
+		}
+		return 0, "'-' refers to a nonexistent element"
+	}
+
+	index, ok := parseIndexSegment(segment)
+	if !ok {
+		return 0, "invalid list index"
+	}
+	if index > length || (index == length && !allowAppend) {
+		return 0, fmt.Sprintf("index out of range (length %d)", length)
+	}
+
+	return index, ""
+}
+
+// parseIndexSegment parses a non-negative decimal number without leading zeros.
+func parseIndexSegment(segment string) (int, bool) {
+	if segment == "" || (len(segment) > 1 && segment[0] == '0') {
+		return 0, false
+	}
+	for _, c := range segment {
+		if c < '0' || c > '9' {
+			return 0, false
+		}
+	}
+
+	index, err := strconv.Atoi(segment)
+	return index, err == nil
+}
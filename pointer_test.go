@@ -0,0 +1,214 @@
+package dynjson_test
+
+import (
+	"testing"
+
+	"github.com/go-schild/dynjson"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParsePointer(t *testing.T) {
+	segments, err := dynjson.ParsePointer("")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{}, segments)
+
+	segments, err = dynjson.ParsePointer("/a~1b/m~0n/0/")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"a/b", "m~n", "0", ""}, segments)
+
+	segments, err = dynjson.ParsePointer("/~01")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"~1"}, segments)
+
+	_, err = dynjson.ParsePointer("a/b")
+	assert.NotNil(t, err)
+
+	_, err = dynjson.ParsePointer("/a~2")
+	assert.NotNil(t, err)
+
+	_, err = dynjson.ParsePointer("/a~")
+	assert.NotNil(t, err)
+}
+
+func TestFormatPointer(t *testing.T) {
+	assert.Equal(t, "", dynjson.FormatPointer())
+	assert.Equal(t, "/a~1b/m~0n/0", dynjson.FormatPointer("a/b", "m~n", "0"))
+	assert.Equal(t, "~01", dynjson.EscapePointerSegment("~1"))
+}
+
+func TestJsonObject_Pointer(t *testing.T) {
+	// Examples from RFC 6901, section 5
+	const testData = `{
+		"foo": ["bar", "baz"],
+		"": 0,
+		"a/b": 1,
+		"c%d": 2,
+		"e^f": 3,
+		"g|h": 4,
+		"i\\j": 5,
+		"k\"l": 6,
+		" ": 7,
+		"m~n": 8
+	}`
+
+	j, err := dynjson.ParseObject(testData)
+	assert.Nil(t, err)
+
+	v, err := j.Pointer("")
+	assert.Nil(t, err)
+	assert.Equal(t, dynjson.KindObject, v.Kind())
+
+	v, err = j.Pointer("/foo")
+	assert.Nil(t, err)
+	assert.Equal(t, `["bar","baz"]`, v.ToString())
+
+	v, err = j.Pointer("/foo/0")
+	assert.Nil(t, err)
+	assert.Equal(t, "bar", v.String())
+
+	expected := map[string]int{"/": 0, "/a~1b": 1, "/c%d": 2, "/e^f": 3, "/g|h": 4, "/i\\j": 5, "/k\"l": 6, "/ ": 7, "/m~0n": 8}
+	for ptr, val := range expected {
+		v, err = j.Pointer(ptr)
+		assert.Nil(t, err, ptr)
+		assert.Equal(t, val, v.Int(), ptr)
+	}
+}
+
+func TestJsonObject_Pointer_Errors(t *testing.T) {
+	const testData = `{"items": [{"name": "a"}, {"name": "b"}], "n": 5}`
+
+	j, err := dynjson.ParseObject(testData)
+	assert.Nil(t, err)
+
+	v, err := j.Pointer("/items/1/name")
+	assert.Nil(t, err)
+	assert.Equal(t, "b", v.String())
+
+	testErrors := map[string]int{
+		"/items/3/name": 1,
+		"/items/01":     1,
+		"/items/-":      1,
+		"/items/x":      1,
+		"/none":         0,
+		"/n/a":          1,
+		"/items/0/age":  2,
+	}
+
+	for ptr, position := range testErrors {
+		v, err = j.Pointer(ptr)
+		assert.Equal(t, dynjson.KindMissing, v.Kind(), ptr)
+
+		pErr, ok := err.(*dynjson.PointerError)
+		if assert.True(t, ok, ptr) {
+			assert.Equal(t, ptr, pErr.Pointer)
+			assert.Equal(t, position, pErr.Position, ptr)
+		}
+	}
+
+	_, err = j.Pointer("/items/3/name")
+	assert.EqualError(t, err, `dynjson: pointer "/items/3/name": segment 1 ("3"): index out of range (length 2)`)
+}
+
+func TestJsonObject_SetPointer(t *testing.T) {
+	const testData = `{"items": [{"name": "a"}, {"name": "b"}], "obj": {}}`
+
+	j, err := dynjson.ParseObject(testData)
+	assert.Nil(t, err)
+
+	assert.Nil(t, j.SetPointer("/items/1/name", "c"))
+	assert.Nil(t, j.SetPointer("/items/-", "d"))
+	assert.Nil(t, j.SetPointer("/items/3", 5))
+	assert.Nil(t, j.SetPointer("/obj/a~1b", true))
+	assert.Nil(t, j.SetPointer("/new", dynjson.NewJsonList(nil)))
+
+	assert.JSONEq(t, `{"items": [{"name": "a"}, {"name": "c"}, "d", 5], "obj": {"a/b": true}, "new": []}`, j.ToString())
+
+	assert.NotNil(t, j.SetPointer("", 1))
+	assert.NotNil(t, j.SetPointer("/items/2/a", 1))
+	assert.NotNil(t, j.SetPointer("/items/x", 1))
+	assert.NotNil(t, j.SetPointer("/items/01/a", 1))
+}
+
+func TestJsonObject_SetPointer_Create(t *testing.T) {
+	j := dynjson.NewJsonObject()
+
+	assert.Nil(t, j.SetPointer("/a/b/0/c", 1))
+	assert.Nil(t, j.SetPointer("/a/b/2", "x"))
+	assert.Nil(t, j.SetPointer("/a/b/-/d", true))
+	assert.Nil(t, j.SetPointer("/a/e~1f/g", nil))
+
+	assert.JSONEq(t, `{"a": {"b": [{"c": 1}, null, "x", {"d": true}], "e/f": {"g": null}}}`, j.ToString())
+
+	err := j.SetPointer("/a/b/2/c", 1)
+	pErr, ok := err.(*dynjson.PointerError)
+	assert.True(t, ok)
+	assert.Equal(t, 3, pErr.Position)
+	assert.Equal(t, "c", pErr.Segment)
+
+	err = j.SetPointer("/a/b/1/c", 1)
+	pErr, ok = err.(*dynjson.PointerError)
+	assert.True(t, ok)
+	assert.Equal(t, 3, pErr.Position)
+}
+
+func TestJsonObject_DeletePointer(t *testing.T) {
+	const testData = `{"items": [1, 2, [3, 4]], "obj": {"a": 1, "b": 2}}`
+
+	j, err := dynjson.ParseObject(testData)
+	assert.Nil(t, err)
+
+	assert.Nil(t, j.DeletePointer("/items/0"))
+	assert.Nil(t, j.DeletePointer("/items/1/0"))
+	assert.Nil(t, j.DeletePointer("/obj/a"))
+
+	assert.JSONEq(t, `{"items": [2, [4]], "obj": {"b": 2}}`, j.ToString())
+
+	assert.NotNil(t, j.DeletePointer("/obj/a"))
+	assert.NotNil(t, j.DeletePointer("/items/-"))
+	assert.NotNil(t, j.DeletePointer("/items/5"))
+	assert.NotNil(t, j.DeletePointer(""))
+}
+
+func TestJsonObject_DeletePointer_Shared(t *testing.T) {
+	list := dynjson.NewJsonList(dynjson.JsonListRaw{1, 2, 3})
+	a := dynjson.JsonObject{"l": list}
+	b := dynjson.JsonObject{"l": list}
+
+	assert.Nil(t, a.DeletePointer("/l/0"))
+	assert.Equal(t, `{"l":[2,3]}`, a.ToString())
+	assert.Equal(t, `{"l":[1,2,3]}`, b.ToString())
+	assert.Equal(t, `[1,2,3]`, list.ToString())
+}
+
+func TestJsonList_Pointer(t *testing.T) {
+	const testData = `[{"a": [1, 2]}, "b"]`
+
+	j, err := dynjson.ParseList(testData)
+	assert.Nil(t, err)
+
+	v, err := j.Pointer("/0/a/1")
+	assert.Nil(t, err)
+	assert.Equal(t, 2, v.Int())
+
+	assert.Nil(t, j.SetPointer("/-", "c"))
+	assert.Nil(t, j.SetPointer("/0/a/-", 3))
+	assert.Nil(t, j.DeletePointer("/1"))
+
+	assert.JSONEq(t, `[{"a": [1, 2, 3]}, "c"]`, j.ToString())
+	assert.Equal(t, 2, len(j))
+
+	_, err = j.Pointer("/2")
+	assert.NotNil(t, err)
+}
+
+func TestValue_Pointer(t *testing.T) {
+	v, err := dynjson.Parse([]byte(`[[1, {"a": true}]]`))
+	assert.Nil(t, err)
+
+	b, err := v.Pointer("/0/1/a")
+	assert.Nil(t, err)
+	assert.True(t, b.Bool())
+
+	_, err = dynjson.Value{}.Pointer("")
+	assert.NotNil(t, err)
+}
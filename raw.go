@@ -0,0 +1,554 @@
+package dynjson
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// RawDocument looks up values in json data without parsing the whole document. It only scans the data along the
+// path and returns sub-slices of it, so reading a few fields of a large document doesn't allocate its tree.
+//
+// Only the scanned parts are checked for syntax errors, the rest of the document is not validated.
+// When an object contains a key more than once, the last value is used, like ParseObject does.
+type RawDocument struct {
+	data []byte
+}
+
+// NewRawDocument creates a RawDocument for data. The data is not copied and must not be changed while the document
+// or values returned by it are in use.
+func NewRawDocument(data []byte) RawDocument {
+	return RawDocument{data: data}
+}
+
+// Pointer returns the value the JSON pointer refers to, e.g. "/items/3/name". It fails with a *PointerError,
+// when the value doesn't exist, and with an *Error, when the scanned data is malformed.
+func (d RawDocument) Pointer(ptr string) (RawValue, error) {
+	segments, err := ParsePointer(ptr)
+	if err != nil {
+		return RawValue{}, err
+	}
+	return d.lookup(ptr, segments)
+}
+
+// Path returns the value at the dotted path, e.g. "items.3.name". List elements are addressed by their index.
+// Use Pointer or Lookup for keys containing dots. The empty path refers to the whole document. See Pointer.
+func (d RawDocument) Path(path string) (RawValue, error) {
+	if path == "" {
+		return d.Lookup()
+	}
+	return d.Lookup(strings.Split(path, ".")...)
+}
+
+// Lookup returns the value at the path given by its segments, e.g. Lookup("items", "3", "name"). See Pointer.
+func (d RawDocument) Lookup(segments ...string) (RawValue, error) {
+	return d.lookup("", segments)
+}
+
+// lookup walks down the segments. The pointer is only used for errors, when it is empty, it is built from segments.
+func (d RawDocument) lookup(ptr string, segments []string) (RawValue, error) {
+	s := scanner{data: d.data}
+	start := s.skipSpace(0)
+
+	for i, segment := range segments {
+		var child int
+		var reason string
+		var err error
+
+		switch s.at(start) {
+		case '{':
+			child, reason, err = s.member(start, segment)
+		case '[':
+			child, reason, err = s.element(start, segment)
+		default:
+			if _, err := s.skipValue(start); err != nil {
+				return RawValue{}, err
+			}
+			reason = fmt.Sprintf("can't descend into %s", rawKind(d.data[start:]))
+		}
+		if err != nil {
+			return RawValue{}, err
+		}
+		if reason != "" {
+			if ptr == "" {
+				ptr = FormatPointer(segments...)
+			}
+			return RawValue{}, &PointerError{Pointer: ptr, Segment: segment, Position: i, Reason: reason}
+		}
+		start = child
+	}
+
+	end, err := s.skipValue(start)
+	if err != nil {
+		return RawValue{}, err
+	}
+	return RawValue{data: d.data[start:end]}, nil
+}
+
+// RawValue is a json value inside of a RawDocument. The zero RawValue is missing.
+type RawValue struct {
+	data []byte
+}
+
+// Raw returns the json encoding of the value. It is a sub-slice of the document's data.
+func (r RawValue) Raw() []byte {
+	return r.data
+}
+
+// Kind returns the JSON type of the value.
+func (r RawValue) Kind() Kind {
+	if r.data == nil {
+		return KindMissing
+	}
+	return rawKind(r.data)
+}
+
+// Value parses the value. Objects and lists are parsed completely.
+func (r RawValue) Value(opts ...ParseOption) (Value, error) {
+	if r.data == nil {
+		return Value{}, nil
+	}
+	return Parse(r.data, opts...)
+}
+
+// StringOk returns the string value. Like the other typed getters, it only decodes the value itself, numbers are read
+// without losing precision. See Value for the conversion rules.
+func (r RawValue) StringOk() (string, bool) {
+	return r.scalar().StringOk()
+}
+
+func (r RawValue) StringDefault(def string) string {
+	return r.scalar().StringDefault(def)
+}
+
+func (r RawValue) String() string {
+	return r.scalar().String()
+}
+
+func (r RawValue) StringErr() (string, error) {
+	return r.scalar().StringErr()
+}
+
+func (r RawValue) Float64Ok() (float64, bool) {
+	return r.scalar().Float64Ok()
+}
+
+func (r RawValue) Float64Default(def float64) float64 {
+	return r.scalar().Float64Default(def)
+}
+
+func (r RawValue) Float64() float64 {
+	return r.scalar().Float64()
+}
+
+func (r RawValue) Float64Err() (float64, error) {
+	return r.scalar().Float64Err()
+}
+
+func (r RawValue) Float32Ok() (float32, bool) {
+	return r.scalar().Float32Ok()
+}
+
+func (r RawValue) Float32Default(def float32) float32 {
+	return r.scalar().Float32Default(def)
+}
+
+func (r RawValue) Float32() float32 {
+	return r.scalar().Float32()
+}
+
+func (r RawValue) Float32Err() (float32, error) {
+	return r.scalar().Float32Err()
+}
+
+func (r RawValue) IntOk() (int, bool) {
+	return r.scalar().IntOk()
+}
+
+func (r RawValue) IntDefault(def int) int {
+	return r.scalar().IntDefault(def)
+}
+
+func (r RawValue) Int() int {
+	return r.scalar().Int()
+}
+
+func (r RawValue) IntErr() (int, error) {
+	return r.scalar().IntErr()
+}
+
+func (r RawValue) Int64Ok() (int64, bool) {
+	return r.scalar().Int64Ok()
+}
+
+func (r RawValue) Int64Default(def int64) int64 {
+	return r.scalar().Int64Default(def)
+}
+
+func (r RawValue) Int64() int64 {
+	return r.scalar().Int64()
+}
+
+func (r RawValue) Int64Err() (int64, error) {
+	return r.scalar().Int64Err()
+}
+
+func (r RawValue) Int32Ok() (int32, bool) {
+	return r.scalar().Int32Ok()
+}
+
+func (r RawValue) Int32Default(def int32) int32 {
+	return r.scalar().Int32Default(def)
+}
+
+func (r RawValue) Int32() int32 {
+	return r.scalar().Int32()
+}
+
+func (r RawValue) Int32Err() (int32, error) {
+	return r.scalar().Int32Err()
+}
+
+func (r RawValue) Uint64Ok() (uint64, bool) {
+	return r.scalar().Uint64Ok()
+}
+
+func (r RawValue) Uint64Default(def uint64) uint64 {
+	return r.scalar().Uint64Default(def)
+}
+
+func (r RawValue) Uint64() uint64 {
+	return r.scalar().Uint64()
+}
+
+func (r RawValue) Uint64Err() (uint64, error) {
+	return r.scalar().Uint64Err()
+}
+
+func (r RawValue) BoolOk() (bool, bool) {
+	return r.scalar().BoolOk()
+}
+
+func (r RawValue) BoolDefault(def bool) bool {
+	return r.scalar().BoolDefault(def)
+}
+
+func (r RawValue) Bool() bool {
+	return r.scalar().Bool()
+}
+
+func (r RawValue) BoolErr() (bool, error) {
+	return r.scalar().BoolErr()
+}
+
+// scalar converts strings, numbers, booleans and null into a Value. Numbers are kept as json.Number.
+// Objects and lists are represented by an empty container, so the typed getters fail with the right kind.
+func (r RawValue) scalar() Value {
+	if r.data == nil {
+		return Value{}
+	}
+
+	switch r.data[0] {
+	case '{':
+		return Value{data: map[string]interface{}(nil), exists: true}
+	case '[':
+		return Value{data: []interface{}(nil), exists: true}
+	case '"':
+		if bytes.IndexByte(r.data, '\\') < 0 {
+			return Value{data: string(r.data[1 : len(r.data)-1]), exists: true}
+		}
+		// The escapes were validated by the scanner, so the string can always be decoded.
+		var s string
+		_ = json.Unmarshal(r.data, &s)
+		return Value{data: s, exists: true}
+	case 't':
+		return Value{data: true, exists: true}
+	case 'f':
+		return Value{data: false, exists: true}
+	case 'n':
+		return Value{data: nil, exists: true}
+	}
+
+	return Value{data: json.Number(r.data), exists: true}
+}
+
+func rawKind(data []byte) Kind {
+	switch data[0] {
+	case '{':
+		return KindObject
+	case '[':
+		return KindArray
+	case '"':
+		return KindString
+	case 't', 'f':
+		return KindBool
+	case 'n':
+		return KindNull
+	}
+	return KindNumber
+}
+
+// scanner finds the boundaries of json values in data.
+type scanner struct {
+	data []byte
+}
+
+// at returns the byte at position i or 0 at the end of the data.
+func (s *scanner) at(i int) byte {
+	if i >= len(s.data) {
+		return 0
+	}
+	return s.data[i]
+}
+
+func (s *scanner) skipSpace(i int) int {
+	for i < len(s.data) && isSpace(s.data[i]) {
+		i++
+	}
+	return i
+}
+
+// error returns a syntax error for the byte at position i.
+func (s *scanner) error(i int, context string) error {
+	if i >= len(s.data) {
+		return syntaxError(io.ErrUnexpectedEOF, int64(len(s.data)), newlineOffsets(s.data))
+	}
+
+	line, column := position(int64(i)+1, newlineOffsets(s.data[:i+1]))
+	reason := fmt.Sprintf("invalid character %q %s", s.data[i], context)
+	return &Error{Kind: ErrorKindSyntax, Offset: int64(i) + 1, Line: line, Column: column, Reason: reason}
+}
+
+// member finds the value of the key in the object starting at i. The last occurrence of the key wins.
+func (s *scanner) member(i int, key string) (int, string, error) {
+	found := -1
+	i = s.skipSpace(i + 1)
+	if s.at(i) == '}' {
+		return 0, "member not found", nil
+	}
+
+	for {
+		if s.at(i) != '"' {
+			return 0, "", s.error(i, "looking for beginning of object key string")
+		}
+		end, err := s.skipString(i)
+		if err != nil {
+			return 0, "", err
+		}
+		match := s.keyEquals(s.data[i:end], key)
+
+		i = s.skipSpace(end)
+		if s.at(i) != ':' {
+			return 0, "", s.error(i, "after object key")
+		}
+		i = s.skipSpace(i + 1)
+		if match {
+			found = i
+		}
+		if i, err = s.skipValue(i); err != nil {
+			return 0, "", err
+		}
+
+		i = s.skipSpace(i)
+		switch s.at(i) {
+		case ',':
+			i = s.skipSpace(i + 1)
+		case '}':
+			if found < 0 {
+				return 0, "member not found", nil
+			}
+			return found, "", nil
+		default:
+			return 0, "", s.error(i, "after object key:value pair")
+		}
+	}
+}
+
+// keyEquals compares a quoted key with an unescaped one.
+func (s *scanner) keyEquals(quoted []byte, key string) bool {
+	if bytes.IndexByte(quoted, '\\') < 0 {
+		return string(quoted[1:len(quoted)-1]) == key
+	}
+	var unquoted string
+	return json.Unmarshal(quoted, &unquoted) == nil && unquoted == key
+}
+
+// element finds the element of the list starting at i.
+func (s *scanner) element(i int, segment string) (int, string, error) {
+	index, ok := parseIndexSegment(segment)
+	if !ok {
+		return 0, "invalid list index", nil
+	}
+
+	i = s.skipSpace(i + 1)
+	if s.at(i) == ']' {
+		return 0, "index out of range (length 0)", nil
+	}
+
+	for n := 0; ; n++ {
+		if n == index {
+			return i, "", nil
+		}
+
+		var err error
+		if i, err = s.skipValue(i); err != nil {
+			return 0, "", err
+		}
+		i = s.skipSpace(i)
+		switch s.at(i) {
+		case ',':
+			i = s.skipSpace(i + 1)
+		case ']':
+			return 0, fmt.Sprintf("index out of range (length %d)", n+1), nil
+		default:
+			return 0, "", s.error(i, "after array element")
+		}
+	}
+}
+
+// skipValue returns the end of the value starting at i. Nested values are only checked roughly.
+func (s *scanner) skipValue(i int) (int, error) {
+	switch c := s.at(i); {
+	case c == '"':
+		return s.skipString(i)
+	case c == '{' || c == '[':
+		return s.skipContainer(i)
+	case c == 't':
+		return s.skipLiteral(i, "true")
+	case c == 'f':
+		return s.skipLiteral(i, "false")
+	case c == 'n':
+		return s.skipLiteral(i, "null")
+	case c == '-' || (c >= '0' && c <= '9'):
+		return s.skipNumber(i)
+	}
+	return 0, s.error(i, "looking for beginning of value")
+}
+
+// skipString returns the end of the string starting at i. Escape sequences are validated, so the typed getters of
+// RawValue and the comparison of keys can rely on decoding the string.
+func (s *scanner) skipString(i int) (int, error) {
+	for k := i + 1; k < len(s.data); k++ {
+		switch c := s.data[k]; {
+		case c == '"':
+			return k + 1, nil
+		case c == '\\':
+			k++
+			switch s.at(k) {
+			case '"', '\\', '/', 'b', 'f', 'n', 'r', 't':
+			case 'u':
+				for n := 0; n < 4; n++ {
+					k++
+					if !isHex(s.at(k)) {
+						return 0, s.error(k, "in \\u hexadecimal character escape")
+					}
+				}
+			default:
+				return 0, s.error(k, "in string escape code")
+			}
+		case c < 0x20:
+			return 0, s.error(k, "in string literal")
+		}
+	}
+	return 0, s.error(len(s.data), "")
+}
+
+func isHex(c byte) bool {
+	return '0' <= c && c <= '9' || 'a' <= c && c <= 'f' || 'A' <= c && c <= 'F'
+}
+
+func (s *scanner) skipLiteral(i int, literal string) (int, error) {
+	for k := 0; k < len(literal); k++ {
+		if s.at(i+k) != literal[k] {
+			return 0, s.error(i+k, "in literal "+literal)
+		}
+	}
+	return i + len(literal), nil
+}
+
+// skipNumber returns the end of the number starting at i. The number is validated, when it is read.
+func (s *scanner) skipNumber(i int) (int, error) {
+	k := i + 1
+	for k < len(s.data) && strings.IndexByte("0123456789.eE+-", s.data[k]) >= 0 {
+		k++
+	}
+	if !validNumber(s.data[i:k]) {
+		return 0, s.error(k-1, "in numeric literal")
+	}
+	return k, nil
+}
+
+// validNumber checks the grammar of json numbers: -?(0|[1-9][0-9]*)(\.[0-9]+)?([eE][+-]?[0-9]+)?
+func validNumber(b []byte) bool {
+	digits := func(i int) int {
+		for i < len(b) && b[i] >= '0' && b[i] <= '9' {
+			i++
+		}
+		return i
+	}
+
+	i := 0
+	if i < len(b) && b[i] == '-' {
+		i++
+	}
+	switch {
+	case i < len(b) && b[i] == '0':
+		i++
+	case i < len(b) && b[i] >= '1' && b[i] <= '9':
+		i = digits(i)
+	default:
+		return false
+	}
+
+	if i < len(b) && b[i] == '.' {
+		end := digits(i + 1)
+		if end == i+1 {
+			return false
+		}
+		i = end
+	}
+
+	if i < len(b) && (b[i] == 'e' || b[i] == 'E') {
+		i++
+		if i < len(b) && (b[i] == '+' || b[i] == '-') {
+			i++
+		}
+		end := digits(i)
+		if end == i {
+			return false
+		}
+		i = end
+	}
+
+	return i == len(b)
+}
+
+// skipContainer returns the end of the object or list starting at i. It only checks, that brackets and braces match.
+func (s *scanner) skipContainer(i int) (int, error) {
+	var buf [32]byte
+	stack := append(buf[:0], s.data[i])
+	for k := i + 1; k < len(s.data); k++ {
+		switch c := s.data[k]; c {
+		case '"':
+			end, err := s.skipString(k)
+			if err != nil {
+				return 0, err
+			}
+			k = end - 1
+		case '{', '[':
+			stack = append(stack, c)
+		case '}', ']':
+			open := stack[len(stack)-1]
+			if (open == '{') != (c == '}') {
+				return 0, s.error(k, "after nested value")
+			}
+			stack = stack[:len(stack)-1]
+			if len(stack) == 0 {
+				return k + 1, nil
+			}
+		}
+	}
+	return 0, s.error(len(s.data), "")
+}
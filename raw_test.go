@@ -0,0 +1,210 @@
+package dynjson_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/go-schild/dynjson"
+	"github.com/stretchr/testify/assert"
+)
+
+const rawTestData = `{
+  "id": 9007199254740993,
+  "name": "café",
+  "plain": "abc",
+  "ok": true,
+  "nothing": null,
+  "ratio": 1.5e2,
+  "a.b": {"c": 1},
+  "a~/": 2,
+  "items": [
+    {"name": "first", "tags": ["x", "}", "]"]},
+    {"name": "second", "nested": [[1, 2], {"k": "v"}]}
+  ],
+  "dup": 1,
+  "dup": 2
+}`
+
+func TestRawDocument_Path(t *testing.T) {
+	doc := dynjson.NewRawDocument([]byte(rawTestData))
+
+	v, err := doc.Path("id")
+	assert.Nil(t, err)
+	assert.Equal(t, int64(9007199254740993), v.Int64())
+	assert.Equal(t, dynjson.KindNumber, v.Kind())
+
+	v, err = doc.Path("name")
+	assert.Nil(t, err)
+	assert.Equal(t, "café", v.String())
+	assert.Equal(t, `"café"`, string(v.Raw()))
+
+	v, _ = doc.Path("plain")
+	assert.Equal(t, "abc", v.String())
+	v, _ = doc.Path("ok")
+	assert.True(t, v.Bool())
+	v, _ = doc.Path("nothing")
+	assert.Equal(t, dynjson.KindNull, v.Kind())
+	v, _ = doc.Path("ratio")
+	assert.Equal(t, 150, v.Int())
+	v, _ = doc.Path("dup")
+	assert.Equal(t, 2, v.Int())
+
+	v, err = doc.Path("items.1.nested.1.k")
+	assert.Nil(t, err)
+	assert.Equal(t, "v", v.String())
+
+	v, err = doc.Path("items.0.tags")
+	assert.Nil(t, err)
+	assert.Equal(t, `["x", "}", "]"]`, string(v.Raw()))
+	assert.Equal(t, dynjson.KindArray, v.Kind())
+	_, err = v.StringErr()
+	assert.EqualError(t, err, "dynjson: expected string, got array")
+	list, err := v.Value()
+	assert.Nil(t, err)
+	assert.Equal(t, "}", list.List()[1].String())
+
+	v, err = doc.Path("")
+	assert.Nil(t, err)
+	assert.Equal(t, rawTestData, string(v.Raw()))
+}
+
+func TestRawDocument_Pointer(t *testing.T) {
+	doc := dynjson.NewRawDocument([]byte(rawTestData))
+
+	v, err := doc.Pointer("/a.b/c")
+	assert.Nil(t, err)
+	assert.Equal(t, 1, v.Int())
+
+	v, err = doc.Pointer("/a~0~1")
+	assert.Nil(t, err)
+	assert.Equal(t, 2, v.Int())
+
+	v, err = doc.Lookup("items", "1", "name")
+	assert.Nil(t, err)
+	assert.Equal(t, "second", v.String())
+
+	_, err = doc.Pointer("a")
+	assert.NotNil(t, err)
+
+	testData := []struct {
+		ptr, err string
+	}{
+		{"/missing", `dynjson: pointer "/missing": segment 0 ("missing"): member not found`},
+		{"/items/2", `dynjson: pointer "/items/2": segment 1 ("2"): index out of range (length 2)`},
+		{"/items/x", `dynjson: pointer "/items/x": segment 1 ("x"): invalid list index`},
+		{"/id/x", `dynjson: pointer "/id/x": segment 1 ("x"): can't descend into number`},
+		{"/a.b/c/d", `dynjson: pointer "/a.b/c/d": segment 2 ("d"): can't descend into number`},
+	}
+	for _, data := range testData {
+		v, err := doc.Pointer(data.ptr)
+		assert.EqualError(t, err, data.err)
+		assert.Equal(t, dynjson.KindMissing, v.Kind())
+		_, ok := v.IntOk()
+		assert.False(t, ok)
+	}
+}
+
+func TestRawDocument_Syntax(t *testing.T) {
+	testData := []struct {
+		data, path, err string
+	}{
+		{`{"a": 1, "b" 2}`, "b", "dynjson: syntax error at line 1, column 14 (offset 14): invalid character '2' after object key"},
+		{`{"a": tru}`, "a", "dynjson: syntax error at line 1, column 10 (offset 10): invalid character '}' in literal true"},
+		{"{\"a\": [1,\n 2 3]}", "a.5", "dynjson: syntax error at line 2, column 4 (offset 14): invalid character '3' after array element"},
+		{`{"a": {"b": [}}`, "c", "dynjson: syntax error at line 1, column 14 (offset 14): invalid character '}' after nested value"},
+		{`{"a": 1.2.3}`, "a", "dynjson: syntax error at line 1, column 11 (offset 11): invalid character '3' in numeric literal"},
+		{`{"a": "x`, "a", "dynjson: syntax error at line 1, column 8 (offset 8): unexpected EOF"},
+		{`{"a": "x\q"}`, "a", "dynjson: syntax error at line 1, column 10 (offset 10): invalid character 'q' in string escape code"},
+		{`{"a\q": 1, "b": 2}`, "b", "dynjson: syntax error at line 1, column 5 (offset 5): invalid character 'q' in string escape code"},
+		{`{"a": ["\u12x4"]}`, "a", "dynjson: syntax error at line 1, column 13 (offset 13): invalid character 'x' in \\u hexadecimal character escape"},
+		{`{"a": "\`, "a", "dynjson: syntax error at line 1, column 8 (offset 8): unexpected EOF"},
+		{``, "", "dynjson: syntax error at line 1, column 1 (offset 0): unexpected EOF"},
+	}
+
+	for _, data := range testData {
+		_, err := dynjson.NewRawDocument([]byte(data.data)).Path(data.path)
+		assert.True(t, errors.Is(err, dynjson.ErrSyntax), data.data)
+		assert.EqualError(t, err, data.err)
+	}
+
+	// Parts of the document, which are not scanned, are not validated.
+	v, err := dynjson.NewRawDocument([]byte(`{"a": 1, "b": {"x": invalid}}`)).Path("a")
+	assert.Nil(t, err)
+	assert.Equal(t, 1, v.Int())
+	_, err = dynjson.NewRawDocument([]byte(`{"a": 1, "b": {"x": invalid}}`)).Path("b")
+	assert.Nil(t, err)
+}
+
+func TestRawDocument_Equivalence(t *testing.T) {
+	j, err := dynjson.ParseObject(rawTestData, dynjson.UseNumber())
+	assert.Nil(t, err)
+	doc := dynjson.NewRawDocument([]byte(rawTestData))
+
+	for _, path := range [][]string{{"id"}, {"name"}, {"ok"}, {"ratio"}, {"dup"}, {"items"}, {"items", "1", "nested"}} {
+		v, err := doc.Lookup(path...)
+		assert.Nil(t, err)
+		parsed, err := v.Value(dynjson.UseNumber())
+		assert.Nil(t, err)
+		assert.True(t, dynjson.Equal(j.GetPath(path), parsed), path)
+	}
+}
+
+// benchmarkData returns a document of about 200 KB with the fields read by the benchmarks at its end.
+func benchmarkData() []byte {
+	var buf bytes.Buffer
+	buf.WriteString(`{"items": [`)
+	for i := 0; i < 2000; i++ {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		fmt.Fprintf(&buf, `{"id": %d, "name": "item %d", "price": %d.99, "tags": ["a", "b"], "active": true}`, i, i, i)
+	}
+	buf.WriteString(`], "route": {"service": "billing", "region": "eu-1"}}`)
+	return buf.Bytes()
+}
+
+func BenchmarkRawDocument_Path(b *testing.B) {
+	data := benchmarkData()
+	b.SetBytes(int64(len(data)))
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		doc := dynjson.NewRawDocument(data)
+		service, _ := doc.Path("route.service")
+		region, _ := doc.Path("route.region")
+		if service.String() != "billing" || region.String() != "eu-1" {
+			b.Fatal("unexpected result")
+		}
+	}
+}
+
+func BenchmarkParseObject_Chain(b *testing.B) {
+	data := benchmarkData()
+	b.SetBytes(int64(len(data)))
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		j, err := dynjson.ParseObject(string(data))
+		if err != nil {
+			b.Fatal(err)
+		}
+		route := j.Chain("route")
+		if route.String("service") != "billing" || route.String("region") != "eu-1" {
+			b.Fatal("unexpected result")
+		}
+	}
+}
+
+func TestRawDocument_Numbers(t *testing.T) {
+	testData := map[string]bool{
+		"0": true, "-0": true, "12": true, "1.5": true, "-1.5e10": true, "1E+2": true, "2e-3": true,
+		"01": false, "1.": false, ".5": false, "-": false, "1e": false, "1e+": false, "--1": false, "1.2.3": false,
+	}
+
+	for number, valid := range testData {
+		_, err := dynjson.NewRawDocument([]byte(`[` + number + `]`)).Path("0")
+		assert.Equal(t, valid, err == nil, number)
+	}
+}
@@ -0,0 +1,424 @@
+package dynjson
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// ErrTrailingData is returned when DisallowTrailingData is set and more data follows the json value.
+var ErrTrailingData = errors.New("dynjson: invalid data after top-level value")
+
+// Limit names a limit of the parser, which can be set by a ParseOption.
+type Limit int
+
+const (
+	LimitBytes Limit = iota + 1
+	LimitDepth
+	LimitStringLength
+	LimitKeys
+)
+
+var limitNames = map[Limit]string{
+	LimitBytes:        "size in bytes",
+	LimitDepth:        "nesting depth",
+	LimitStringLength: "string length",
+	LimitKeys:         "number of keys",
+}
+
+// String returns a description of the limit, e.g. "nesting depth".
+func (l Limit) String() string {
+	if name, ok := limitNames[l]; ok {
+		return name
+	}
+	return "unknown limit"
+}
+
+// LimitError is returned when the input exceeds one of the limits set by MaxBytes, MaxDepth, MaxStringLength or
+// MaxKeys.
+type LimitError struct {
+	// Limit is the limit which was exceeded.
+	Limit Limit
+	// Max is the configured maximum.
+	Max int64
+	// Offset is the position in the input, where the limit was exceeded.
+	Offset int64
+}
+
+func (e *LimitError) Error() string {
+	return fmt.Sprintf("dynjson: maximum %s of %d exceeded at offset %d", e.Limit, e.Max, e.Offset)
+}
+
+// ParseReader reads a json value from r. It stops reading after the value, unless DisallowTrailingData is set.
+// Use the options MaxBytes, MaxDepth, MaxStringLength, MaxKeys and DuplicateKeys to protect against malicious input.
+func ParseReader(r io.Reader, opts ...ParseOption) (Value, error) {
+	raw, err := parseReader(r, newParseConfig(opts))
+	if err != nil {
+		return Value{}, err
+	}
+
+	return Value{data: raw, exists: true}, nil
+}
+
+// ParseObjectReader reads a json object from r. See ParseReader.
+func ParseObjectReader(r io.Reader, opts ...ParseOption) (JsonObject, error) {
+	raw, err := parseReader(r, newParseConfig(opts))
+	if err != nil {
+		return nil, err
+	}
+
+	return toObject(raw)
+}
+
+// ParseListReader reads a json list from r. See ParseReader.
+func ParseListReader(r io.Reader, opts ...ParseOption) (JsonList, error) {
+	raw, err := parseReader(r, newParseConfig(opts))
+	if err != nil {
+		return nil, err
+	}
+
+	return toList(raw)
+}
+
+// toObject converts the result of a parse function into a json object. Null results in a nil object.
+func toObject(raw interface{}) (JsonObject, error) {
+	if raw == nil {
+		return nil, nil
+	}
+	if obj, ok := convToObject(raw); ok {
+		return obj, nil
+	}
+	return nil, typeError(KindObject, raw)
+}
+
+// toList converts the result of a parse function into a json list. Null results in an empty list.
+func toList(raw interface{}) (JsonList, error) {
+	if raw == nil {
+		return NewJsonList(nil), nil
+	}
+	if list, ok := raw.([]interface{}); ok {
+		return NewJsonList(list), nil
+	}
+	return nil, typeError(KindArray, raw)
+}
+
+// parseBytes parses a complete json document.
+func parseBytes(data []byte, config parseConfig) (interface{}, error) {
+	if config.maxBytes > 0 && int64(len(data)) > config.maxBytes {
+		return nil, &LimitError{Limit: LimitBytes, Max: config.maxBytes, Offset: config.maxBytes}
+	}
+
+	if !config.useNumber && !config.needsTokenizer() {
+		var raw interface{}
+		if err := json.Unmarshal(data, &raw); err != nil {
+			return nil, syntaxError(err, int64(len(data)), newlineOffsets(data))
+		}
+		return raw, nil
+	}
+
+	config.maxBytes = 0
+	config.disallowTrailing = true
+	return parseReader(bytes.NewReader(data), config)
+}
+
+// parseReader reads a json value from r. Syntax errors are returned as *Error with the position of the error.
+func parseReader(r io.Reader, config parseConfig) (interface{}, error) {
+	if config.maxBytes > 0 {
+		r = &limitedReader{r: r, remaining: config.maxBytes, max: config.maxBytes}
+	}
+	if config.maxStringLength > 0 {
+		r = &stringLimitReader{r: r, max: int64(config.maxStringLength)}
+	}
+	pos := &positionReader{r: r}
+	dec := newDecoder(pos, config)
+
+	raw, err := decodeNext(dec, pos, config)
+	if err != nil {
+		return nil, err
+	}
+
+	if config.disallowTrailing {
+		offset := dec.InputOffset()
+		if _, err := dec.Token(); err != io.EOF {
+			if err == nil {
+				err = ErrTrailingData
+			}
+			return nil, syntaxError(err, offset+1, pos.newlines)
+		}
+	}
+
+	return raw, nil
+}
+
+func newDecoder(r io.Reader, config parseConfig) *json.Decoder {
+	dec := json.NewDecoder(r)
+	if config.useNumber {
+		dec.UseNumber()
+	}
+	return dec
+}
+
+// decodeNext reads the next json value from dec, which reads from pos.
+func decodeNext(dec *json.Decoder, pos *positionReader, config parseConfig) (interface{}, error) {
+	var raw interface{}
+	var err error
+	if config.needsTokenizer() {
+		d := treeDecoder{dec: dec, config: config}
+		raw, err = d.value()
+	} else {
+		err = dec.Decode(&raw)
+	}
+	if err == io.EOF {
+		err = io.ErrUnexpectedEOF
+	}
+	if e, ok := err.(*Error); ok && e.Kind == ErrorKindDuplicateKey {
+		e.Line, e.Column = position(e.Offset, pos.newlines)
+		return nil, e
+	}
+	if err != nil {
+		return nil, syntaxError(err, pos.offset, pos.newlines)
+	}
+
+	return raw, nil
+}
+
+// limitedReader fails with a *LimitError, when more than max bytes are read.
+type limitedReader struct {
+	r         io.Reader
+	remaining int64
+	max       int64
+}
+
+func (l *limitedReader) Read(p []byte) (int, error) {
+	if l.remaining <= 0 {
+		// Only fail, when there is really more data.
+		var b [1]byte
+		n, err := l.r.Read(b[:])
+		if n > 0 {
+			return 0, &LimitError{Limit: LimitBytes, Max: l.max, Offset: l.max}
+		}
+		return 0, err
+	}
+
+	if int64(len(p)) > l.remaining {
+		p = p[:l.remaining]
+	}
+	n, err := l.r.Read(p)
+	l.remaining -= int64(n)
+	return n, err
+}
+
+// stringLimitReader fails with a *LimitError, while a string is read, which is too long for MaxStringLength. This way,
+// encoding/json doesn't buffer huge strings completely. A single byte takes up to six bytes in the input, e.g.
+// \u0041, so only strings longer than six times the limit are rejected here. The treeDecoder checks the exact length
+// after unescaping.
+type stringLimitReader struct {
+	r        io.Reader
+	max      int64
+	offset   int64
+	inString bool
+	escaped  bool
+	length   int64
+	// err is returned by all further calls, because encoding/json doesn't always keep the error of a read.
+	err error
+}
+
+func (s *stringLimitReader) Read(p []byte) (int, error) {
+	if s.err != nil {
+		return 0, s.err
+	}
+
+	n, err := s.r.Read(p)
+	for i, c := range p[:n] {
+		switch {
+		case !s.inString:
+			s.inString = c == '"'
+			s.length = 0
+			continue
+		case s.escaped:
+			s.escaped = false
+		case c == '\\':
+			s.escaped = true
+		case c == '"':
+			s.inString = false
+			continue
+		}
+
+		s.length++
+		if s.length > 6*s.max {
+			s.err = &LimitError{Limit: LimitStringLength, Max: s.max, Offset: s.offset + int64(i)}
+			return i, s.err
+		}
+	}
+	s.offset += int64(n)
+	return n, err
+}
+
+// treeDecoder builds a json tree token by token, so it can check the limits and duplicate keys while reading.
+type treeDecoder struct {
+	dec    *json.Decoder
+	config parseConfig
+	depth  int
+	// path contains the keys and indices of the values being read. It is only maintained for DuplicateKeysError.
+	path []interface{}
+}
+
+func (d *treeDecoder) value() (interface{}, error) {
+	tok, err := d.dec.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	switch t := tok.(type) {
+	case json.Delim:
+		switch t {
+		case '{':
+			return d.object()
+		case '[':
+			return d.list()
+		}
+		return nil, fmt.Errorf("dynjson: unexpected %q at offset %d", rune(t), d.dec.InputOffset())
+	case string:
+		if err := d.checkString(t); err != nil {
+			return nil, err
+		}
+	}
+
+	return tok, nil
+}
+
+func (d *treeDecoder) enter() error {
+	d.depth++
+	if d.config.maxDepth > 0 && d.depth > d.config.maxDepth {
+		return d.limitError(LimitDepth, int64(d.config.maxDepth))
+	}
+	return nil
+}
+
+func (d *treeDecoder) checkString(s string) error {
+	if d.config.maxStringLength > 0 && len(s) > d.config.maxStringLength {
+		return d.limitError(LimitStringLength, int64(d.config.maxStringLength))
+	}
+	return nil
+}
+
+func (d *treeDecoder) limitError(limit Limit, max int64) error {
+	return &LimitError{Limit: limit, Max: max, Offset: d.dec.InputOffset()}
+}
+
+func (d *treeDecoder) object() (interface{}, error) {
+	if err := d.enter(); err != nil {
+		return nil, err
+	}
+
+	obj := map[string]interface{}{}
+	var keys []string
+	var collected map[string]bool
+	for d.dec.More() {
+		tok, err := d.dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		key := tok.(string)
+		if err := d.checkString(key); err != nil {
+			return nil, err
+		}
+		_, exists := obj[key]
+
+		if d.config.maxKeys > 0 && len(obj) >= d.config.maxKeys && !exists {
+			return nil, d.limitError(LimitKeys, int64(d.config.maxKeys))
+		}
+		if exists && d.config.duplicateKeys == DuplicateKeysError {
+			return nil, d.duplicateError(key)
+		}
+
+		d.push(key)
+		val, err := d.value()
+		if err != nil {
+			return nil, err
+		}
+		d.pop()
+
+		switch {
+		case !exists:
+			if d.config.preserveOrder {
+				keys = append(keys, key)
+			}
+			obj[key] = val
+		case d.config.duplicateKeys == DuplicateKeysKeepFirst:
+		case d.config.duplicateKeys == DuplicateKeysCollect:
+			if collected == nil {
+				collected = map[string]bool{}
+			}
+			if !collected[key] {
+				collected[key] = true
+				obj[key] = []interface{}{obj[key]}
+			}
+			obj[key] = append(obj[key].([]interface{}), val)
+		default:
+			obj[key] = val
+		}
+	}
+
+	// closing '}'
+	if _, err := d.dec.Token(); err != nil {
+		return nil, err
+	}
+
+	d.depth--
+	if d.config.preserveOrder {
+		return &OrderedObject{keys: keys, values: obj}, nil
+	}
+	return obj, nil
+}
+
+func (d *treeDecoder) list() (interface{}, error) {
+	if err := d.enter(); err != nil {
+		return nil, err
+	}
+
+	list := make([]interface{}, 0)
+	for d.dec.More() {
+		d.push(len(list))
+		val, err := d.value()
+		if err != nil {
+			return nil, err
+		}
+		d.pop()
+		list = append(list, val)
+	}
+
+	// closing ']'
+	if _, err := d.dec.Token(); err != nil {
+		return nil, err
+	}
+
+	d.depth--
+	return list, nil
+}
+
+// push adds a key or an index to the path.
+func (d *treeDecoder) push(segment interface{}) {
+	if d.config.duplicateKeys == DuplicateKeysError {
+		d.path = append(d.path, segment)
+	}
+}
+
+func (d *treeDecoder) pop() {
+	if d.config.duplicateKeys == DuplicateKeysError {
+		d.path = d.path[:len(d.path)-1]
+	}
+}
+
+// duplicateError returns an *Error with the path of the duplicate key. Line and column are added by parseReader.
+func (d *treeDecoder) duplicateError(key string) error {
+	segments := make([]string, 0, len(d.path)+1)
+	for _, segment := range d.path {
+		segments = append(segments, fmt.Sprint(segment))
+	}
+	segments = append(segments, key)
+
+	return &Error{Kind: ErrorKindDuplicateKey, Path: FormatPointer(segments...), Offset: d.dec.InputOffset()}
+}
@@ -0,0 +1,156 @@
+package dynjson_test
+
+import (
+	"errors"
+	"io"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/go-schild/dynjson"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseReader(t *testing.T) {
+	v, err := dynjson.ParseReader(strings.NewReader(`{"a": [1, 2]}`))
+	assert.Nil(t, err)
+	assert.Equal(t, dynjson.KindObject, v.Kind())
+
+	v, err = dynjson.ParseReader(strings.NewReader(`"abc"`))
+	assert.Nil(t, err)
+	assert.Equal(t, "abc", v.String())
+
+	_, err = dynjson.ParseReader(strings.NewReader(``))
+	assert.True(t, errors.Is(err, io.ErrUnexpectedEOF))
+	assert.True(t, errors.Is(err, dynjson.ErrSyntax))
+
+	_, err = dynjson.ParseReader(strings.NewReader(`{"a": `))
+	assert.NotNil(t, err)
+}
+
+func TestParseObjectReader(t *testing.T) {
+	j, err := dynjson.ParseObjectReader(strings.NewReader(`{"a": {"b": 9007199254740993}}`), dynjson.UseNumber())
+	assert.Nil(t, err)
+	assert.Equal(t, int64(9007199254740993), j.Object("a").Int64("b"))
+
+	_, err = dynjson.ParseObjectReader(strings.NewReader(`[1]`))
+	assert.NotNil(t, err)
+}
+
+func TestParseListReader(t *testing.T) {
+	j, err := dynjson.ParseListReader(strings.NewReader(`[1, {"a": 2}]`))
+	assert.Nil(t, err)
+	assert.Equal(t, 2, j[1].Object().Int("a"))
+
+	_, err = dynjson.ParseListReader(strings.NewReader(`{"a": 1}`))
+	assert.NotNil(t, err)
+}
+
+func TestParseReader_TrailingData(t *testing.T) {
+	r := strings.NewReader(`{"a": 1} {"b": 2}`)
+
+	j, err := dynjson.ParseObjectReader(r)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, j.Int("a"))
+
+	_, err = dynjson.ParseObjectReader(strings.NewReader(`{"a": 1} {"b": 2}`), dynjson.DisallowTrailingData())
+	assert.True(t, errors.Is(err, dynjson.ErrTrailingData))
+
+	_, err = dynjson.ParseObjectReader(strings.NewReader(`{"a": 1} `+"\n"), dynjson.DisallowTrailingData())
+	assert.Nil(t, err)
+
+	_, err = dynjson.ParseObject(`{"a": 1} {"b": 2}`, dynjson.MaxDepth(5))
+	assert.NotNil(t, err)
+}
+
+func limitOf(err error) dynjson.Limit {
+	var lErr *dynjson.LimitError
+	if errors.As(err, &lErr) {
+		return lErr.Limit
+	}
+	return 0
+}
+
+func TestParseReader_Limits(t *testing.T) {
+	testData := []struct {
+		data  string
+		opt   dynjson.ParseOption
+		limit dynjson.Limit
+	}{
+		{`{"a": "0123456789"}`, dynjson.MaxBytes(20), 0},
+		{`{"a": "0123456789"}`, dynjson.MaxBytes(10), dynjson.LimitBytes},
+		{`[[[1]]]`, dynjson.MaxDepth(3), 0},
+		{`[[[[1]]]]`, dynjson.MaxDepth(3), dynjson.LimitDepth},
+		{`{"a": {"b": {"c": {}}}}`, dynjson.MaxDepth(3), dynjson.LimitDepth},
+		{`["12345"]`, dynjson.MaxStringLength(5), 0},
+		{`["123456"]`, dynjson.MaxStringLength(5), dynjson.LimitStringLength},
+		{`{"123456": 1}`, dynjson.MaxStringLength(5), dynjson.LimitStringLength},
+		{`["\u0041\u0042\u0043\u0044\u0045"]`, dynjson.MaxStringLength(5), 0},
+		{`["\u0041\u0042\u0043\u0044\u0045\u0046"]`, dynjson.MaxStringLength(5), dynjson.LimitStringLength},
+		{`{"a": 1, "b": 2}`, dynjson.MaxKeys(2), 0},
+		{`{"a": 1, "b": 2, "c": 3}`, dynjson.MaxKeys(2), dynjson.LimitKeys},
+		{`[{"a": 1, "b": 2}, {"c": 3, "d": 4}]`, dynjson.MaxKeys(2), 0},
+	}
+
+	for _, data := range testData {
+		_, err := dynjson.ParseReader(strings.NewReader(data.data), data.opt)
+		assert.Equal(t, data.limit, limitOf(err), data.data)
+
+		_, err = dynjson.Parse([]byte(data.data), data.opt)
+		assert.Equal(t, data.limit, limitOf(err), data.data)
+	}
+}
+
+func TestParseReader_MaxStringLength(t *testing.T) {
+	r := strings.NewReader(`{"a": "` + strings.Repeat("x", 16<<20) + `"}`)
+
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	_, err := dynjson.ParseReader(r, dynjson.MaxStringLength(100))
+	runtime.ReadMemStats(&after)
+
+	assert.Equal(t, dynjson.LimitStringLength, limitOf(err))
+	assert.Less(t, after.TotalAlloc-before.TotalAlloc, uint64(1<<20))
+}
+
+func TestLimitError_Error(t *testing.T) {
+	_, err := dynjson.ParseReader(strings.NewReader(`[[[[1]]]]`), dynjson.MaxDepth(3))
+	assert.EqualError(t, err, "dynjson: maximum nesting depth of 3 exceeded at offset 4")
+}
+
+func TestParse_DuplicateKeys(t *testing.T) {
+	data := `{"a": 1, "b": {"c": true}, "a": 2, "a": [3]}`
+
+	j, err := dynjson.ParseObject(data)
+	assert.Nil(t, err)
+	assert.Equal(t, `{"a":[3],"b":{"c":true}}`, j.ToString())
+
+	j, err = dynjson.ParseObject(data, dynjson.DuplicateKeys(dynjson.DuplicateKeysKeepLast))
+	assert.Nil(t, err)
+	assert.Equal(t, `{"a":[3],"b":{"c":true}}`, j.ToString())
+
+	j, err = dynjson.ParseObject(data, dynjson.DuplicateKeys(dynjson.DuplicateKeysKeepFirst))
+	assert.Nil(t, err)
+	assert.Equal(t, `{"a":1,"b":{"c":true}}`, j.ToString())
+
+	j, err = dynjson.ParseObject(data, dynjson.DuplicateKeys(dynjson.DuplicateKeysCollect))
+	assert.Nil(t, err)
+	assert.Equal(t, `{"a":[1,2,[3]],"b":{"c":true}}`, j.ToString())
+
+	_, err = dynjson.ParseObject(data, dynjson.DuplicateKeys(dynjson.DuplicateKeysError))
+	assert.True(t, errors.Is(err, dynjson.ErrDuplicateKey))
+}
+
+func TestParse_DuplicateKeysError(t *testing.T) {
+	opt := dynjson.DuplicateKeys(dynjson.DuplicateKeysError)
+
+	_, err := dynjson.ParseList("[{}, {\"a\": {\"x/y\": 1,\n \"x/y\": 2}}]", opt)
+	assert.EqualError(t, err, `dynjson: "/1/a/x~1y": duplicate key at line 2, column 6`)
+
+	_, err = dynjson.ParseReader(strings.NewReader(`{"a": [{"b": 1}, {"b": 2}], "c": {"b": 3}}`), opt)
+	assert.Nil(t, err)
+
+	o, err := dynjson.ParseOrderedObject(`{"b": 1, "a": 2, "b": 3}`, dynjson.DuplicateKeys(dynjson.DuplicateKeysCollect))
+	assert.Nil(t, err)
+	assert.Equal(t, `{"b":[1,3],"a":2}`, o.ToString())
+}
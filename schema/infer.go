@@ -0,0 +1,378 @@
+package schema
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/go-schild/dynjson"
+)
+
+// InferOption changes how an Inferrer generalizes its samples.
+type InferOption func(*Inferrer)
+
+// MaxEnumValues sets how many different values a string field may have to become an enum. The default is 5,
+// 0 disables enums. A field only becomes an enum, when at least one of its values occurs more than once.
+func MaxEnumValues(n int) InferOption {
+	return func(i *Inferrer) {
+		i.maxEnum = n
+	}
+}
+
+// Inferrer infers a JSON Schema or Go types from sample documents.
+//
+// All samples are unified: fields which are missing in some objects become optional, fields which are null in some
+// objects become nullable, integers and fractional numbers become numbers and strings with only a few different
+// values become enums.
+type Inferrer struct {
+	root    *shape
+	maxEnum int
+}
+
+// NewInferrer creates an Inferrer without samples.
+func NewInferrer(opts ...InferOption) *Inferrer {
+	i := &Inferrer{root: &shape{}, maxEnum: 5}
+	for _, opt := range opts {
+		opt(i)
+	}
+	return i
+}
+
+// Infer creates an Inferrer with default options and adds all samples.
+func Infer(samples ...interface{}) (*Inferrer, error) {
+	i := NewInferrer()
+	for _, sample := range samples {
+		if err := i.Add(sample); err != nil {
+			return nil, err
+		}
+	}
+	return i, nil
+}
+
+// Add adds a sample, e.g. a JsonObject, a JsonList or a Value.
+func (i *Inferrer) Add(sample interface{}) error {
+	return i.root.add(dynjson.NewValue(sample), "")
+}
+
+// shape collects what was seen at one location of the samples.
+type shape struct {
+	count    int
+	nulls    int
+	bools    int
+	integers int
+	numbers  int
+	strings  int
+	objects  int
+	arrays   int
+
+	values map[string]int
+	fields map[string]*shape
+	items  *shape
+}
+
+func (s *shape) add(v dynjson.Value, path string) error {
+	s.count++
+
+	switch v.Kind() {
+	case dynjson.KindNull:
+		s.nulls++
+	case dynjson.KindBool:
+		s.bools++
+	case dynjson.KindNumber:
+		if f := v.Float64(); f == math.Trunc(f) {
+			s.integers++
+		} else {
+			s.numbers++
+		}
+	case dynjson.KindString:
+		s.strings++
+		if s.values == nil {
+			s.values = map[string]int{}
+		}
+		s.values[v.String()]++
+	case dynjson.KindObject:
+		s.objects++
+		if s.fields == nil {
+			s.fields = map[string]*shape{}
+		}
+		obj := v.Object()
+		for _, key := range sortedKeys(obj) {
+			field, ok := s.fields[key]
+			if !ok {
+				field = &shape{}
+				s.fields[key] = field
+			}
+			if err := field.add(obj.Value(key), path+dynjson.FormatPointer(key)); err != nil {
+				return err
+			}
+		}
+	case dynjson.KindArray:
+		s.arrays++
+		if s.items == nil {
+			s.items = &shape{}
+		}
+		for k, item := range v.List() {
+			if err := s.items.add(item.Value(), fmt.Sprintf("%s/%d", path, k)); err != nil {
+				return err
+			}
+		}
+	default:
+		return fmt.Errorf("schema: %q: can't infer a type from %T", path, v.Interface())
+	}
+
+	return nil
+}
+
+// types returns the JSON Schema types seen, without null.
+func (s *shape) types() []string {
+	var result []string
+	if s.objects > 0 {
+		result = append(result, "object")
+	}
+	if s.arrays > 0 {
+		result = append(result, "array")
+	}
+	if s.strings > 0 {
+		result = append(result, "string")
+	}
+	if s.numbers > 0 {
+		result = append(result, "number")
+	} else if s.integers > 0 {
+		result = append(result, "integer")
+	}
+	if s.bools > 0 {
+		result = append(result, "boolean")
+	}
+	return result
+}
+
+func (s *shape) nullable() bool {
+	return s.nulls > 0
+}
+
+// enum returns the sorted string values, when they are few enough and at least one of them repeats.
+func (s *shape) enum(max int) ([]string, bool) {
+	if s.strings == 0 || s.strings != s.count-s.nulls || len(s.values) > max || len(s.values) == s.strings {
+		return nil, false
+	}
+
+	result := make([]string, 0, len(s.values))
+	for value := range s.values {
+		result = append(result, value)
+	}
+	sort.Strings(result)
+	return result, true
+}
+
+// required returns the sorted names of the fields, which are part of every object.
+func (s *shape) required() []string {
+	var result []string
+	for name, field := range s.fields {
+		if field.count == s.objects {
+			result = append(result, name)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
+
+// Schema returns a JSON Schema (draft 2020-12), which all samples match.
+func (i *Inferrer) Schema() dynjson.JsonObject {
+	result := i.schema(i.root)
+	result["$schema"] = "https://json-schema.org/draft/2020-12/schema"
+	return result
+}
+
+func (i *Inferrer) schema(s *shape) dynjson.JsonObject {
+	result := dynjson.JsonObject{}
+
+	types := s.types()
+	if s.nullable() {
+		types = append(types, "null")
+	}
+	switch len(types) {
+	case 0:
+		return result
+	case 1:
+		result["type"] = types[0]
+	default:
+		list := make([]interface{}, len(types))
+		for k, t := range types {
+			list[k] = t
+		}
+		result["type"] = dynjson.NewJsonList(list)
+	}
+
+	if values, ok := s.enum(i.maxEnum); ok {
+		list := make([]interface{}, 0, len(values)+1)
+		for _, value := range values {
+			list = append(list, value)
+		}
+		if s.nullable() {
+			list = append(list, nil)
+		}
+		result["enum"] = dynjson.NewJsonList(list)
+	}
+
+	if s.objects > 0 {
+		properties := dynjson.JsonObject{}
+		for name, field := range s.fields {
+			properties[name] = i.schema(field)
+		}
+		result["properties"] = properties
+
+		if required := s.required(); len(required) > 0 {
+			list := make([]interface{}, len(required))
+			for k, name := range required {
+				list[k] = name
+			}
+			result["required"] = dynjson.NewJsonList(list)
+		}
+	}
+
+	if s.arrays > 0 && s.items.count > 0 {
+		result["items"] = i.schema(s.items)
+	}
+
+	return result
+}
+
+// GoTypes returns Go type declarations for the samples. The type of the samples is called name, the types of nested
+// objects are named after their parent type and their field, e.g. PersonAddress.
+// Optional and nullable fields become pointers and get the omitempty option, fields with mixed types become
+// interface{}. The result doesn't contain a package clause.
+func (i *Inferrer) GoTypes(name string) ([]byte, error) {
+	g := goGenerator{inferrer: i}
+	g.declare(name, i.root)
+
+	source, err := format.Source(append(bytes.TrimRight(g.buf.Bytes(), "\n"), '\n'))
+	if err != nil {
+		return nil, fmt.Errorf("schema: invalid Go source: %v", err)
+	}
+	return source, nil
+}
+
+type goGenerator struct {
+	inferrer *Inferrer
+	buf      bytes.Buffer
+}
+
+func (g *goGenerator) declare(name string, s *shape) {
+	if types := s.types(); len(types) == 1 && types[0] == "object" {
+		g.buf.WriteString(g.declareStruct(name, s))
+		return
+	}
+
+	// Nested types are declared while building the type, they follow the declaration.
+	nested := goGenerator{inferrer: g.inferrer}
+	fmt.Fprintf(&g.buf, "type %s %s\n\n", name, nested.goType(name, s, false))
+	g.buf.Write(nested.buf.Bytes())
+}
+
+func (g *goGenerator) goType(name string, s *shape, optional bool) string {
+	types := s.types()
+	if len(types) != 1 {
+		return "interface{}"
+	}
+
+	pointer := ""
+	if optional || s.nullable() {
+		pointer = "*"
+	}
+
+	switch types[0] {
+	case "object":
+		g.buf.WriteString(g.declareStruct(name, s))
+		return pointer + name
+	case "array":
+		if s.items.count == 0 {
+			return "[]interface{}"
+		}
+		return "[]" + g.goType(name+"Item", s.items, false)
+	case "string":
+		return pointer + "string"
+	case "number":
+		return pointer + "float64"
+	case "integer":
+		return pointer + "int64"
+	}
+	return pointer + "bool"
+}
+
+// declareStruct returns the declaration of a struct type and declares the types of its fields.
+func (g *goGenerator) declareStruct(name string, s *shape) string {
+	names := make([]string, 0, len(s.fields))
+	for field := range s.fields {
+		names = append(names, field)
+	}
+	sort.Strings(names)
+
+	var fields bytes.Buffer
+	nested := goGenerator{inferrer: g.inferrer}
+	used := map[string]bool{}
+	for _, field := range names {
+		shape := s.fields[field]
+		optional := shape.count < s.objects
+
+		goName := goFieldName(field)
+		for k := 2; used[goName]; k++ {
+			goName = fmt.Sprintf("%s%d", goFieldName(field), k)
+		}
+		used[goName] = true
+
+		tag := field
+		if optional || shape.nullable() {
+			tag += ",omitempty"
+		}
+
+		comment := ""
+		if values, ok := shape.enum(g.inferrer.maxEnum); ok {
+			comment = " // one of " + strings.Join(quoteAll(values), ", ")
+		}
+
+		fmt.Fprintf(&fields, "\t%s %s `json:%q`%s\n", goName, nested.goType(name+goName, shape, optional), tag, comment)
+	}
+
+	return fmt.Sprintf("type %s struct {\n%s}\n\n", name, fields.String()) + nested.buf.String()
+}
+
+// goFieldName converts a json key like "user_id" into an exported Go name like "UserID".
+func goFieldName(key string) string {
+	parts := strings.FieldsFunc(key, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	var sb strings.Builder
+	for _, part := range parts {
+		if initialisms[strings.ToUpper(part)] {
+			sb.WriteString(strings.ToUpper(part))
+			continue
+		}
+		runes := []rune(part)
+		runes[0] = unicode.ToUpper(runes[0])
+		sb.WriteString(string(runes))
+	}
+
+	name := sb.String()
+	if name == "" || !unicode.IsLetter([]rune(name)[0]) {
+		name = "Field" + name
+	}
+	return name
+}
+
+var initialisms = map[string]bool{
+	"API": true, "HTML": true, "HTTP": true, "HTTPS": true, "ID": true, "IP": true, "JSON": true,
+	"SQL": true, "URI": true, "URL": true, "UUID": true, "XML": true,
+}
+
+func quoteAll(values []string) []string {
+	result := make([]string, len(values))
+	for i, value := range values {
+		result[i] = fmt.Sprintf("%q", value)
+	}
+	return result
+}
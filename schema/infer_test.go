@@ -0,0 +1,100 @@
+package schema_test
+
+import (
+	"testing"
+
+	"github.com/go-schild/dynjson"
+	"github.com/go-schild/dynjson/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+func inferSamples(t *testing.T, opts []schema.InferOption, samples ...string) *schema.Inferrer {
+	i := schema.NewInferrer(opts...)
+	for _, sample := range samples {
+		v, err := dynjson.Parse([]byte(sample))
+		assert.Nil(t, err)
+		assert.Nil(t, i.Add(v))
+	}
+	return i
+}
+
+var personSamples = []string{
+	`{"id": 1, "name": "Bob", "score": 1, "status": "active", "address": {"street": "Main"}, "tags": ["a"], "nick": null}`,
+	`{"id": 2, "name": "Alice", "score": 2.5, "status": "inactive", "address": {"street": "High", "zip": "12345"}, "tags": []}`,
+	`{"id": 3, "name": "Carol", "score": 3, "status": "active", "tags": ["b", "c"], "nick": "cc", "user_id": "x"}`,
+}
+
+func TestInferrer_Schema(t *testing.T) {
+	i := inferSamples(t, nil, personSamples...)
+
+	expected, _ := dynjson.ParseObject(`{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"type": "object",
+		"properties": {
+			"id": {"type": "integer"},
+			"name": {"type": "string"},
+			"score": {"type": "number"},
+			"status": {"type": "string", "enum": ["active", "inactive"]},
+			"address": {
+				"type": "object",
+				"properties": {"street": {"type": "string"}, "zip": {"type": "string"}},
+				"required": ["street"]
+			},
+			"tags": {"type": "array", "items": {"type": "string"}},
+			"nick": {"type": ["string", "null"]},
+			"user_id": {"type": "string"}
+		},
+		"required": ["id", "name", "score", "status", "tags"]
+	}`)
+	assert.Equal(t, expected.ToString(), i.Schema().ToString())
+
+	// The inferred schema accepts all samples.
+	s, err := schema.Compile(i.Schema())
+	assert.Nil(t, err)
+	for _, sample := range personSamples {
+		j, _ := dynjson.ParseObject(sample)
+		assert.Nil(t, s.Validate(j))
+	}
+
+	noEnums := inferSamples(t, []schema.InferOption{schema.MaxEnumValues(0)}, personSamples...)
+	assert.False(t, noEnums.Schema().Object("properties").Object("status").Has("enum"))
+}
+
+func TestInferrer_GoTypes(t *testing.T) {
+	i := inferSamples(t, nil, personSamples...)
+
+	source, err := i.GoTypes("Person")
+	assert.Nil(t, err)
+	assert.Equal(t, "type Person struct {\n"+
+		"\tAddress *PersonAddress `json:\"address,omitempty\"`\n"+
+		"\tID      int64          `json:\"id\"`\n"+
+		"\tName    string         `json:\"name\"`\n"+
+		"\tNick    *string        `json:\"nick,omitempty\"`\n"+
+		"\tScore   float64        `json:\"score\"`\n"+
+		"\tStatus  string         `json:\"status\"` // one of \"active\", \"inactive\"\n"+
+		"\tTags    []string       `json:\"tags\"`\n"+
+		"\tUserID  *string        `json:\"user_id,omitempty\"`\n"+
+		"}\n\n"+
+		"type PersonAddress struct {\n"+
+		"\tStreet string  `json:\"street\"`\n"+
+		"\tZip    *string `json:\"zip,omitempty\"`\n"+
+		"}\n", string(source))
+}
+
+func TestInferrer_Lists(t *testing.T) {
+	i := inferSamples(t, nil, `[{"a": 1, "b": true}, {"a": "x"}]`, `[]`)
+
+	source, err := i.GoTypes("Items")
+	assert.Nil(t, err)
+	assert.Equal(t, "type Items []ItemsItem\n\n"+
+		"type ItemsItem struct {\n"+
+		"\tA interface{} `json:\"a\"`\n"+
+		"\tB *bool       `json:\"b,omitempty\"`\n"+
+		"}\n", string(source))
+
+	assert.Equal(t, `{"$schema":"https://json-schema.org/draft/2020-12/schema","items":{"properties":{"a":{"type":["string","integer"]},"b":{"type":"boolean"}},"required":["a"],"type":"object"},"type":"array"}`,
+		i.Schema().ToString())
+
+	_, err = schema.Infer(dynjson.JsonObject{"c": make(chan int)})
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,527 @@
+// Package schema validates json documents against a JSON Schema (draft 2020-12).
+//
+// Supported keywords are type, enum, const, properties, additionalProperties, required, prefixItems, items,
+// minimum, maximum, exclusiveMinimum, exclusiveMaximum, multipleOf, minLength, maxLength, pattern, format,
+// minItems, maxItems, uniqueItems, $ref, $defs, allOf, anyOf, oneOf, not and if/then/else.
+// References are resolved locally: inside the same schema or to other schemas added to a Registry.
+// Unknown keywords are ignored.
+package schema
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/go-schild/dynjson"
+)
+
+// Registry holds schemas by their URI, so they can reference each other with $ref.
+type Registry struct {
+	documents map[string]dynjson.Value
+	compiled  map[string]*schema
+}
+
+// NewRegistry creates an empty registry.
+func NewRegistry() *Registry {
+	return &Registry{documents: map[string]dynjson.Value{}, compiled: map[string]*schema{}}
+}
+
+// Add adds a schema document under the given URI, e.g. "https://example.com/address.json".
+// When the schema has an "$id", it is registered under that URI, too.
+func (r *Registry) Add(uri string, doc dynjson.JsonObject) error {
+	return r.add(uri, dynjson.NewValue(doc))
+}
+
+func (r *Registry) add(uri string, doc dynjson.Value) error {
+	uri = stripFragment(uri)
+	if _, exists := r.documents[uri]; exists {
+		return fmt.Errorf("schema: %q is already registered", uri)
+	}
+	r.documents[uri] = doc
+
+	if id, ok := doc.Object().StringOk("$id"); ok && stripFragment(id) != uri {
+		r.documents[stripFragment(id)] = doc
+	}
+	return nil
+}
+
+// Compile compiles the schema registered under the URI. All references have to be resolvable.
+func (r *Registry) Compile(uri string) (*Schema, error) {
+	c := compiler{registry: r, compiled: map[string]*schema{}}
+	root, err := c.resolve(uri, "")
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.finish(); err != nil {
+		return nil, err
+	}
+	for location, s := range c.compiled {
+		r.compiled[location] = s
+	}
+	return &Schema{root: root}, nil
+}
+
+// Compile compiles a single schema, which may only reference itself.
+func Compile(doc dynjson.JsonObject) (*Schema, error) {
+	r := NewRegistry()
+	if err := r.Add("", doc); err != nil {
+		return nil, err
+	}
+	return r.Compile("")
+}
+
+// MustCompile works like Compile, but panics when the schema is invalid.
+func MustCompile(doc dynjson.JsonObject) *Schema {
+	s, err := Compile(doc)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+// Schema is a compiled JSON Schema. It is safe for concurrent use.
+type Schema struct {
+	root *schema
+}
+
+// schema is a compiled schema object or boolean schema.
+type schema struct {
+	// location is the URI of the schema document followed by a JSON pointer to the schema, e.g. "#/$defs/a".
+	location string
+	// always is set for the boolean schemas true and false.
+	always *bool
+
+	types []string
+
+	enum     []dynjson.Value
+	hasEnum  bool
+	constVal dynjson.Value
+
+	properties           map[string]*schema
+	additionalProperties *schema
+	required             []string
+
+	prefixItems []*schema
+	items       *schema
+
+	minimum          *float64
+	maximum          *float64
+	exclusiveMinimum *float64
+	exclusiveMaximum *float64
+	multipleOf       *float64
+
+	minLength *int
+	maxLength *int
+	pattern   *regexp.Regexp
+	format    string
+
+	minItems    *int
+	maxItems    *int
+	uniqueItems bool
+
+	ref   *schema
+	allOf []*schema
+	anyOf []*schema
+	oneOf []*schema
+	not   *schema
+
+	ifSchema   *schema
+	thenSchema *schema
+	elseSchema *schema
+}
+
+// compiler compiles schemas and resolves their references. Each schema is compiled only once, so recursive schemas
+// are possible.
+type compiler struct {
+	registry *Registry
+	// compiled holds the schemas compiled by this compiler. They are added to the registry, when all of them
+	// compiled successfully.
+	compiled map[string]*schema
+	pending  []pendingRef
+}
+
+type pendingRef struct {
+	from *schema
+	base string
+	ref  string
+}
+
+// resolve returns the compiled schema the reference points to. Base is the URI of the referencing document.
+func (c *compiler) resolve(ref, base string) (*schema, error) {
+	uri, fragment := ref, ""
+	if i := strings.IndexByte(ref, '#'); i >= 0 {
+		uri, fragment = ref[:i], ref[i+1:]
+	}
+
+	if uri == "" {
+		uri = base
+	} else if base != "" {
+		baseURL, err := url.Parse(base)
+		if err != nil {
+			return nil, fmt.Errorf("schema: invalid base URI %q: %v", base, err)
+		}
+		refURL, err := url.Parse(uri)
+		if err != nil {
+			return nil, fmt.Errorf("schema: invalid reference %q: %v", ref, err)
+		}
+		uri = baseURL.ResolveReference(refURL).String()
+	}
+
+	ptr, err := url.PathUnescape(fragment)
+	if err != nil {
+		return nil, fmt.Errorf("schema: invalid reference %q: %v", ref, err)
+	}
+	if ptr != "" && ptr[0] != '/' {
+		return nil, fmt.Errorf("schema: reference %q: anchors are not supported", ref)
+	}
+
+	if s, ok := c.lookup(uri + "#" + ptr); ok {
+		return s, nil
+	}
+
+	doc, ok := c.registry.documents[uri]
+	if !ok {
+		return nil, fmt.Errorf("schema: reference %q: unknown schema %q", ref, uri)
+	}
+	val, err := doc.Pointer(ptr)
+	if err != nil {
+		return nil, fmt.Errorf("schema: reference %q: %v", ref, err)
+	}
+
+	return c.compile(val, uri, ptr)
+}
+
+// finish resolves the references collected while compiling. Resolving them may compile further schemas.
+func (c *compiler) finish() error {
+	for len(c.pending) > 0 {
+		p := c.pending[0]
+		c.pending = c.pending[1:]
+
+		target, err := c.resolve(p.ref, p.base)
+		if err != nil {
+			return err
+		}
+		p.from.ref = target
+	}
+	return nil
+}
+
+// lookup returns a schema, which was already compiled.
+func (c *compiler) lookup(location string) (*schema, bool) {
+	if s, ok := c.compiled[location]; ok {
+		return s, true
+	}
+	s, ok := c.registry.compiled[location]
+	return s, ok
+}
+
+func (c *compiler) compile(val dynjson.Value, base, ptr string) (*schema, error) {
+	if s, ok := c.lookup(base + "#" + ptr); ok {
+		return s, nil
+	}
+
+	s := &schema{location: base + "#" + ptr}
+	c.compiled[s.location] = s
+
+	if b, ok := val.BoolOk(); ok {
+		s.always = &b
+		return s, nil
+	}
+	obj, ok := val.ObjectOk()
+	if !ok {
+		return nil, c.errorf(s, "", "schema must be an object or a bool, got %s", val.Kind())
+	}
+
+	if err := c.compileGeneral(s, obj, base, ptr); err != nil {
+		return nil, err
+	}
+	if err := c.compileObjectKeywords(s, obj, base, ptr); err != nil {
+		return nil, err
+	}
+	if err := c.compileListKeywords(s, obj, base, ptr); err != nil {
+		return nil, err
+	}
+	if err := c.compileScalarKeywords(s, obj); err != nil {
+		return nil, err
+	}
+	return s, c.compileApplicators(s, obj, base, ptr)
+}
+
+func (c *compiler) compileGeneral(s *schema, obj dynjson.JsonObject, base, ptr string) error {
+	if typ := obj.Value("type"); typ.Exists() {
+		if name, ok := typ.StringOk(); ok {
+			s.types = []string{name}
+		} else if list, ok := typ.ListOk(); ok {
+			for _, item := range list {
+				name, ok := item.StringOk()
+				if !ok {
+					return c.errorf(s, "type", "must be a string or a list of strings")
+				}
+				s.types = append(s.types, name)
+			}
+		} else {
+			return c.errorf(s, "type", "must be a string or a list of strings")
+		}
+		for _, name := range s.types {
+			if !validTypes[name] {
+				return c.errorf(s, "type", "unknown type %q", name)
+			}
+		}
+	}
+
+	if enum := obj.Value("enum"); enum.Exists() {
+		list, ok := enum.ListOk()
+		if !ok {
+			return c.errorf(s, "enum", "must be a list")
+		}
+		s.hasEnum = true
+		for _, item := range list {
+			s.enum = append(s.enum, item.Value())
+		}
+	}
+	s.constVal = obj.Value("const")
+
+	if ref, ok := obj.StringOk("$ref"); ok {
+		c.pending = append(c.pending, pendingRef{from: s, base: base, ref: ref})
+	}
+
+	return nil
+}
+
+func (c *compiler) compileObjectKeywords(s *schema, obj dynjson.JsonObject, base, ptr string) error {
+	if props := obj.Value("properties"); props.Exists() {
+		propsObj, ok := props.ObjectOk()
+		if !ok {
+			return c.errorf(s, "properties", "must be an object")
+		}
+		s.properties = map[string]*schema{}
+		for name := range propsObj {
+			child, err := c.compile(propsObj.Value(name), base, ptr+dynjson.FormatPointer("properties", name))
+			if err != nil {
+				return err
+			}
+			s.properties[name] = child
+		}
+	}
+
+	var err error
+	if s.additionalProperties, err = c.compileChild(obj, "additionalProperties", base, ptr); err != nil {
+		return err
+	}
+
+	if required := obj.Value("required"); required.Exists() {
+		list, ok := required.ListOk()
+		if !ok {
+			return c.errorf(s, "required", "must be a list of strings")
+		}
+		for _, item := range list {
+			name, ok := item.StringOk()
+			if !ok {
+				return c.errorf(s, "required", "must be a list of strings")
+			}
+			s.required = append(s.required, name)
+		}
+	}
+
+	return nil
+}
+
+func (c *compiler) compileListKeywords(s *schema, obj dynjson.JsonObject, base, ptr string) error {
+	var err error
+	if s.prefixItems, err = c.compileChildren(obj, "prefixItems", base, ptr); err != nil {
+		return err
+	}
+	if s.items, err = c.compileChild(obj, "items", base, ptr); err != nil {
+		return err
+	}
+
+	if s.minItems, err = c.intKeyword(s, obj, "minItems"); err != nil {
+		return err
+	}
+	if s.maxItems, err = c.intKeyword(s, obj, "maxItems"); err != nil {
+		return err
+	}
+	if unique := obj.Value("uniqueItems"); unique.Exists() {
+		b, ok := unique.BoolOk()
+		if !ok {
+			return c.errorf(s, "uniqueItems", "must be a bool")
+		}
+		s.uniqueItems = b
+	}
+
+	return nil
+}
+
+func (c *compiler) compileScalarKeywords(s *schema, obj dynjson.JsonObject) error {
+	numbers := []struct {
+		name   string
+		target **float64
+	}{
+		{"minimum", &s.minimum},
+		{"maximum", &s.maximum},
+		{"exclusiveMinimum", &s.exclusiveMinimum},
+		{"exclusiveMaximum", &s.exclusiveMaximum},
+		{"multipleOf", &s.multipleOf},
+	}
+	for _, keyword := range numbers {
+		val := obj.Value(keyword.name)
+		if !val.Exists() {
+			continue
+		}
+		f, ok := val.Float64Ok()
+		if !ok {
+			return c.errorf(s, keyword.name, "must be a number")
+		}
+		*keyword.target = &f
+	}
+	if s.multipleOf != nil && *s.multipleOf <= 0 {
+		return c.errorf(s, "multipleOf", "must be greater than 0")
+	}
+
+	var err error
+	if s.minLength, err = c.intKeyword(s, obj, "minLength"); err != nil {
+		return err
+	}
+	if s.maxLength, err = c.intKeyword(s, obj, "maxLength"); err != nil {
+		return err
+	}
+
+	if pattern := obj.Value("pattern"); pattern.Exists() {
+		expr, ok := pattern.StringOk()
+		if !ok {
+			return c.errorf(s, "pattern", "must be a string")
+		}
+		if s.pattern, err = regexp.Compile(expr); err != nil {
+			return c.errorf(s, "pattern", "%v", err)
+		}
+	}
+
+	if format := obj.Value("format"); format.Exists() {
+		name, ok := format.StringOk()
+		if !ok {
+			return c.errorf(s, "format", "must be a string")
+		}
+		s.format = name
+	}
+
+	return nil
+}
+
+func (c *compiler) compileApplicators(s *schema, obj dynjson.JsonObject, base, ptr string) error {
+	if defs := obj.Value("$defs"); defs.Exists() {
+		defsObj, ok := defs.ObjectOk()
+		if !ok {
+			return c.errorf(s, "$defs", "must be an object")
+		}
+		// Definitions are compiled, so errors in unused definitions are reported, too.
+		for name := range defsObj {
+			if _, err := c.compile(defsObj.Value(name), base, ptr+dynjson.FormatPointer("$defs", name)); err != nil {
+				return err
+			}
+		}
+	}
+
+	var err error
+	if s.allOf, err = c.compileChildren(obj, "allOf", base, ptr); err != nil {
+		return err
+	}
+	if s.anyOf, err = c.compileChildren(obj, "anyOf", base, ptr); err != nil {
+		return err
+	}
+	if s.oneOf, err = c.compileChildren(obj, "oneOf", base, ptr); err != nil {
+		return err
+	}
+	if s.not, err = c.compileChild(obj, "not", base, ptr); err != nil {
+		return err
+	}
+	if s.ifSchema, err = c.compileChild(obj, "if", base, ptr); err != nil {
+		return err
+	}
+	if s.thenSchema, err = c.compileChild(obj, "then", base, ptr); err != nil {
+		return err
+	}
+	s.elseSchema, err = c.compileChild(obj, "else", base, ptr)
+	return err
+}
+
+func (c *compiler) compileChild(obj dynjson.JsonObject, keyword, base, ptr string) (*schema, error) {
+	val := obj.Value(keyword)
+	if !val.Exists() {
+		return nil, nil
+	}
+
+	return c.compile(val, base, ptr+dynjson.FormatPointer(keyword))
+}
+
+func (c *compiler) compileChildren(obj dynjson.JsonObject, keyword, base, ptr string) ([]*schema, error) {
+	val := obj.Value(keyword)
+	if !val.Exists() {
+		return nil, nil
+	}
+
+	list, ok := val.ListOk()
+	if !ok || len(list) == 0 {
+		return nil, fmt.Errorf("schema: %s#%s/%s: must be a non-empty list of schemas", base, ptr, keyword)
+	}
+
+	result := make([]*schema, len(list))
+	for i, item := range list {
+		s, err := c.compile(item.Value(), base, ptr+dynjson.FormatPointer(keyword, fmt.Sprint(i)))
+		if err != nil {
+			return nil, err
+		}
+		result[i] = s
+	}
+	return result, nil
+}
+
+func (c *compiler) intKeyword(s *schema, obj dynjson.JsonObject, keyword string) (*int, error) {
+	val := obj.Value(keyword)
+	if !val.Exists() {
+		return nil, nil
+	}
+
+	n, ok := val.Int64Ok()
+	if !ok || n < 0 {
+		return nil, c.errorf(s, keyword, "must be a non-negative integer")
+	}
+	i := int(n)
+	return &i, nil
+}
+
+func (c *compiler) errorf(s *schema, keyword, format string, args ...interface{}) error {
+	location := s.location
+	if keyword != "" {
+		location += dynjson.FormatPointer(keyword)
+	}
+	return fmt.Errorf("schema: %s: %s", location, fmt.Sprintf(format, args...))
+}
+
+var validTypes = map[string]bool{
+	"null":    true,
+	"boolean": true,
+	"object":  true,
+	"array":   true,
+	"number":  true,
+	"integer": true,
+	"string":  true,
+}
+
+func stripFragment(uri string) string {
+	if i := strings.IndexByte(uri, '#'); i >= 0 {
+		return uri[:i]
+	}
+	return uri
+}
+
+func sortedKeys(obj dynjson.JsonObject) []string {
+	keys := make([]string, 0, len(obj))
+	for key := range obj {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,190 @@
+package schema_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/go-schild/dynjson"
+	"github.com/go-schild/dynjson/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+func mustParse(t *testing.T, s string) dynjson.JsonObject {
+	j, err := dynjson.ParseObject(s)
+	assert.Nil(t, err)
+	return j
+}
+
+func violations(t *testing.T, s *schema.Schema, doc string) []schema.Violation {
+	v, err := dynjson.Parse([]byte(doc))
+	assert.Nil(t, err)
+
+	err = s.Validate(v)
+	if err == nil {
+		return nil
+	}
+	var vErr *schema.ValidationError
+	assert.True(t, errors.As(err, &vErr))
+	return vErr.Violations
+}
+
+func TestSchema_Validate(t *testing.T) {
+	s, err := schema.Compile(mustParse(t, `{
+		"type": "object",
+		"required": ["name", "age"],
+		"properties": {
+			"name": {"type": "string", "minLength": 2, "maxLength": 5, "pattern": "^[A-Z]"},
+			"age": {"type": "integer", "minimum": 0, "exclusiveMaximum": 150},
+			"email": {"type": "string", "format": "email"},
+			"tags": {"type": "array", "items": {"type": "string"}, "maxItems": 2, "uniqueItems": true},
+			"kind": {"enum": ["a", "b", 1]},
+			"version": {"const": 2}
+		}
+	}`))
+	assert.Nil(t, err)
+
+	assert.Nil(t, violations(t, s, `{"name": "Bob", "age": 42, "email": "bob@example.com", "tags": ["x"], "kind": 1, "version": 2.0}`))
+
+	result := violations(t, s, `{"name": "b", "email": "nope", "tags": ["x", "x", 3], "kind": "c", "version": 3}`)
+	assert.Equal(t, []schema.Violation{
+		{InstanceLocation: "", KeywordLocation: "#/required", Message: `missing required property "age"`},
+		{InstanceLocation: "/email", KeywordLocation: "#/properties/email/format", Message: "string is not a valid email"},
+		{InstanceLocation: "/kind", KeywordLocation: "#/properties/kind/enum", Message: "value is not one of the allowed values"},
+		{InstanceLocation: "/name", KeywordLocation: "#/properties/name/minLength", Message: "string must have at least 2 characters, got 1"},
+		{InstanceLocation: "/name", KeywordLocation: "#/properties/name/pattern", Message: `string doesn't match "^[A-Z]"`},
+		{InstanceLocation: "/tags", KeywordLocation: "#/properties/tags/maxItems", Message: "list must have at most 2 items, got 3"},
+		{InstanceLocation: "/tags", KeywordLocation: "#/properties/tags/uniqueItems", Message: "items 0 and 1 are equal"},
+		{InstanceLocation: "/tags/2", KeywordLocation: "#/properties/tags/items/type", Message: "expected string, got integer"},
+		{InstanceLocation: "/version", KeywordLocation: "#/properties/version/const", Message: "value must be 2"},
+	}, result)
+
+	result = violations(t, s, `{"name": "Bob", "age": 150.5}`)
+	assert.Len(t, result, 2)
+	assert.Equal(t, "/age", result[0].InstanceLocation)
+
+	assert.Len(t, violations(t, s, `[]`), 1)
+}
+
+func TestSchema_Ref(t *testing.T) {
+	s, err := schema.Compile(mustParse(t, `{
+		"$defs": {
+			"node": {
+				"type": "object",
+				"properties": {"value": {"type": "number"}, "children": {"type": "array", "items": {"$ref": "#/$defs/node"}}}
+			}
+		},
+		"$ref": "#/$defs/node"
+	}`))
+	assert.Nil(t, err)
+
+	assert.Nil(t, violations(t, s, `{"value": 1, "children": [{"value": 2, "children": []}]}`))
+
+	result := violations(t, s, `{"value": 1, "children": [{"value": "x"}]}`)
+	assert.Equal(t, []schema.Violation{
+		{InstanceLocation: "/children/0/value", KeywordLocation: "#/$defs/node/properties/value/type", Message: "expected number, got string"},
+	}, result)
+
+	_, err = schema.Compile(mustParse(t, `{"$ref": "#/$defs/missing"}`))
+	assert.NotNil(t, err)
+}
+
+func TestRegistry(t *testing.T) {
+	r := schema.NewRegistry()
+	assert.Nil(t, r.Add("https://example.com/address.json", mustParse(t, `{
+		"type": "object", "required": ["street"], "properties": {"zip": {"$ref": "#/$defs/zip"}},
+		"$defs": {"zip": {"type": "string", "pattern": "^[0-9]{5}$"}}
+	}`)))
+	assert.Nil(t, r.Add("https://example.com/person.json", mustParse(t, `{
+		"properties": {"address": {"$ref": "address.json"}, "zip": {"$ref": "address.json#/$defs/zip"}}
+	}`)))
+
+	s, err := r.Compile("https://example.com/person.json")
+	assert.Nil(t, err)
+
+	result := violations(t, s, `{"address": {"zip": "123"}, "zip": "12345"}`)
+	assert.Equal(t, []schema.Violation{
+		{InstanceLocation: "/address", KeywordLocation: "https://example.com/address.json#/required", Message: `missing required property "street"`},
+		{InstanceLocation: "/address/zip", KeywordLocation: "https://example.com/address.json#/$defs/zip/pattern", Message: `string doesn't match "^[0-9]{5}$"`},
+	}, result)
+
+	assert.NotNil(t, r.Add("https://example.com/person.json", dynjson.JsonObject{}))
+
+	_, err = r.Compile("https://example.com/unknown.json")
+	assert.NotNil(t, err)
+}
+
+func TestSchema_Combinators(t *testing.T) {
+	s := schema.MustCompile(mustParse(t, `{
+		"properties": {
+			"all": {"allOf": [{"type": "number"}, {"minimum": 5}]},
+			"any": {"anyOf": [{"type": "string"}, {"type": "null"}]},
+			"one": {"oneOf": [{"type": "number"}, {"type": "integer"}]},
+			"not": {"not": {"type": "string"}},
+			"cond": {
+				"if": {"properties": {"kind": {"const": "circle"}}},
+				"then": {"required": ["radius"]},
+				"else": {"required": ["width"]}
+			},
+			"never": false,
+			"always": true
+		}
+	}`))
+
+	assert.True(t, s.IsValid(dynjson.JsonObject{"all": 6, "any": nil, "one": 1.5, "not": 1, "always": 1}))
+	assert.True(t, s.IsValid(dynjson.JsonObject{"cond": dynjson.JsonObject{"kind": "circle", "radius": 1}}))
+	assert.True(t, s.IsValid(dynjson.JsonObject{"cond": dynjson.JsonObject{"kind": "square", "width": 1}}))
+
+	assert.False(t, s.IsValid(dynjson.JsonObject{"all": 4}))
+	assert.False(t, s.IsValid(dynjson.JsonObject{"any": 1}))
+	assert.False(t, s.IsValid(dynjson.JsonObject{"one": 1}))
+	assert.False(t, s.IsValid(dynjson.JsonObject{"not": "x"}))
+	assert.False(t, s.IsValid(dynjson.JsonObject{"cond": dynjson.JsonObject{"kind": "circle", "width": 1}}))
+	assert.False(t, s.IsValid(dynjson.JsonObject{"cond": dynjson.JsonObject{"kind": "square"}}))
+	assert.False(t, s.IsValid(dynjson.JsonObject{"never": 1}))
+}
+
+func TestSchema_Formats(t *testing.T) {
+	tests := []struct {
+		format string
+		valid  string
+		wrong  string
+	}{
+		{"date-time", "2020-01-02T03:04:05Z", "2020-01-02 03:04:05"},
+		{"date", "2020-01-02", "2020-13-02"},
+		{"time", "03:04:05+01:00", "3pm"},
+		{"email", "a@example.com", "a.example.com"},
+		{"hostname", "www.example.com", "-example.com"},
+		{"ipv4", "127.0.0.1", "::1"},
+		{"ipv6", "::1", "127.0.0.1"},
+		{"uri", "https://example.com/a", "/a"},
+		{"uuid", "123e4567-e89b-12d3-a456-426614174000", "123e4567"},
+		{"unknown", "anything", ""},
+	}
+
+	for _, test := range tests {
+		s := schema.MustCompile(dynjson.JsonObject{"format": test.format})
+		assert.True(t, s.IsValid(test.valid), test.format)
+		if test.wrong != "" {
+			assert.False(t, s.IsValid(test.wrong), test.format)
+		}
+		// Formats only apply to strings.
+		assert.True(t, s.IsValid(42), test.format)
+	}
+}
+
+func TestCompile_Errors(t *testing.T) {
+	for _, s := range []string{
+		`{"type": "text"}`,
+		`{"minLength": -1}`,
+		`{"pattern": "("}`,
+		`{"properties": []}`,
+		`{"allOf": []}`,
+		`{"multipleOf": 0}`,
+		`{"$defs": {"a": 5}}`,
+	} {
+		_, err := schema.Compile(mustParse(t, s))
+		assert.NotNil(t, err, s)
+	}
+
+	assert.Panics(t, func() { schema.MustCompile(dynjson.JsonObject{"type": 5}) })
+}
@@ -0,0 +1,341 @@
+package schema
+
+import (
+	"fmt"
+	"math"
+	"net"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/go-schild/dynjson"
+)
+
+// Violation describes a part of a document, which doesn't match the schema.
+type Violation struct {
+	// InstanceLocation is the JSON pointer of the invalid value inside the document, e.g. "/items/0/name".
+	InstanceLocation string
+	// KeywordLocation is the location of the failed keyword inside the schema, e.g. "#/properties/name/type".
+	KeywordLocation string
+	// Message describes the violation.
+	Message string
+}
+
+func (v Violation) String() string {
+	return fmt.Sprintf("%q: %s (%s)", v.InstanceLocation, v.Message, v.KeywordLocation)
+}
+
+// ValidationError is returned by Validate, when the document doesn't match the schema.
+type ValidationError struct {
+	Violations []Violation
+}
+
+func (e *ValidationError) Error() string {
+	messages := make([]string, len(e.Violations))
+	for i, v := range e.Violations {
+		messages[i] = v.String()
+	}
+	return "schema: document is invalid: " + strings.Join(messages, "; ")
+}
+
+// Validate validates a document, which can be a JsonObject, a JsonList, a Value or any other json value.
+// It returns a *ValidationError with all violations or nil, when the document is valid.
+func (s *Schema) Validate(doc interface{}) error {
+	violations := s.root.validate(dynjson.NewValue(doc), "")
+	if len(violations) == 0 {
+		return nil
+	}
+	return &ValidationError{Violations: violations}
+}
+
+// IsValid reports whether the document matches the schema.
+func (s *Schema) IsValid(doc interface{}) bool {
+	return s.Validate(doc) == nil
+}
+
+func (s *schema) violation(instance, keyword, format string, args ...interface{}) Violation {
+	return Violation{
+		InstanceLocation: instance,
+		KeywordLocation:  s.location + dynjson.FormatPointer(keyword),
+		Message:          fmt.Sprintf(format, args...),
+	}
+}
+
+func (s *schema) validate(v dynjson.Value, path string) []Violation {
+	if s.always != nil {
+		if *s.always {
+			return nil
+		}
+		return []Violation{{InstanceLocation: path, KeywordLocation: s.location, Message: "no value is allowed"}}
+	}
+
+	var result []Violation
+
+	if len(s.types) > 0 && !matchesType(v, s.types) {
+		result = append(result, s.violation(path, "type", "expected %s, got %s", strings.Join(s.types, " or "), typeName(v)))
+	}
+	if s.hasEnum && !containsValue(s.enum, v) {
+		result = append(result, s.violation(path, "enum", "value is not one of the allowed values"))
+	}
+	if s.constVal.Exists() && !dynjson.Equal(s.constVal, v) {
+		result = append(result, s.violation(path, "const", "value must be %s", s.constVal.ToString()))
+	}
+
+	if obj, ok := v.ObjectOk(); ok {
+		result = append(result, s.validateObject(obj, path)...)
+	}
+	if list, ok := v.ListOk(); ok {
+		result = append(result, s.validateList(list, path)...)
+	}
+	if f, ok := v.Float64Ok(); ok {
+		result = append(result, s.validateNumber(f, path)...)
+	}
+	if str, ok := v.StringOk(); ok {
+		result = append(result, s.validateString(str, path)...)
+	}
+
+	return append(result, s.validateApplicators(v, path)...)
+}
+
+func (s *schema) validateObject(obj dynjson.JsonObject, path string) []Violation {
+	var result []Violation
+
+	for _, name := range s.required {
+		if !obj.Has(name) {
+			result = append(result, s.violation(path, "required", "missing required property %q", name))
+		}
+	}
+
+	for _, name := range sortedKeys(obj) {
+		childPath := path + dynjson.FormatPointer(name)
+		if child, ok := s.properties[name]; ok {
+			result = append(result, child.validate(obj.Value(name), childPath)...)
+		} else if s.additionalProperties != nil {
+			result = append(result, s.additionalProperties.validate(obj.Value(name), childPath)...)
+		}
+	}
+
+	return result
+}
+
+func (s *schema) validateList(list dynjson.JsonList, path string) []Violation {
+	var result []Violation
+
+	if s.minItems != nil && len(list) < *s.minItems {
+		result = append(result, s.violation(path, "minItems", "list must have at least %d items, got %d", *s.minItems, len(list)))
+	}
+	if s.maxItems != nil && len(list) > *s.maxItems {
+		result = append(result, s.violation(path, "maxItems", "list must have at most %d items, got %d", *s.maxItems, len(list)))
+	}
+	if s.uniqueItems {
+	unique:
+		for i := range list {
+			for k := 0; k < i; k++ {
+				if dynjson.Equal(list[i].Value(), list[k].Value()) {
+					result = append(result, s.violation(path, "uniqueItems", "items %d and %d are equal", k, i))
+					break unique
+				}
+			}
+		}
+	}
+
+	for i, item := range list {
+		childPath := path + "/" + strconv.Itoa(i)
+		if i < len(s.prefixItems) {
+			result = append(result, s.prefixItems[i].validate(item.Value(), childPath)...)
+		} else if s.items != nil {
+			result = append(result, s.items.validate(item.Value(), childPath)...)
+		}
+	}
+
+	return result
+}
+
+func (s *schema) validateNumber(f float64, path string) []Violation {
+	var result []Violation
+
+	if s.minimum != nil && f < *s.minimum {
+		result = append(result, s.violation(path, "minimum", "value must be >= %v", *s.minimum))
+	}
+	if s.maximum != nil && f > *s.maximum {
+		result = append(result, s.violation(path, "maximum", "value must be <= %v", *s.maximum))
+	}
+	if s.exclusiveMinimum != nil && f <= *s.exclusiveMinimum {
+		result = append(result, s.violation(path, "exclusiveMinimum", "value must be > %v", *s.exclusiveMinimum))
+	}
+	if s.exclusiveMaximum != nil && f >= *s.exclusiveMaximum {
+		result = append(result, s.violation(path, "exclusiveMaximum", "value must be < %v", *s.exclusiveMaximum))
+	}
+	if s.multipleOf != nil {
+		q := f / *s.multipleOf
+		if math.IsInf(q, 0) || math.Abs(q-math.Round(q)) > 1e-9 {
+			result = append(result, s.violation(path, "multipleOf", "value must be a multiple of %v", *s.multipleOf))
+		}
+	}
+
+	return result
+}
+
+func (s *schema) validateString(str, path string) []Violation {
+	var result []Violation
+
+	length := utf8.RuneCountInString(str)
+	if s.minLength != nil && length < *s.minLength {
+		result = append(result, s.violation(path, "minLength", "string must have at least %d characters, got %d", *s.minLength, length))
+	}
+	if s.maxLength != nil && length > *s.maxLength {
+		result = append(result, s.violation(path, "maxLength", "string must have at most %d characters, got %d", *s.maxLength, length))
+	}
+	if s.pattern != nil && !s.pattern.MatchString(str) {
+		result = append(result, s.violation(path, "pattern", "string doesn't match %q", s.pattern))
+	}
+	if check, ok := formats[s.format]; ok && !check(str) {
+		result = append(result, s.violation(path, "format", "string is not a valid %s", s.format))
+	}
+
+	return result
+}
+
+func (s *schema) validateApplicators(v dynjson.Value, path string) []Violation {
+	var result []Violation
+
+	if s.ref != nil {
+		result = append(result, s.ref.validate(v, path)...)
+	}
+
+	for _, child := range s.allOf {
+		result = append(result, child.validate(v, path)...)
+	}
+
+	if len(s.anyOf) > 0 {
+		var all []Violation
+		matched := false
+		for _, child := range s.anyOf {
+			violations := child.validate(v, path)
+			if len(violations) == 0 {
+				matched = true
+				break
+			}
+			all = append(all, violations...)
+		}
+		if !matched {
+			result = append(result, s.violation(path, "anyOf", "value doesn't match any schema"))
+			result = append(result, all...)
+		}
+	}
+
+	if len(s.oneOf) > 0 {
+		var matches []int
+		var all []Violation
+		for i, child := range s.oneOf {
+			violations := child.validate(v, path)
+			if len(violations) == 0 {
+				matches = append(matches, i)
+			}
+			all = append(all, violations...)
+		}
+		switch {
+		case len(matches) == 0:
+			result = append(result, s.violation(path, "oneOf", "value doesn't match any schema"))
+			result = append(result, all...)
+		case len(matches) > 1:
+			result = append(result, s.violation(path, "oneOf", "value matches schemas %d and %d, but only one is allowed", matches[0], matches[1]))
+		}
+	}
+
+	if s.not != nil && len(s.not.validate(v, path)) == 0 {
+		result = append(result, s.violation(path, "not", "value must not match the schema"))
+	}
+
+	if s.ifSchema != nil {
+		if len(s.ifSchema.validate(v, path)) == 0 {
+			if s.thenSchema != nil {
+				result = append(result, s.thenSchema.validate(v, path)...)
+			}
+		} else if s.elseSchema != nil {
+			result = append(result, s.elseSchema.validate(v, path)...)
+		}
+	}
+
+	return result
+}
+
+// typeName returns the JSON Schema type of a value. Numbers without fractional digits are integers.
+func typeName(v dynjson.Value) string {
+	switch v.Kind() {
+	case dynjson.KindBool:
+		return "boolean"
+	case dynjson.KindNumber:
+		if f, ok := v.Float64Ok(); ok && f == math.Trunc(f) {
+			return "integer"
+		}
+		return "number"
+	}
+	return v.Kind().String()
+}
+
+func matchesType(v dynjson.Value, types []string) bool {
+	actual := typeName(v)
+	for _, t := range types {
+		if t == actual || (t == "number" && actual == "integer") {
+			return true
+		}
+	}
+	return false
+}
+
+func containsValue(values []dynjson.Value, v dynjson.Value) bool {
+	for _, candidate := range values {
+		if dynjson.Equal(candidate, v) {
+			return true
+		}
+	}
+	return false
+}
+
+var (
+	uuidPattern     = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+	hostnamePattern = regexp.MustCompile(`^(?i)[a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?(\.[a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?)*$`)
+)
+
+// formats contains the checks for the supported values of the "format" keyword. Other formats are ignored.
+var formats = map[string]func(string) bool{
+	"date-time": func(s string) bool {
+		_, err := time.Parse(time.RFC3339Nano, s)
+		return err == nil
+	},
+	"date": func(s string) bool {
+		_, err := time.Parse("2006-01-02", s)
+		return err == nil
+	},
+	"time": func(s string) bool {
+		_, err := time.Parse("15:04:05Z07:00", s)
+		if err != nil {
+			_, err = time.Parse("15:04:05.999999999Z07:00", s)
+		}
+		return err == nil
+	},
+	"email": func(s string) bool {
+		addr, err := mail.ParseAddress(s)
+		return err == nil && addr.Address == s
+	},
+	"hostname": func(s string) bool {
+		return len(s) <= 253 && hostnamePattern.MatchString(s)
+	},
+	"ipv4": func(s string) bool {
+		ip := net.ParseIP(s)
+		return ip != nil && ip.To4() != nil && !strings.Contains(s, ":")
+	},
+	"ipv6": func(s string) bool {
+		return net.ParseIP(s) != nil && strings.Contains(s, ":")
+	},
+	"uri": func(s string) bool {
+		u, err := url.Parse(s)
+		return err == nil && u.IsAbs()
+	},
+	"uuid": uuidPattern.MatchString,
+}
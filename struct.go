@@ -0,0 +1,642 @@
+package dynjson
+
+import (
+	"encoding"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+var (
+	jsonObjectType      = reflect.TypeOf(JsonObject(nil))
+	jsonListType        = reflect.TypeOf(JsonList(nil))
+	jsonListItemType    = reflect.TypeOf(JsonListItem{})
+	valueType           = reflect.TypeOf(Value{})
+	orderedObjectType   = reflect.TypeOf((*OrderedObject)(nil))
+	numberType          = reflect.TypeOf(json.Number(""))
+	marshalerType       = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	unmarshalerType     = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+	textMarshalerType   = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// Decode stores the json object in the struct or map v points to, like json.Unmarshal would do.
+// It respects json struct tags, embedded structs, pointers and json.Unmarshaler, but works on the tree directly
+// instead of marshalling it first. Errors are returned as *Error with the path of the value which couldn't be decoded.
+func (j JsonObject) Decode(v interface{}) error {
+	return decodeValue(j, v)
+}
+
+// Decode stores the json list in the slice or array v points to. See JsonObject.Decode.
+func (j JsonList) Decode(v interface{}) error {
+	return decodeValue(j, v)
+}
+
+// Decode stores the item in the Go value v points to. See JsonObject.Decode.
+func (j JsonListItem) Decode(v interface{}) error {
+	return decodeValue(j.data, v)
+}
+
+// Decode stores the value in the Go value v points to. See JsonObject.Decode.
+func (v Value) Decode(target interface{}) error {
+	if !v.exists {
+		return missingError()
+	}
+	return decodeValue(v.data, target)
+}
+
+// FromStruct converts a struct or a map into a json object, like json.Marshal would do.
+// It respects json struct tags, embedded structs, omitempty, pointers and json.Marshaler.
+// Integers are stored as int64 or uint64, so they don't lose precision.
+func FromStruct(v interface{}) (JsonObject, error) {
+	node, err := encodeNode(reflect.ValueOf(v), "")
+	if err != nil {
+		return nil, err
+	}
+
+	obj, ok := convToObject(node)
+	if !ok {
+		return nil, typeError(KindObject, node)
+	}
+	return obj, nil
+}
+
+func decodeValue(node interface{}, v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return &json.InvalidUnmarshalError{Type: reflect.TypeOf(v)}
+	}
+	return decodeNode(node, rv.Elem(), "")
+}
+
+func decodeNode(node interface{}, rv reflect.Value, path string) error {
+	switch rv.Type() {
+	case jsonObjectType:
+		if node == nil {
+			rv.Set(reflect.Zero(rv.Type()))
+			return nil
+		}
+		obj, ok := convToObject(node)
+		if !ok {
+			return decodeMismatch(node, KindObject, rv.Type(), path)
+		}
+		rv.Set(reflect.ValueOf(cloneNode(obj)))
+		return nil
+	case jsonListType:
+		if node == nil {
+			rv.Set(reflect.Zero(rv.Type()))
+			return nil
+		}
+		list, ok := convToList(node)
+		if !ok {
+			return decodeMismatch(node, KindArray, rv.Type(), path)
+		}
+		rv.Set(reflect.ValueOf(cloneNode(list)))
+		return nil
+	case jsonListItemType:
+		rv.Set(reflect.ValueOf(JsonListItem{data: cloneNode(node)}))
+		return nil
+	case valueType:
+		rv.Set(reflect.ValueOf(Value{data: cloneNode(node), exists: true}))
+		return nil
+	}
+
+	if rv.Kind() == reflect.Ptr {
+		if node == nil {
+			rv.Set(reflect.Zero(rv.Type()))
+			return nil
+		}
+		if rv.IsNil() {
+			rv.Set(reflect.New(rv.Type().Elem()))
+		}
+		return decodeNode(node, rv.Elem(), path)
+	}
+
+	if rv.CanAddr() {
+		addr := rv.Addr()
+		if addr.Type().Implements(unmarshalerType) {
+			data, err := json.Marshal(node)
+			if err != nil {
+				return err
+			}
+			return withPath(addr.Interface().(json.Unmarshaler).UnmarshalJSON(data), path)
+		}
+		if addr.Type().Implements(textUnmarshalerType) && node != nil {
+			s, ok := node.(string)
+			if !ok {
+				return decodeMismatch(node, KindString, rv.Type(), path)
+			}
+			return withPath(addr.Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(s)), path)
+		}
+	}
+
+	if node == nil {
+		// Like encoding/json, null only changes values which can be nil.
+		switch rv.Kind() {
+		case reflect.Interface, reflect.Map, reflect.Slice:
+			rv.Set(reflect.Zero(rv.Type()))
+		}
+		return nil
+	}
+
+	switch rv.Kind() {
+	case reflect.Interface:
+		if rv.NumMethod() != 0 {
+			return decodeUnsupported(rv.Type(), path)
+		}
+		rv.Set(reflect.ValueOf(plainNode(node)))
+	case reflect.Struct:
+		return decodeStruct(node, rv, path)
+	case reflect.Map:
+		return decodeMap(node, rv, path)
+	case reflect.Slice:
+		if s, ok := node.(string); ok && rv.Type().Elem().Kind() == reflect.Uint8 {
+			data, err := base64.StdEncoding.DecodeString(s)
+			if err != nil {
+				return &Error{Kind: ErrorKindTypeMismatch, Path: path, Reason: "invalid base64 data", Err: err}
+			}
+			rv.SetBytes(data)
+			return nil
+		}
+		return decodeList(node, rv, path)
+	case reflect.Array:
+		return decodeList(node, rv, path)
+	case reflect.String:
+		if rv.Type() == numberType && kindOf(node) == KindNumber {
+			s, _, _ := coerceString(node)
+			rv.SetString(s)
+			return nil
+		}
+		s, ok := node.(string)
+		if !ok {
+			return decodeMismatch(node, KindString, rv.Type(), path)
+		}
+		rv.SetString(s)
+	case reflect.Bool:
+		b, ok := node.(bool)
+		if !ok {
+			return decodeMismatch(node, KindBool, rv.Type(), path)
+		}
+		rv.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, ok := convToInt64(node)
+		if !ok || rv.OverflowInt(n) {
+			return decodeMismatch(node, KindNumber, rv.Type(), path)
+		}
+		rv.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		n, ok := convToUint64(node)
+		if !ok || rv.OverflowUint(n) {
+			return decodeMismatch(node, KindNumber, rv.Type(), path)
+		}
+		rv.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, ok := convToFloat64(node)
+		if !ok || rv.OverflowFloat(f) {
+			return decodeMismatch(node, KindNumber, rv.Type(), path)
+		}
+		rv.SetFloat(f)
+	default:
+		return decodeUnsupported(rv.Type(), path)
+	}
+
+	return nil
+}
+
+func decodeStruct(node interface{}, rv reflect.Value, path string) error {
+	obj, ok := convToObject(node)
+	if !ok {
+		return decodeMismatch(node, KindObject, rv.Type(), path)
+	}
+
+	fields := cachedFields(rv.Type())
+	for _, key := range objectKeys(obj) {
+		field := fields.lookup(key)
+		if field == nil {
+			continue
+		}
+
+		childPath := path + "/" + EscapePointerSegment(key)
+		fv, err := fieldByIndexAlloc(rv, field.index, childPath)
+		if err != nil {
+			return err
+		}
+		if err := decodeNode(obj[key], fv, childPath); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func decodeMap(node interface{}, rv reflect.Value, path string) error {
+	obj, ok := convToObject(node)
+	if !ok {
+		return decodeMismatch(node, KindObject, rv.Type(), path)
+	}
+
+	t := rv.Type()
+	if rv.IsNil() {
+		rv.Set(reflect.MakeMapWithSize(t, len(obj)))
+	}
+
+	for _, key := range objectKeys(obj) {
+		childPath := path + "/" + EscapePointerSegment(key)
+
+		kv, err := decodeMapKey(key, t.Key(), childPath)
+		if err != nil {
+			return err
+		}
+
+		ev := reflect.New(t.Elem()).Elem()
+		if err := decodeNode(obj[key], ev, childPath); err != nil {
+			return err
+		}
+		rv.SetMapIndex(kv, ev)
+	}
+
+	return nil
+}
+
+func decodeMapKey(key string, t reflect.Type, path string) (reflect.Value, error) {
+	if reflect.PtrTo(t).Implements(textUnmarshalerType) {
+		kv := reflect.New(t)
+		if err := kv.Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(key)); err != nil {
+			return reflect.Value{}, withPath(err, path)
+		}
+		return kv.Elem(), nil
+	}
+
+	kv := reflect.New(t).Elem()
+	switch t.Kind() {
+	case reflect.String:
+		kv.SetString(key)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(key, 10, 64)
+		if err != nil || kv.OverflowInt(n) {
+			return reflect.Value{}, rangeErrorAt(path, fmt.Sprintf("key %q doesn't fit into %s", key, t))
+		}
+		kv.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		n, err := strconv.ParseUint(key, 10, 64)
+		if err != nil || kv.OverflowUint(n) {
+			return reflect.Value{}, rangeErrorAt(path, fmt.Sprintf("key %q doesn't fit into %s", key, t))
+		}
+		kv.SetUint(n)
+	default:
+		return reflect.Value{}, decodeUnsupported(t, path)
+	}
+
+	return kv, nil
+}
+
+func decodeList(node interface{}, rv reflect.Value, path string) error {
+	n, ok := listLen(node)
+	if !ok {
+		return decodeMismatch(node, KindArray, rv.Type(), path)
+	}
+
+	if rv.Kind() == reflect.Slice {
+		rv.Set(reflect.MakeSlice(rv.Type(), n, n))
+	}
+
+	for i := 0; i < rv.Len(); i++ {
+		if i >= n {
+			// Like encoding/json, additional elements of arrays are zeroed.
+			rv.Index(i).Set(reflect.Zero(rv.Type().Elem()))
+			continue
+		}
+		if err := decodeNode(listGet(node, i), rv.Index(i), path+"/"+strconv.Itoa(i)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// decodeMismatch explains why node couldn't be decoded into a value of the type t.
+func decodeMismatch(node interface{}, expected Kind, t reflect.Type, path string) error {
+	if expected == KindNumber && kindOf(node) == KindNumber {
+		return rangeErrorAt(path, fmt.Sprintf("%s doesn't fit into %s", Value{data: node, exists: true}.ToString(), t))
+	}
+
+	err := typeError(expected, node)
+	err.Path = path
+	return err
+}
+
+func decodeUnsupported(t reflect.Type, path string) error {
+	return &Error{Kind: ErrorKindTypeMismatch, Path: path, Reason: fmt.Sprintf("unsupported Go type %s", t)}
+}
+
+func rangeErrorAt(path, reason string) error {
+	err := rangeError(reason)
+	err.Path = path
+	return err
+}
+
+// fieldByIndexAlloc returns the field of a struct, nil pointers to embedded structs are allocated on the way.
+func fieldByIndexAlloc(rv reflect.Value, index []int, path string) (reflect.Value, error) {
+	for i, x := range index {
+		if i > 0 && rv.Kind() == reflect.Ptr {
+			if rv.IsNil() {
+				if !rv.CanSet() {
+					return reflect.Value{}, &Error{Kind: ErrorKindTypeMismatch, Path: path,
+						Reason: fmt.Sprintf("can't set embedded pointer to unexported struct %s", rv.Type().Elem())}
+				}
+				rv.Set(reflect.New(rv.Type().Elem()))
+			}
+			rv = rv.Elem()
+		}
+		rv = rv.Field(x)
+	}
+	return rv, nil
+}
+
+// fieldByIndex returns the field of a struct. It fails, when a pointer to an embedded struct is nil.
+func fieldByIndex(rv reflect.Value, index []int) (reflect.Value, bool) {
+	for i, x := range index {
+		if i > 0 && rv.Kind() == reflect.Ptr {
+			if rv.IsNil() {
+				return reflect.Value{}, false
+			}
+			rv = rv.Elem()
+		}
+		rv = rv.Field(x)
+	}
+	return rv, true
+}
+
+func encodeNode(rv reflect.Value, path string) (interface{}, error) {
+	if !rv.IsValid() {
+		return nil, nil
+	}
+
+	switch rv.Type() {
+	case jsonObjectType, jsonListType:
+		if rv.IsNil() {
+			return nil, nil
+		}
+		return cloneNode(rv.Interface()), nil
+	case jsonListItemType, valueType, orderedObjectType:
+		return cloneNode(normalize(rv.Interface())), nil
+	}
+
+	if (rv.Kind() == reflect.Ptr || rv.Kind() == reflect.Interface) && rv.IsNil() {
+		return nil, nil
+	}
+
+	if rv.Kind() != reflect.Ptr && rv.CanAddr() && rv.Addr().Type().Implements(marshalerType) {
+		rv = rv.Addr()
+	}
+	if rv.Type().Implements(marshalerType) {
+		data, err := rv.Interface().(json.Marshaler).MarshalJSON()
+		if err != nil {
+			return nil, withPath(err, path)
+		}
+		var raw interface{}
+		if err := json.Unmarshal(data, &raw); err != nil {
+			return nil, err
+		}
+		return raw, nil
+	}
+
+	if rv.Kind() != reflect.Ptr && rv.CanAddr() && rv.Addr().Type().Implements(textMarshalerType) {
+		rv = rv.Addr()
+	}
+	if rv.Type().Implements(textMarshalerType) {
+		text, err := rv.Interface().(encoding.TextMarshaler).MarshalText()
+		if err != nil {
+			return nil, withPath(err, path)
+		}
+		return string(text), nil
+	}
+
+	switch rv.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		return encodeNode(rv.Elem(), path)
+	case reflect.Struct:
+		obj := JsonObject{}
+		for _, field := range cachedFields(rv.Type()).list {
+			fv, ok := fieldByIndex(rv, field.index)
+			if !ok || (field.omitEmpty && isEmptyValue(fv)) {
+				continue
+			}
+			val, err := encodeNode(fv, path+"/"+EscapePointerSegment(field.name))
+			if err != nil {
+				return nil, err
+			}
+			obj[field.name] = val
+		}
+		return obj, nil
+	case reflect.Map:
+		if rv.IsNil() {
+			return nil, nil
+		}
+		obj := make(JsonObject, rv.Len())
+		iter := rv.MapRange()
+		for iter.Next() {
+			key, err := encodeMapKey(iter.Key(), path)
+			if err != nil {
+				return nil, err
+			}
+			val, err := encodeNode(iter.Value(), path+"/"+EscapePointerSegment(key))
+			if err != nil {
+				return nil, err
+			}
+			obj[key] = val
+		}
+		return obj, nil
+	case reflect.Slice:
+		if rv.IsNil() {
+			return nil, nil
+		}
+		if rv.Type().Elem().Kind() == reflect.Uint8 {
+			return base64.StdEncoding.EncodeToString(rv.Bytes()), nil
+		}
+		return encodeList(rv, path)
+	case reflect.Array:
+		return encodeList(rv, path)
+	case reflect.String:
+		if rv.Type() == numberType {
+			return json.Number(rv.String()), nil
+		}
+		return rv.String(), nil
+	case reflect.Bool:
+		return rv.Bool(), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return rv.Int(), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return rv.Uint(), nil
+	case reflect.Float32, reflect.Float64:
+		f := rv.Float()
+		if math.IsNaN(f) || math.IsInf(f, 0) {
+			return nil, rangeErrorAt(path, fmt.Sprintf("%v can't be represented in json", f))
+		}
+		return f, nil
+	}
+
+	return nil, &Error{Kind: ErrorKindTypeMismatch, Path: path, Reason: fmt.Sprintf("unsupported Go type %s", rv.Type())}
+}
+
+func encodeMapKey(kv reflect.Value, path string) (string, error) {
+	if kv.Kind() == reflect.String {
+		return kv.String(), nil
+	}
+	if tm, ok := kv.Interface().(encoding.TextMarshaler); ok {
+		text, err := tm.MarshalText()
+		return string(text), withPath(err, path)
+	}
+
+	switch kv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(kv.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(kv.Uint(), 10), nil
+	}
+
+	return "", &Error{Kind: ErrorKindTypeMismatch, Path: path, Reason: fmt.Sprintf("unsupported map key type %s", kv.Type())}
+}
+
+func encodeList(rv reflect.Value, path string) (interface{}, error) {
+	list := make(JsonList, rv.Len())
+	for i := range list {
+		val, err := encodeNode(rv.Index(i), path+"/"+strconv.Itoa(i))
+		if err != nil {
+			return nil, err
+		}
+		list[i] = JsonListItem{data: val}
+	}
+	return list, nil
+}
+
+func isEmptyValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool:
+		return !v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int() == 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return v.Uint() == 0
+	case reflect.Float32, reflect.Float64:
+		return v.Float() == 0
+	case reflect.Interface, reflect.Ptr:
+		return v.IsNil()
+	}
+	return false
+}
+
+// structField describes a field of a struct as seen by encoding/json.
+type structField struct {
+	name      string
+	index     []int
+	omitEmpty bool
+	tagged    bool
+}
+
+type structFields struct {
+	list   []structField
+	byName map[string]*structField
+}
+
+// lookup finds the field for a json key. Like encoding/json, it prefers an exact match, but also accepts keys which
+// only differ in case.
+func (f *structFields) lookup(key string) *structField {
+	if field, ok := f.byName[key]; ok {
+		return field
+	}
+	for i := range f.list {
+		if strings.EqualFold(f.list[i].name, key) {
+			return &f.list[i]
+		}
+	}
+	return nil
+}
+
+var fieldCache sync.Map // map[reflect.Type]*structFields
+
+func cachedFields(t reflect.Type) *structFields {
+	if fields, ok := fieldCache.Load(t); ok {
+		return fields.(*structFields)
+	}
+
+	var all []structField
+	collectFields(t, nil, map[reflect.Type]bool{t: true}, &all)
+
+	// Like encoding/json, the field with the shortest index wins, when several fields have the same name.
+	// On the same depth, a tagged field wins. Otherwise, all of them are ignored.
+	fields := &structFields{byName: map[string]*structField{}}
+	for i, field := range all {
+		dominant := true
+		for k, other := range all {
+			if k == i || other.name != field.name {
+				continue
+			}
+			if len(other.index) < len(field.index) ||
+				(len(other.index) == len(field.index) && (other.tagged || !field.tagged)) {
+				dominant = false
+				break
+			}
+		}
+		if dominant {
+			fields.list = append(fields.list, field)
+		}
+	}
+	for i := range fields.list {
+		fields.byName[fields.list[i].name] = &fields.list[i]
+	}
+
+	actual, _ := fieldCache.LoadOrStore(t, fields)
+	return actual.(*structFields)
+}
+
+func collectFields(t reflect.Type, index []int, visited map[reflect.Type]bool, result *[]structField) {
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		tag := sf.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+
+		name, opts := tag, ""
+		if comma := strings.IndexByte(tag, ','); comma >= 0 {
+			name, opts = tag[:comma], tag[comma:]
+		}
+
+		fieldIndex := make([]int, len(index)+1)
+		copy(fieldIndex, index)
+		fieldIndex[len(index)] = i
+
+		ft := sf.Type
+		if sf.Anonymous {
+			if ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			if name == "" && ft.Kind() == reflect.Struct {
+				if !visited[ft] {
+					visited[ft] = true
+					collectFields(ft, fieldIndex, visited, result)
+					delete(visited, ft)
+				}
+				continue
+			}
+		}
+		if sf.PkgPath != "" {
+			// unexported
+			continue
+		}
+
+		field := structField{name: name, index: fieldIndex, omitEmpty: strings.Contains(opts, ",omitempty"), tagged: name != ""}
+		if field.name == "" {
+			field.name = sf.Name
+		}
+		*result = append(*result, field)
+	}
+}
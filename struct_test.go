@@ -0,0 +1,173 @@
+package dynjson_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-schild/dynjson"
+	"github.com/stretchr/testify/assert"
+)
+
+type testAddress struct {
+	Street string `json:"street"`
+	Zip    *int   `json:"zip,omitempty"`
+}
+
+type testBase struct {
+	ID      int64  `json:"id"`
+	Created string `json:"created,omitempty"`
+}
+
+type testUpper string
+
+func (u *testUpper) UnmarshalJSON(data []byte) error {
+	*u = testUpper(strings.ToUpper(strings.Trim(string(data), `"`)))
+	return nil
+}
+
+type testPerson struct {
+	testBase
+	Name     string             `json:"name"`
+	Age      int                `json:"age,omitempty"`
+	Address  *testAddress       `json:"address"`
+	Tags     []string           `json:"tags"`
+	Scores   map[string]int     `json:"scores,omitempty"`
+	Code     testUpper          `json:"code"`
+	Extra    interface{}        `json:"extra"`
+	Raw      dynjson.JsonObject `json:"raw"`
+	Ignored  string             `json:"-"`
+	internal string
+}
+
+func TestJsonObject_Decode(t *testing.T) {
+	j, _ := dynjson.ParseObject(`{
+		"id": 7, "name": "Bob", "age": 42, "address": {"street": "Main", "zip": 12345},
+		"tags": ["a", "b"], "scores": {"x": 1}, "code": "abc", "extra": {"l": [1]},
+		"raw": {"k": true}, "Ignored": "x", "unknown": 1
+	}`)
+
+	var p testPerson
+	assert.Nil(t, j.Decode(&p))
+	assert.Equal(t, int64(7), p.ID)
+	assert.Equal(t, "Bob", p.Name)
+	assert.Equal(t, 42, p.Age)
+	assert.Equal(t, "Main", p.Address.Street)
+	assert.Equal(t, 12345, *p.Address.Zip)
+	assert.Equal(t, []string{"a", "b"}, p.Tags)
+	assert.Equal(t, map[string]int{"x": 1}, p.Scores)
+	assert.Equal(t, testUpper("ABC"), p.Code)
+	assert.Equal(t, map[string]interface{}{"l": []interface{}{1.0}}, p.Extra)
+	assert.True(t, p.Raw.Bool("k"))
+	assert.Equal(t, "", p.Ignored)
+
+	// The decoded values don't share containers with the tree.
+	j.Object("raw").SetBool("k", false)
+	assert.True(t, p.Raw.Bool("k"))
+
+	// Keys are matched case-insensitively, like encoding/json does.
+	j, _ = dynjson.ParseObject(`{"NAME": "Alice", "address": null}`)
+	assert.Nil(t, j.Decode(&p))
+	assert.Equal(t, "Alice", p.Name)
+	assert.Nil(t, p.Address)
+}
+
+func TestJsonObject_DecodeErrors(t *testing.T) {
+	j, _ := dynjson.ParseObject(`{"name": 5}`)
+	var p testPerson
+
+	err := j.Decode(&p)
+	assert.True(t, errors.Is(err, dynjson.ErrTypeMismatch))
+	assert.Equal(t, `dynjson: "/name": expected string, got number`, err.Error())
+
+	j, _ = dynjson.ParseObject(`{"address": {"zip": 1.5}}`)
+	err = j.Decode(&p)
+	assert.True(t, errors.Is(err, dynjson.ErrOutOfRange))
+	var dErr *dynjson.Error
+	assert.True(t, errors.As(err, &dErr))
+	assert.Equal(t, "/address/zip", dErr.Path)
+
+	var small struct {
+		A int8 `json:"a"`
+	}
+	j, _ = dynjson.ParseObject(`{"a": 300}`)
+	assert.True(t, errors.Is(j.Decode(&small), dynjson.ErrOutOfRange))
+
+	assert.NotNil(t, j.Decode(small))
+	assert.NotNil(t, j.Decode(nil))
+}
+
+func TestJsonListItem_Decode(t *testing.T) {
+	list, _ := dynjson.ParseList(`[{"street": "Main"}, [1, 2, 3], "x"]`)
+
+	var addr testAddress
+	assert.Nil(t, list[0].Decode(&addr))
+	assert.Equal(t, "Main", addr.Street)
+	assert.Nil(t, addr.Zip)
+
+	var arr [2]int
+	assert.Nil(t, list[1].Decode(&arr))
+	assert.Equal(t, [2]int{1, 2}, arr)
+
+	var s *string
+	assert.Nil(t, list[2].Decode(&s))
+	assert.Equal(t, "x", *s)
+
+	var nums []float64
+	assert.Nil(t, list.Value(1).Decode(&nums))
+	assert.Equal(t, []float64{1, 2, 3}, nums)
+	assert.True(t, errors.Is(list.Value(5).Decode(&nums), dynjson.ErrMissing))
+}
+
+func TestFromStruct(t *testing.T) {
+	zip := 12345
+	p := testPerson{
+		testBase: testBase{ID: 9007199254740993},
+		Name:     "Bob",
+		Address:  &testAddress{Street: "Main", Zip: &zip},
+		Tags:     []string{"a"},
+		Code:     "X",
+		Ignored:  "x",
+	}
+
+	j, err := dynjson.FromStruct(&p)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(9007199254740993), j.Int64("id"))
+	assert.Equal(t, "Bob", j.String("name"))
+	assert.False(t, j.Has("age"))
+	assert.False(t, j.Has("created"))
+	assert.False(t, j.Has("scores"))
+	assert.False(t, j.Has("Ignored"))
+	assert.True(t, j.IsNull("raw"))
+	assert.Equal(t, 12345, j.Object("address").Int("zip"))
+	assert.Equal(t, "a", j.List("tags")[0].String())
+	assert.Equal(t, `{"address":{"street":"Main","zip":12345},"code":"X","extra":null,"id":9007199254740993,"name":"Bob","raw":null,"tags":["a"]}`, j.ToString())
+
+	var back testPerson
+	assert.Nil(t, j.Decode(&back))
+	assert.Equal(t, p.ID, back.ID)
+	assert.Equal(t, p.Address, back.Address)
+}
+
+func TestFromStruct_Marshaler(t *testing.T) {
+	ts := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	j, err := dynjson.FromStruct(map[string]interface{}{"t": ts, "b": []byte("hi"), "n": 1})
+	assert.Nil(t, err)
+	assert.Equal(t, "2020-01-02T03:04:05Z", j.String("t"))
+	assert.Equal(t, "aGk=", j.String("b"))
+
+	var back struct {
+		T time.Time `json:"t"`
+		B []byte    `json:"b"`
+	}
+	assert.Nil(t, j.Decode(&back))
+	assert.True(t, ts.Equal(back.T))
+	assert.Equal(t, []byte("hi"), back.B)
+
+	_, err = dynjson.FromStruct("abc")
+	assert.True(t, errors.Is(err, dynjson.ErrTypeMismatch))
+
+	_, err = dynjson.FromStruct(map[string]interface{}{"c": make(chan int)})
+	assert.True(t, errors.Is(err, dynjson.ErrTypeMismatch))
+}
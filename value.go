@@ -0,0 +1,374 @@
+package dynjson
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+)
+
+// Kind describes the JSON type of a Value.
+type Kind int
+
+const (
+	// KindMissing is the kind of a value which doesn't exist, e.g. a field which is not part of an object.
+	KindMissing Kind = iota
+	KindNull
+	KindObject
+	KindArray
+	KindString
+	KindNumber
+	KindBool
+)
+
+var kindNames = map[Kind]string{
+	KindMissing: "missing",
+	KindNull:    "null",
+	KindObject:  "object",
+	KindArray:   "array",
+	KindString:  "string",
+	KindNumber:  "number",
+	KindBool:    "bool",
+}
+
+// String returns the name of the kind, e.g. "object".
+func (k Kind) String() string {
+	if name, ok := kindNames[k]; ok {
+		return name
+	}
+	return "unknown"
+}
+
+// Value wraps an arbitrary JSON value: an object, an array, a string, a number, a boolean or null.
+// The zero Value is missing, which is what you get when asking for a field an object doesn't have.
+type Value struct {
+	data   interface{}
+	exists bool
+}
+
+// NewValue wraps data into a Value.
+// Go numbers like int or float32 are converted to int64 or float64, just like in JsonList.Append.
+func NewValue(data interface{}) Value {
+	return Value{data: normalize(data), exists: true}
+}
+
+// Parse parses any JSON document, including scalar roots like `"abc"`, `42` or `null`.
+func Parse(data []byte, opts ...ParseOption) (Value, error) {
+	raw, err := parseBytes(data, newParseConfig(opts))
+	if err != nil {
+		return Value{}, err
+	}
+
+	return Value{data: raw, exists: true}, nil
+}
+
+// MarshalJSON implements json.Marshaler. A missing value is encoded as null.
+func (v Value) MarshalJSON() ([]byte, error) {
+	return json.Marshal(v.data)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (v *Value) UnmarshalJSON(data []byte) error {
+	var raw interface{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	*v = Value{data: raw, exists: true}
+	return nil
+}
+
+// ToString returns the JSON data as string.
+// Returns an empty string, when an error occurred. Use Marshal to get the error or to change the output.
+func (v Value) ToString() string {
+	data, _ := json.Marshal(v)
+	return string(data)
+}
+
+// Kind returns the JSON type of the value.
+func (v Value) Kind() Kind {
+	if !v.exists {
+		return KindMissing
+	}
+	return kindOf(v.data)
+}
+
+// Exists returns false, when the value is missing.
+func (v Value) Exists() bool {
+	return v.exists
+}
+
+// IsNull returns true, when the value exists and is null.
+func (v Value) IsNull() bool {
+	return v.exists && v.data == nil
+}
+
+// Interface returns the underlying Go value, e.g. a string, a float64, a JsonObject or nil.
+func (v Value) Interface() interface{} {
+	return v.data
+}
+
+func (v Value) ObjectOk() (JsonObject, bool) {
+	return convToObject(v.data)
+}
+
+func (v Value) Object() JsonObject {
+	val, _ := v.ObjectOk()
+	return val
+}
+
+func (v Value) ListOk() (JsonList, bool) {
+	return convToList(v.data)
+}
+
+func (v Value) List() JsonList {
+	val, _ := v.ListOk()
+	return val
+}
+
+func (v Value) StringOk() (string, bool) {
+	return convToString(v.data)
+}
+
+func (v Value) StringDefault(def string) string {
+	val, ok := v.StringOk()
+	if ok {
+		return val
+	}
+	return def
+}
+
+func (v Value) String() string {
+	val, _ := v.StringOk()
+	return val
+}
+
+func (v Value) Float64Ok() (float64, bool) {
+	return convToFloat64(v.data)
+}
+
+func (v Value) Float64Default(def float64) float64 {
+	val, ok := v.Float64Ok()
+	if ok {
+		return val
+	}
+	return def
+}
+
+func (v Value) Float64() float64 {
+	val, _ := v.Float64Ok()
+	return val
+}
+
+func (v Value) Float32Ok() (float32, bool) {
+	val, ok := v.Float64Ok()
+	return float32(val), ok
+}
+
+func (v Value) Float32Default(def float32) float32 {
+	val, ok := v.Float32Ok()
+	if ok {
+		return val
+	}
+	return def
+}
+
+func (v Value) Float32() float32 {
+	val, _ := v.Float32Ok()
+	return val
+}
+
+// IntOk returns the number converted to int. Fractional digits are truncated.
+// Use Int64Ok, when fractional numbers should be rejected.
+// IntOk returns the value as int. Like Int64Ok, it fails on fractional numbers and on numbers which don't fit
+// into an int.
+func (v Value) IntOk() (int, bool) {
+	val, ok := v.Int64Ok()
+	if !ok || int64(int(val)) != val {
+		return 0, false
+	}
+	return int(val), true
+}
+
+func (v Value) IntDefault(def int) int {
+	val, ok := v.IntOk()
+	if ok {
+		return val
+	}
+	return def
+}
+
+func (v Value) Int() int {
+	val, _ := v.IntOk()
+	return val
+}
+
+// Int64Ok returns the number converted to int64.
+// In contrast to IntOk, it fails on fractional numbers and on numbers which don't fit into an int64.
+func (v Value) Int64Ok() (int64, bool) {
+	return convToInt64(v.data)
+}
+
+func (v Value) Int64Default(def int64) int64 {
+	val, ok := v.Int64Ok()
+	if ok {
+		return val
+	}
+	return def
+}
+
+func (v Value) Int64() int64 {
+	val, _ := v.Int64Ok()
+	return val
+}
+
+// Int32Ok returns the number converted to int32.
+// It fails on fractional numbers and on numbers which don't fit into an int32.
+func (v Value) Int32Ok() (int32, bool) {
+	val, ok := v.Int64Ok()
+	if !ok || val < math.MinInt32 || val > math.MaxInt32 {
+		return 0, false
+	}
+	return int32(val), true
+}
+
+func (v Value) Int32Default(def int32) int32 {
+	val, ok := v.Int32Ok()
+	if ok {
+		return val
+	}
+	return def
+}
+
+func (v Value) Int32() int32 {
+	val, _ := v.Int32Ok()
+	return val
+}
+
+// Uint64Ok returns the number converted to uint64.
+// It fails on fractional numbers, negative numbers and on numbers which don't fit into an uint64.
+func (v Value) Uint64Ok() (uint64, bool) {
+	return convToUint64(v.data)
+}
+
+func (v Value) Uint64Default(def uint64) uint64 {
+	val, ok := v.Uint64Ok()
+	if ok {
+		return val
+	}
+	return def
+}
+
+func (v Value) Uint64() uint64 {
+	val, _ := v.Uint64Ok()
+	return val
+}
+
+func (v Value) BoolOk() (bool, bool) {
+	return convToBool(v.data)
+}
+
+func (v Value) BoolDefault(def bool) bool {
+	val, ok := v.BoolOk()
+	if ok {
+		return val
+	}
+	return def
+}
+
+func (v Value) Bool() bool {
+	val, _ := v.BoolOk()
+	return val
+}
+
+// kindError explains why the value couldn't be converted: it is missing, it has another kind or it is a number,
+// which doesn't fit into the requested Go type.
+func (v Value) kindError(goType string, expected Kind) *Error {
+	if !v.exists {
+		return missingError()
+	}
+	if expected == KindNumber && kindOf(v.data) == KindNumber {
+		return rangeError(fmt.Sprintf("%s doesn't fit into %s", v.ToString(), goType))
+	}
+	return typeError(expected, v.data)
+}
+
+// ObjectErr works like ObjectOk, but returns an *Error, which tells why the value couldn't be read.
+func (v Value) ObjectErr() (JsonObject, error) {
+	val, ok := v.ObjectOk()
+	if !ok {
+		return nil, v.kindError("JsonObject", KindObject)
+	}
+	return val, nil
+}
+
+func (v Value) ListErr() (JsonList, error) {
+	val, ok := v.ListOk()
+	if !ok {
+		return nil, v.kindError("JsonList", KindArray)
+	}
+	return val, nil
+}
+
+func (v Value) StringErr() (string, error) {
+	val, ok := v.StringOk()
+	if !ok {
+		return "", v.kindError("string", KindString)
+	}
+	return val, nil
+}
+
+func (v Value) Float64Err() (float64, error) {
+	val, ok := v.Float64Ok()
+	if !ok {
+		return 0, v.kindError("float64", KindNumber)
+	}
+	return val, nil
+}
+
+func (v Value) Float32Err() (float32, error) {
+	val, ok := v.Float32Ok()
+	if !ok {
+		return 0, v.kindError("float32", KindNumber)
+	}
+	return val, nil
+}
+
+func (v Value) IntErr() (int, error) {
+	val, ok := v.IntOk()
+	if !ok {
+		return 0, v.kindError("int", KindNumber)
+	}
+	return val, nil
+}
+
+func (v Value) Int64Err() (int64, error) {
+	val, ok := v.Int64Ok()
+	if !ok {
+		return 0, v.kindError("int64", KindNumber)
+	}
+	return val, nil
+}
+
+func (v Value) Int32Err() (int32, error) {
+	val, ok := v.Int32Ok()
+	if !ok {
+		return 0, v.kindError("int32", KindNumber)
+	}
+	return val, nil
+}
+
+func (v Value) Uint64Err() (uint64, error) {
+	val, ok := v.Uint64Ok()
+	if !ok {
+		return 0, v.kindError("uint64", KindNumber)
+	}
+	return val, nil
+}
+
+func (v Value) BoolErr() (bool, error) {
+	val, ok := v.BoolOk()
+	if !ok {
+		return false, v.kindError("bool", KindBool)
+	}
+	return val, nil
+}
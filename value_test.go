@@ -0,0 +1,168 @@
+package dynjson_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/go-schild/dynjson"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	testData := map[string]dynjson.Kind{
+		`{"a": 1}`: dynjson.KindObject,
+		`[1, 2]`:   dynjson.KindArray,
+		`"abc"`:    dynjson.KindString,
+		`42`:       dynjson.KindNumber,
+		`true`:     dynjson.KindBool,
+		`null`:     dynjson.KindNull,
+	}
+
+	for data, kind := range testData {
+		v, err := dynjson.Parse([]byte(data))
+		assert.Nil(t, err)
+		assert.Equal(t, kind, v.Kind(), data)
+		assert.JSONEq(t, data, v.ToString())
+	}
+
+	_, err := dynjson.Parse([]byte(`{"a": `))
+	assert.NotNil(t, err)
+
+	_, err = dynjson.Parse([]byte(``))
+	assert.NotNil(t, err)
+}
+
+func TestNewValue(t *testing.T) {
+	assert.Equal(t, dynjson.KindNumber, dynjson.NewValue(5).Kind())
+	assert.Equal(t, int64(5), dynjson.NewValue(5).Interface())
+	assert.Equal(t, int64(9007199254740993), dynjson.NewValue(9007199254740993).Int64())
+	assert.Equal(t, dynjson.KindNull, dynjson.NewValue(nil).Kind())
+	assert.Equal(t, dynjson.KindObject, dynjson.NewValue(dynjson.NewJsonObject()).Kind())
+	assert.Equal(t, dynjson.KindArray, dynjson.NewValue(dynjson.NewJsonList(nil)).Kind())
+}
+
+func TestKind_String(t *testing.T) {
+	assert.Equal(t, "missing", dynjson.KindMissing.String())
+	assert.Equal(t, "object", dynjson.KindObject.String())
+	assert.Equal(t, "array", dynjson.KindArray.String())
+}
+
+func TestValue_Kind(t *testing.T) {
+	const testData = `{"a": {}, "b": [], "c": "Hello", "d": 1.5, "e": false, "f": null}`
+
+	j, err := dynjson.ParseObject(testData)
+	assert.Nil(t, err)
+
+	assert.Equal(t, dynjson.KindObject, j.Value("a").Kind())
+	assert.Equal(t, dynjson.KindArray, j.Value("b").Kind())
+	assert.Equal(t, dynjson.KindString, j.Value("c").Kind())
+	assert.Equal(t, dynjson.KindNumber, j.Value("d").Kind())
+	assert.Equal(t, dynjson.KindBool, j.Value("e").Kind())
+	assert.Equal(t, dynjson.KindNull, j.Value("f").Kind())
+	assert.Equal(t, dynjson.KindMissing, j.Value("g").Kind())
+
+	assert.True(t, j.Value("f").Exists())
+	assert.False(t, j.Value("g").Exists())
+	assert.True(t, j.Value("f").IsNull())
+	assert.False(t, j.Value("g").IsNull())
+}
+
+func TestValue_Getters(t *testing.T) {
+	v, err := dynjson.Parse([]byte(`"Hello"`))
+	assert.Nil(t, err)
+
+	s, ok := v.StringOk()
+	assert.True(t, ok)
+	assert.Equal(t, "Hello", s)
+
+	_, ok = v.Float64Ok()
+	assert.False(t, ok)
+	assert.Equal(t, -1, v.IntDefault(-1))
+
+	v, err = dynjson.Parse([]byte(`3.5`))
+	assert.Nil(t, err)
+
+	assert.Equal(t, float64(3.5), v.Float64())
+	assert.Equal(t, float32(3.5), v.Float32())
+	assert.Equal(t, 0, v.Int())
+	assert.Equal(t, "default", v.StringDefault("default"))
+
+	v, err = dynjson.Parse([]byte(`true`))
+	assert.Nil(t, err)
+
+	b, ok := v.BoolOk()
+	assert.True(t, ok)
+	assert.True(t, b)
+
+	v, err = dynjson.Parse([]byte(`{"a": [1, 2]}`))
+	assert.Nil(t, err)
+
+	assert.Equal(t, 2, v.Object().List("a")[1].Int())
+	assert.Nil(t, v.List())
+}
+
+func TestValue_MarshalJSON(t *testing.T) {
+	type container struct {
+		A dynjson.Value `json:"a"`
+		B dynjson.Value `json:"b"`
+	}
+
+	var c container
+	err := json.Unmarshal([]byte(`{"a": [1, {"b": null}], "b": "Hello"}`), &c)
+	assert.Nil(t, err)
+
+	assert.Equal(t, dynjson.KindArray, c.A.Kind())
+	assert.Equal(t, dynjson.KindString, c.B.Kind())
+
+	b, err := json.Marshal(c)
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{"a": [1, {"b": null}], "b": "Hello"}`, string(b))
+}
+
+func TestJsonListItem_Value(t *testing.T) {
+	j, err := dynjson.ParseList(`[{"a": 1}, null, "Hello"]`)
+	assert.Nil(t, err)
+
+	assert.Equal(t, dynjson.KindObject, j[0].Value().Kind())
+	assert.Equal(t, dynjson.KindNull, j[1].Value().Kind())
+	assert.Equal(t, "Hello", j[2].Value().String())
+}
+
+func TestValue_IntOk(t *testing.T) {
+	v, err := dynjson.Parse([]byte(`9007199254740993`), dynjson.UseNumber())
+	assert.Nil(t, err)
+	assert.Equal(t, int64(9007199254740993), v.Int64())
+
+	v, err = dynjson.Parse([]byte(`3.9`))
+	assert.Nil(t, err)
+
+	_, ok := v.IntOk()
+	assert.False(t, ok)
+	_, ok = v.Int64Ok()
+	assert.False(t, ok)
+
+	v, err = dynjson.Parse([]byte(`1e30`))
+	assert.Nil(t, err)
+	_, ok = v.IntOk()
+	assert.False(t, ok)
+}
+
+func TestValue_GoNumbers(t *testing.T) {
+	j := dynjson.JsonObject{"int": 5, "int8": int8(-3), "uint32": uint32(7), "float32": float32(1.5)}
+
+	assert.Equal(t, int64(5), j.Int64("int"))
+	assert.Equal(t, 5.0, j.Float64("int"))
+	assert.Equal(t, int32(-3), j.Int32("int8"))
+	assert.Equal(t, uint64(7), j.Uint64("uint32"))
+	assert.Equal(t, 1.5, j.Float64("float32"))
+
+	_, ok := j.Uint64Ok("int8")
+	assert.False(t, ok)
+
+	list := dynjson.NewJsonList(nil)
+	list.Append(9007199254740993)
+	o := dynjson.NewOrderedObject()
+	o.Set("n", 9007199254740993)
+	assert.Equal(t, `[9007199254740993]`, list.ToString())
+	assert.Equal(t, `{"n":9007199254740993}`, o.ToString())
+}